@@ -1,7 +1,12 @@
 package main
 
 import (
+	"audit"
+	"context"
+	"crypto/tls"
 	"db"
+	"errors"
+	"expvar"
 	"fmt"
 	"io"
 	"log"
@@ -9,7 +14,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
-	"strings"
+	pb "services"
 	"syscall"
 	"time"
 
@@ -52,13 +57,18 @@ func initJaeger(service string) (opentracing.Tracer, io.Closer) {
 }
 
 type App struct {
-	certFile   string
-	keyFile    string
+	tlsConfig  *tls.Config
+	gatewayTLS *tls.Config
+	audit      *audit.Recorder
 	db         *db.PrismaClient
 	grpcServer *grpc.Server
 	logger     *zap.SugaredLogger
 	gwmux      *runtime.ServeMux
 	graphqlmux *GraphqlServeMux
+	limiter    *middlewares.RateLimiter
+	ipFilter   *middlewares.IPFilter
+	// trustedProxies are the proxies whose headers name the client of HTTP requests.
+	trustedProxies []string
 }
 
 func NewApp() (*App, error) {
@@ -71,57 +81,199 @@ func NewApp() (*App, error) {
 	certFile := "../../certs/server.crt"
 	keyFile := "../../certs/server.key"
 	sugar := logger.Sugar()
-	creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+	// With TLS_CLIENT_CA_FILE set, both listeners require a client certificate signed by
+	// one of its CAs.
+	clientCAFile := os.Getenv("TLS_CLIENT_CA_FILE")
+	tlsConfig, err := ServerTLSConfig(certFile, keyFile, clientCAFile)
 	if err != nil {
 		sugar.Fatalf("Failed to load TLS credentials: %v", err)
 		return nil, err
 	}
+	creds := credentials.NewTLS(tlsConfig)
 
-	// Initialize rate limiter with default trusted proxies
-	trustedProxies := middlewares.DefaultTrustedProxies()
+	// The gateways dial the gRPC listener, so they need a client certificate as well; by
+	// default they present the server certificate.
+	var gatewayCertFile, gatewayKeyFile string
+	if clientCAFile != "" {
+		gatewayCertFile, gatewayKeyFile = certFile, keyFile
+		if os.Getenv("TLS_GATEWAY_CERT_FILE") != "" {
+			gatewayCertFile, gatewayKeyFile = os.Getenv("TLS_GATEWAY_CERT_FILE"), os.Getenv("TLS_GATEWAY_KEY_FILE")
+		}
+	}
+	gatewayTLS, err := ClientTLSConfig(certFile, "localhost", gatewayCertFile, gatewayKeyFile)
+	if err != nil {
+		sugar.Fatalf("Failed to load gateway TLS credentials: %v", err)
+		return nil, err
+	}
+
+	// Load asymmetric JWT signing keys; without them tokens are signed with JWT_SECRET.
+	keySet, err := pb.LoadKeySetFromEnv()
+	if err != nil {
+		sugar.Fatalf("Failed to load JWT signing keys: %v", err)
+		return nil, err
+	}
+	pb.SetKeySet(keySet)
+
+	client := db.NewClient()
+	// Revoked tokens are checked by the auth interceptors on every request.
+	revocations, err := pb.LoadRevocationStoreFromEnv(client)
+	if err != nil {
+		sugar.Fatalf("Failed to configure token revocation: %v", err)
+		return nil, err
+	}
+	pb.SetRevocationStore(revocations)
+
+	loginAttempts, err := pb.LoadLoginAttemptStoreFromEnv(client)
+	if err != nil {
+		sugar.Fatalf("Failed to configure login attempt tracking: %v", err)
+		return nil, err
+	}
+	pb.SetLoginAttemptStore(loginAttempts)
+	pb.SetAPIKeyVerifier(pb.NewPrismaAPIKeyVerifier(client))
+
+	passwordHasher, err := pb.LoadPasswordHasherFromEnv()
+	if err != nil {
+		sugar.Fatalf("Failed to configure password hashing: %v", err)
+		return nil, err
+	}
+	pb.SetPasswordHasher(passwordHasher)
+	passwordPolicy, err := pb.LoadPasswordPolicyFromEnv()
+	if err != nil {
+		sugar.Fatalf("Failed to configure password policy: %v", err)
+		return nil, err
+	}
+	pb.SetPasswordPolicy(passwordPolicy)
+
+	certificateAuth, err := pb.LoadCertificateAuthenticatorFromEnv()
+	if err == nil && certificateAuth != nil && clientCAFile == "" {
+		err = fmt.Errorf("CLIENT_CERT_RULES_FILE requires TLS_CLIENT_CA_FILE")
+	}
+	if err != nil {
+		sugar.Fatalf("Failed to configure client certificate authentication: %v", err)
+		return nil, err
+	}
+	if certificateAuth != nil {
+		// Requests through the gateways carry the gateway certificate; their callers must
+		// bring their own credentials.
+		for _, cert := range gatewayTLS.Certificates {
+			certificateAuth.Exclude(cert.Certificate[0])
+		}
+	}
+	pb.SetCertificateAuthenticator(certificateAuth)
+
+	// Audit events are written in the background; Run flushes them on shutdown.
+	auditRecorder, err := audit.LoadRecorderFromEnv(client, audit.WithErrorHandler(func(err error, events []audit.Event) {
+		sugar.Errorw("Failed to write audit events", "count", len(events), "error", err)
+	}))
+	if err != nil {
+		sugar.Fatalf("Failed to configure audit log: %v", err)
+		return nil, err
+	}
+	audit.SetRecorder(auditRecorder)
+
+	mailer, err := pb.LoadMailerFromEnv()
+	if err != nil {
+		sugar.Fatalf("Failed to configure mailer: %v", err)
+		return nil, err
+	}
+	pb.SetMailer(mailer)
+
+	oauthProviders, err := pb.LoadOAuthProvidersFromEnv()
+	if err != nil {
+		sugar.Fatalf("Failed to configure OAuth providers: %v", err)
+		return nil, err
+	}
+	pb.SetOAuthProviders(oauthProviders...)
+
+	webAuthn, err := pb.LoadWebAuthnFromEnv()
+	if err != nil {
+		sugar.Fatalf("Failed to configure passkeys: %v", err)
+		return nil, err
+	}
+	pb.SetWebAuthn(webAuthn)
+
+	// The gateway reaches the gRPC server over the loopback address, which should stay
+	// among the trusted proxies so that its requests are attributed to their clients.
+	trustedProxies, err := middlewares.TrustedProxiesFromEnv()
+	if err != nil {
+		sugar.Fatalf("Failed to configure trusted proxies: %v", err)
+		return nil, err
+	}
+	ipFilter, err := middlewares.LoadIPFilterFromEnv(trustedProxies)
+	if err != nil {
+		sugar.Fatalf("Failed to configure IP filtering: %v", err)
+		return nil, err
+	}
 	sugar.Infof("Initializing rate limiter with trusted proxies: %v", trustedProxies)
-	rateLimiter := middlewares.NewRateLimiter(5, 10, trustedProxies)
+	rateLimiter, err := middlewares.LoadRateLimiterFromEnv(trustedProxies,
+		middlewares.WithStoreErrorHandler(func(err error) {
+			// Requests are limited in memory until the store is retried.
+			if !errors.Is(err, middlewares.ErrLimiterStoreUnavailable) {
+				sugar.Warnw("Rate limit store failed, limiting locally", "error", err)
+			}
+		}),
+	)
+	if err != nil {
+		sugar.Fatalf("Failed to configure rate limiting: %v", err)
+		return nil, err
+	}
+	// The limiter counts are published through expvar for metrics exporters.
+	expvar.Publish("rate_limiter", expvar.Func(func() any { return rateLimiter.Stats() }))
 
-	// Create the gRPC server with TLS and middleware.
+	// Create the gRPC server with TLS and middleware. The client address is resolved
+	// before authentication, so that it is audited, and filtered before authentication
+	// too, as are failed authentications. Requests are limited after it, so that policies
+	// can count them per user or API key.
 	grpcServer := grpc.NewServer(
 		grpc.Creds(creds),
 		grpc.UnaryInterceptor(
 			middlewares.ChainUnaryInterceptors(
-				rateLimiter.RateLimiterInterceptor,
+				rateLimiter.ClientIPInterceptor,
+				ipFilter.IPFilterInterceptor,
+				rateLimiter.AuthFailureInterceptor,
 				middlewares.AuthUnaryInterceptor,
+				rateLimiter.RateLimiterInterceptor,
+			),
+		),
+		grpc.StreamInterceptor(
+			middlewares.ChainStreamInterceptors(
+				rateLimiter.ClientIPStreamInterceptor,
+				ipFilter.IPFilterStreamInterceptor,
+				rateLimiter.AuthFailureStreamInterceptor,
+				middlewares.AuthStreamInterceptor,
+				rateLimiter.RateLimiterStreamInterceptor,
 			),
 		),
-		grpc.StreamInterceptor(middlewares.AuthStreamInterceptor),
 	)
 
-	headerMatcher := func(key string) (string, bool) {
-		key = strings.ToLower(key)
-		if key == "authorization" {
-			return "authorization", true // Return lowercase for consistency
-		}
-		return runtime.DefaultHeaderMatcher(key)
-	}
 	// For gRPC gateway
 	gwmux := runtime.NewServeMux(
-		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithIncomingHeaderMatcher(middlewares.GatewayHeaderMatcher),
 	)
 
 	gwmuxGraphql := NewGraphqlServeMux()
-	gwmuxGraphql.SetIncomingHeaderMatcher(headerMatcher)
+	gwmuxGraphql.SetIncomingHeaderMatcher(middlewares.GatewayHeaderMatcher)
 	return &App{
-		certFile:   certFile,
-		keyFile:    keyFile,
-		db:         db.NewClient(),
+		tlsConfig:  tlsConfig,
+		gatewayTLS: gatewayTLS,
+		audit:      auditRecorder,
+		db:         client,
 		grpcServer: grpcServer,
 		logger:     sugar,
 		gwmux:      gwmux,
 		graphqlmux: gwmuxGraphql,
+		limiter:    rateLimiter,
+		ipFilter:   ipFilter,
+
+		trustedProxies: trustedProxies,
 	}, nil
 }
 
 func (app *App) RegisterMux() fasthttp.RequestHandler {
-	// fasthttp handler
-	fasthttpHandler := fasthttpadaptor.NewFastHTTPHandler(wsproxy.WebsocketProxy(app.gwmux))
+	// fasthttp handler. REST and GraphQL requests are rate limited by the address of the
+	// remote client here, as the gRPC server only sees the gateway.
+	fasthttpHandler := app.limiter.RateLimitMiddleware(fasthttpadaptor.NewFastHTTPHandler(wsproxy.WebsocketProxy(app.gwmux)))
+	graphqlHandler := app.limiter.RateLimitMiddleware(middlewares.HeaderForwarderMiddleware(fasthttpadaptor.NewFastHTTPHandler(app.graphqlmux)))
 
 	// Define FastHTTP handlers.
 	healthCheckHandler := func(ctx *fasthttp.RequestCtx) {
@@ -134,20 +286,36 @@ func (app *App) RegisterMux() fasthttp.RequestHandler {
 		ctx.SetBody([]byte("Ready"))
 	}
 
-	// Create a FastHTTP router.
-	fastMux := middlewares.CORSMiddleware(middlewares.LoggingMiddleware(func(ctx *fasthttp.RequestCtx) {
+	// Publishes the public keys used to verify tokens.
+	jwksHandler := func(ctx *fasthttp.RequestCtx) {
+		body, err := pb.JWKS()
+		if err != nil {
+			app.logger.Errorf("Failed to build JWKS: %v", err)
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			return
+		}
+		ctx.SetContentType("application/json")
+		ctx.Response.Header.Set("Cache-Control", "public, max-age=300")
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBody(body)
+	}
+
+	// Create a FastHTTP router. Paths refused by the IP filter are still logged. The proxy
+	// headers are reduced to the resolved client before the gateways forward them.
+	fastMux := middlewares.CORSMiddleware(middlewares.LoggingMiddleware(app.ipFilter.IPFilterMiddleware(middlewares.ForwardedForMiddleware(app.trustedProxies, func(ctx *fasthttp.RequestCtx) {
 		switch string(ctx.Path()) {
 		case "/health":
 			healthCheckHandler(ctx)
 		case "/ready":
 			readyCheckHandler(ctx)
+		case "/.well-known/jwks.json":
+			jwksHandler(ctx)
 		case "/graphql":
-			graphqlHandler := middlewares.HeaderForwarderMiddleware(fasthttpadaptor.NewFastHTTPHandler(app.graphqlmux))
 			graphqlHandler(ctx)
 		default:
 			fasthttpHandler(ctx) // Pass other requests to gRPC-Gateway
 		}
-	}))
+	}))))
 	return fastMux
 }
 
@@ -178,10 +346,7 @@ func (app *App) Run() error {
 		}
 	}()
 
-	clientCreds, err := credentials.NewClientTLSFromFile(app.certFile, "localhost")
-	if err != nil {
-		app.logger.Fatalf("Failed to load client TLS credentials: %v", err)
-	}
+	clientCreds := credentials.NewTLS(app.gatewayTLS)
 	conn, err := grpc.Dial("localhost"+grpcPort, grpc.WithTransportCredentials(clientCreds))
 	if err != nil {
 		log.Fatalln("Failed to dial gRPC server:", err)
@@ -202,12 +367,13 @@ func (app *App) Run() error {
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
 		Logger:       &SilentLogger{}, // Use a silent logger to suppress output
+		TLSConfig:    app.tlsConfig.Clone(),
 	}
 	log.Println("\033[32m✓ Server is running!\033[0m")
 
 	// Run FastHTTP server in a separate goroutine.
 	go func() {
-		if err := httpServer.ListenAndServeTLS(httpPort, app.certFile, app.keyFile); err != nil {
+		if err := httpServer.ListenAndServeTLS(httpPort, "", ""); err != nil {
 			app.logger.Errorf("FastHTTP server stopped: %v", err)
 		}
 	}()
@@ -227,6 +393,14 @@ func (app *App) Run() error {
 	} else {
 		app.logger.Info("FastHTTP server gracefully stopped.")
 	}
+	app.limiter.Close()
+	if app.audit != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := app.audit.Close(ctx); err != nil {
+			app.logger.Errorf("Error flushing audit events: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -127,7 +127,7 @@ case "$1" in
         ;;
     test)
         echo "Running tests..."
-        go test -v ./pkg/db ./pkg/middlewares/ ./pkg/services/ ./pkg/services/generated
+        go test -v ./pkg/audit ./pkg/db ./pkg/middlewares/ ./pkg/services/ ./pkg/services/generated
         exit 0
         ;;
     serve)
@@ -0,0 +1,283 @@
+// Package audit records security events, such as logins and token revocations, in a
+// store that outlives the service logs. Events are written in the background and in
+// batches, so that recording them does not slow requests down.
+package audit
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Outcome is the result of an audited action.
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// Actions recorded by the auth service.
+const (
+	ActionLogin          = "login"
+	ActionRegister       = "register"
+	ActionPasswordChange = "password_change"
+	ActionPasswordReset  = "password_reset"
+	ActionTokenRevoke    = "token_revoke"
+	ActionUserDisable    = "user_disable"
+	ActionUserEnable     = "user_enable"
+	ActionRolesChange    = "roles_change"
+	ActionImpersonate    = "impersonate"
+)
+
+// Event is a security relevant action. Fields of the request that are left empty are
+// taken from the context by Record.
+type Event struct {
+	Time    time.Time
+	Action  string
+	Outcome Outcome
+	// ActorID and ActorEmail identify who acted. Unauthenticated actions, such as a failed
+	// login, only know the email that was tried.
+	ActorID    string
+	ActorEmail string
+	// TargetID is the user the action was applied to, when it is not the actor.
+	TargetID  string
+	TenantID  string
+	IP        string
+	UserAgent string
+	RequestID string
+	// Reason details the outcome, such as why an action failed.
+	Reason string
+}
+
+// Request describes the request an event happened in. The auth interceptors store it in
+// the context.
+type Request struct {
+	ActorID    string
+	ActorEmail string
+	TenantID   string
+	IP         string
+	UserAgent  string
+	RequestID  string
+}
+
+type requestKey struct{}
+
+// WithRequest returns a context carrying the request that events recorded with it
+// belong to.
+func WithRequest(ctx context.Context, r Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+// RequestFromContext returns the request stored by WithRequest.
+func RequestFromContext(ctx context.Context) (Request, bool) {
+	r, ok := ctx.Value(requestKey{}).(Request)
+	return r, ok
+}
+
+// fill sets the fields of the event that were left empty from the request.
+func (e *Event) fill(r Request) {
+	setDefault := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	// The actor of the request is only the actor of the event when none was given, so
+	// that an email tried in a failed login is not paired with another user's ID.
+	if e.ActorID == "" && e.ActorEmail == "" {
+		e.ActorID, e.ActorEmail = r.ActorID, r.ActorEmail
+	}
+	setDefault(&e.TenantID, r.TenantID)
+	setDefault(&e.IP, r.IP)
+	setDefault(&e.UserAgent, r.UserAgent)
+	setDefault(&e.RequestID, r.RequestID)
+}
+
+// Store persists events.
+type Store interface {
+	Write(ctx context.Context, events []Event) error
+}
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	defaultBufferSize    = 10000
+)
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithBatchSize sets the most events written at once.
+func WithBatchSize(n int) Option {
+	return func(r *Recorder) {
+		r.batchSize = n
+	}
+}
+
+// WithFlushInterval sets how long events wait for a batch to fill up.
+func WithFlushInterval(d time.Duration) Option {
+	return func(r *Recorder) {
+		r.flushInterval = d
+	}
+}
+
+// WithBufferSize sets how many events may wait to be written. Events recorded while the
+// buffer is full are dropped.
+func WithBufferSize(n int) Option {
+	return func(r *Recorder) {
+		r.bufferSize = n
+	}
+}
+
+// WithErrorHandler sets the function called with events that could not be written or
+// were dropped. By default they are logged.
+func WithErrorHandler(fn func(err error, events []Event)) Option {
+	return func(r *Recorder) {
+		r.onError = fn
+	}
+}
+
+// Recorder writes events to a store in the background.
+type Recorder struct {
+	store         Store
+	batchSize     int
+	flushInterval time.Duration
+	bufferSize    int
+	onError       func(err error, events []Event)
+
+	events chan Event
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// NewRecorder starts a recorder writing to store. Close it to write the events that are
+// still buffered.
+func NewRecorder(store Store, opts ...Option) *Recorder {
+	r := &Recorder{
+		store:         store,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		bufferSize:    defaultBufferSize,
+		onError: func(err error, events []Event) {
+			log.Printf("audit: %d events lost: %v", len(events), err)
+		},
+		done: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.events = make(chan Event, r.bufferSize)
+	go r.run()
+	return r
+}
+
+var (
+	// ErrBufferFull is passed to the error handler for events dropped because the store
+	// cannot keep up.
+	ErrBufferFull = errors.New("audit buffer is full")
+	// ErrClosed is passed to the error handler for events recorded after Close.
+	ErrClosed = errors.New("audit recorder is closed")
+)
+
+// Record queues an event without blocking. Its time defaults to now and the fields of
+// the request to the ones stored in ctx.
+func (r *Recorder) Record(ctx context.Context, e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if req, ok := RequestFromContext(ctx); ok {
+		e.fill(req)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		r.onError(ErrClosed, []Event{e})
+		return
+	}
+	select {
+	case r.events <- e:
+	default:
+		r.onError(ErrBufferFull, []Event{e})
+	}
+}
+
+// run writes the queued events in batches of up to batchSize, at least every
+// flushInterval.
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, r.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		// Events were accepted already, so they are written even when the request that
+		// recorded them is gone.
+		if err := r.store.Write(context.Background(), batch); err != nil {
+			r.onError(err, batch)
+		}
+		batch = make([]Event, 0, r.batchSize)
+	}
+	for {
+		select {
+		case e, ok := <-r.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= r.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Close stops accepting events and waits until the buffered ones are written, or until
+// ctx is done.
+func (r *Recorder) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var (
+	recorderMu sync.RWMutex
+	recorder   *Recorder
+)
+
+// SetRecorder replaces the recorder used by Record; nil disables auditing.
+func SetRecorder(r *Recorder) {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	recorder = r
+}
+
+// Record queues an event with the configured recorder. It does nothing when auditing is
+// disabled.
+func Record(ctx context.Context, e Event) {
+	recorderMu.RLock()
+	r := recorder
+	recorderMu.RUnlock()
+	if r != nil {
+		r.Record(ctx, e)
+	}
+}
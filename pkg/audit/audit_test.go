@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingStore holds writes until it is released.
+type blockingStore struct {
+	MemoryStore
+	release chan struct{}
+}
+
+func (b *blockingStore) Write(ctx context.Context, events []Event) error {
+	<-b.release
+	return b.MemoryStore.Write(ctx, events)
+}
+
+type failingStore struct{}
+
+func (failingStore) Write(ctx context.Context, events []Event) error {
+	return errors.New("database is down")
+}
+
+func TestRecorderBatchesAndFlushesOnClose(t *testing.T) {
+	store := NewMemoryStore()
+	r := NewRecorder(store, WithBatchSize(2), WithFlushInterval(time.Hour))
+
+	for _, action := range []string{ActionLogin, ActionRegister, ActionTokenRevoke} {
+		r.Record(context.Background(), Event{Action: action, Outcome: OutcomeSuccess})
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(store.Events()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := len(store.Events()); got != 2 {
+		t.Fatalf("Expected a full batch to be written, got %d events", got)
+	}
+
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	events := store.Events()
+	if len(events) != 3 {
+		t.Fatalf("Expected Close to write the partial batch, got %d events", len(events))
+	}
+	if events[2].Action != ActionTokenRevoke || events[2].Time.IsZero() {
+		t.Errorf("Expected events in order with their time set, got %+v", events[2])
+	}
+}
+
+func TestRecorderFlushesOnInterval(t *testing.T) {
+	store := NewMemoryStore()
+	r := NewRecorder(store, WithFlushInterval(10*time.Millisecond))
+	defer r.Close(context.Background())
+
+	r.Record(context.Background(), Event{Action: ActionLogin, Outcome: OutcomeSuccess})
+	deadline := time.Now().Add(time.Second)
+	for len(store.Events()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(store.Events()) != 1 {
+		t.Errorf("Expected the event to be written after the flush interval")
+	}
+}
+
+func TestRecordFillsRequest(t *testing.T) {
+	store := NewMemoryStore()
+	r := NewRecorder(store)
+
+	ctx := WithRequest(context.Background(), Request{
+		ActorID:    "user-1",
+		ActorEmail: "user@test.com",
+		TenantID:   "acme",
+		IP:         "203.0.113.7",
+		UserAgent:  "Mozilla/5.0",
+		RequestID:  "req-1",
+	})
+	r.Record(ctx, Event{Action: ActionPasswordChange, Outcome: OutcomeSuccess})
+	r.Record(ctx, Event{Action: ActionLogin, Outcome: OutcomeFailure, ActorEmail: "other@test.com"})
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	events := store.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	got := events[0]
+	if got.ActorID != "user-1" || got.TenantID != "acme" || got.IP != "203.0.113.7" || got.UserAgent != "Mozilla/5.0" || got.RequestID != "req-1" {
+		t.Errorf("Expected the request to be filled in, got %+v", got)
+	}
+	if got := events[1]; got.ActorID != "" || got.ActorEmail != "other@test.com" || got.IP != "203.0.113.7" {
+		t.Errorf("Expected an explicit actor to be kept, got %+v", got)
+	}
+}
+
+func TestRecorderDropsWhenFull(t *testing.T) {
+	store := &blockingStore{release: make(chan struct{})}
+	var mu sync.Mutex
+	var dropped []error
+	r := NewRecorder(store, WithBatchSize(1), WithBufferSize(1), WithErrorHandler(func(err error, events []Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped = append(dropped, err)
+	}))
+
+	// The first event is taken by the writer, the second fills the buffer.
+	r.Record(context.Background(), Event{Action: ActionLogin})
+	time.Sleep(20 * time.Millisecond)
+	r.Record(context.Background(), Event{Action: ActionLogin})
+	r.Record(context.Background(), Event{Action: ActionLogin})
+
+	close(store.release)
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	r.Record(context.Background(), Event{Action: ActionLogin})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(dropped) != 2 || !errors.Is(dropped[0], ErrBufferFull) || !errors.Is(dropped[1], ErrClosed) {
+		t.Errorf("Expected one event dropped while full and one after Close, got %v", dropped)
+	}
+	if got := len(store.Events()); got != 2 {
+		t.Errorf("Expected the accepted events to be written, got %d", got)
+	}
+}
+
+func TestRecorderReportsWriteErrors(t *testing.T) {
+	var lost int
+	r := NewRecorder(failingStore{}, WithErrorHandler(func(err error, events []Event) {
+		lost += len(events)
+	}))
+	r.Record(context.Background(), Event{Action: ActionLogin})
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if lost != 1 {
+		t.Errorf("Expected the failed batch to be reported, got %d events", lost)
+	}
+}
+
+func TestLoadRecorderFromEnv(t *testing.T) {
+	t.Setenv("AUDIT_STORE", "none")
+	r, err := LoadRecorderFromEnv(nil)
+	if err != nil || r != nil {
+		t.Errorf("Expected auditing to be disabled, got %v, %v", r, err)
+	}
+
+	t.Setenv("AUDIT_STORE", "kafka")
+	if _, err := LoadRecorderFromEnv(nil); err == nil {
+		t.Errorf("Expected an unknown store to be rejected")
+	}
+
+	t.Setenv("AUDIT_STORE", "")
+	t.Setenv("AUDIT_BATCH_SIZE", "0")
+	if _, err := LoadRecorderFromEnv(nil); err == nil {
+		t.Errorf("Expected an invalid batch size to be rejected")
+	}
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"context"
+	"db"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PrismaStore writes events to the AuditEvent table.
+type PrismaStore struct {
+	client *db.PrismaClient
+}
+
+// NewPrismaStore creates a store backed by the database.
+func NewPrismaStore(client *db.PrismaClient) *PrismaStore {
+	return &PrismaStore{client: client}
+}
+
+// Write inserts a batch in one transaction.
+func (p *PrismaStore) Write(ctx context.Context, events []Event) error {
+	txs := make([]db.PrismaTransaction, 0, len(events))
+	for _, e := range events {
+		txs = append(txs, p.client.AuditEvent.CreateOne(
+			db.AuditEvent.Action.Set(e.Action),
+			db.AuditEvent.Outcome.Set(string(e.Outcome)),
+			db.AuditEvent.CreatedAt.Set(e.Time),
+			db.AuditEvent.ActorID.Set(e.ActorID),
+			db.AuditEvent.ActorEmail.Set(e.ActorEmail),
+			db.AuditEvent.TargetID.Set(e.TargetID),
+			db.AuditEvent.TenantID.Set(e.TenantID),
+			db.AuditEvent.IP.Set(e.IP),
+			db.AuditEvent.UserAgent.Set(e.UserAgent),
+			db.AuditEvent.RequestID.Set(e.RequestID),
+			db.AuditEvent.Reason.Set(e.Reason),
+		).Tx())
+	}
+	return p.client.Prisma.Transaction(txs...).Exec(ctx)
+}
+
+// MemoryStore keeps events in process memory, for tests and development.
+type MemoryStore struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (m *MemoryStore) Write(ctx context.Context, events []Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, events...)
+	return nil
+}
+
+// Events returns the events written so far.
+func (m *MemoryStore) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Event(nil), m.events...)
+}
+
+// LoadRecorderFromEnv builds the recorder selected by AUDIT_STORE: "database" (default)
+// writes to the AuditEvent table, "none" disables auditing and returns nil. Batches are
+// tuned through AUDIT_BATCH_SIZE and AUDIT_FLUSH_INTERVAL.
+func LoadRecorderFromEnv(client *db.PrismaClient, opts ...Option) (*Recorder, error) {
+	var store Store
+	switch kind := os.Getenv("AUDIT_STORE"); kind {
+	case "", "database":
+		store = NewPrismaStore(client)
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown AUDIT_STORE %q", kind)
+	}
+
+	if v := os.Getenv("AUDIT_BATCH_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid AUDIT_BATCH_SIZE %q", v)
+		}
+		opts = append(opts, WithBatchSize(n))
+	}
+	if v := os.Getenv("AUDIT_FLUSH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid AUDIT_FLUSH_INTERVAL %q", v)
+		}
+		opts = append(opts, WithFlushInterval(d))
+	}
+	return NewRecorder(store, opts...), nil
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"net"
 	"net/http"
 	"strings"
 
@@ -29,31 +30,35 @@ func defaultHeaderMatcher(key string) (string, bool) {
 	return strings.ToLower(key), true
 }
 
+// SetIncomingHeaderMatcher selects the HTTP headers passed on to the gRPC server as
+// metadata, and their names there.
 func (c *GraphqlServeMux) SetIncomingHeaderMatcher(matcher func(string) (string, bool)) {
 	c.incomingHeaderMatcher = matcher
 }
 
-// Custom handler that intercepts the request and manually sets up metadata
+// ServeHTTP passes the headers selected by the incoming header matcher on to the gRPC
+// calls of the request. Like the gRPC gateway, it appends the remote address of the
+// request to X-Forwarded-For, so the gRPC server can tell the client from the gateway.
 func (c *GraphqlServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-
-	// Get the authorization header
-	authHeader := r.Header.Get("Authorization")
-
-	// Create a custom context with metadata
-	ctx := r.Context()
-
-	if authHeader != "" {
-		// Create metadata with the authorization header
-		md := metadata.Pairs("authorization", authHeader)
-
-		// Set both incoming and outgoing metadata
-		ctx = metadata.NewIncomingContext(ctx, md)
-		ctx = metadata.NewOutgoingContext(ctx, md)
-
-		// Update the request with the new context
-		r = r.WithContext(ctx)
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		if name, ok := c.incomingHeaderMatcher(key); ok {
+			md.Append(name, values...)
+		}
+	}
+	forwardedFor := r.Header.Values("X-Forwarded-For")
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		forwardedFor = append(forwardedFor, host)
 	}
+	if len(forwardedFor) > 0 {
+		md.Set("x-forwarded-for", strings.Join(forwardedFor, ", "))
+	}
+
+	// Set both incoming and outgoing metadata; the resolvers call the gRPC server with
+	// the context of the request.
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	ctx = metadata.NewOutgoingContext(ctx, md)
 
 	// Call the original GraphQL handler
-	c.ServeMux.ServeHTTP(w, r)
+	c.ServeMux.ServeHTTP(w, r.WithContext(ctx))
 }
@@ -1,8 +1,9 @@
 package middlewares
 
 import (
+	"audit"
 	"context"
-	"fmt"
+	"errors"
 	pb "services"
 	"strings"
 
@@ -12,54 +13,112 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// middleware verifies JWT tokens in the request context.
-// Rejects unauthorized requests with a detailed log entry.
+// AuthUnaryInterceptor enforces the (thunder.auth) rule of the called method: public
+// methods pass through, all others need a valid, unrevoked token and, when the rule lists
+// roles, one of those roles.
 func AuthUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	if info.FullMethod == "/authenticator.Auth/Login" || info.FullMethod == "/authenticator.Auth/Register" {
-		return handler(ctx, req)
-	}
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, fmt.Errorf("missing metadata")
-	}
-	token := md["authorization"]
-	if len(token) == 0 {
-		return nil, status.Errorf(codes.Unauthenticated, "missing token")
-	}
-	rawToken := strings.TrimSpace(strings.TrimPrefix(token[0], "Bearer "))
-	claims, err := pb.VerifyJWT(rawToken)
+	ctx, err := authorize(ctx, info.FullMethod)
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "unauthorized: %v", err)
+		return nil, err
 	}
-	// Set timeout for database operations to prevent hanging requests
-	md = metadata.Join(md, metadata.Pairs("current_user", claims.Email))
-	ctx = metadata.NewIncomingContext(ctx, md)
 	return handler(ctx, req)
 }
 
+// AuthStreamInterceptor is the streaming counterpart of AuthUnaryInterceptor.
 func AuthStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	md, ok := metadata.FromIncomingContext(ss.Context())
-	if !ok {
-		return status.Error(codes.Unauthenticated, "missing metadata")
+	ctx, err := authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
 	}
 
-	tokens := md["authorization"]
-	if len(tokens) == 0 {
-		return status.Error(codes.Unauthenticated, "missing token")
+	// 👇 Wrap the stream with overridden context
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize verifies the caller of fullMethod and returns the context carrying its
+// principal and tenant. Rejects unauthorized requests with Unauthenticated, and callers
+// without a required role or addressing another tenant than their own with
+// PermissionDenied.
+func authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	ctx = stripIdentityMetadata(ctx)
+	rule := pb.MethodAuthRule(fullMethod)
+	if rule.GetPublic() {
+		return scopeRequest(ctx, nil)
 	}
 
-	rawToken := strings.TrimSpace(strings.TrimPrefix(tokens[0], "Bearer "))
-	claims, err := pb.VerifyJWT(rawToken)
+	// Certificate callers need not send any metadata.
+	md, _ := metadata.FromIncomingContext(ctx)
+	principal, err := authenticate(ctx, md)
 	if err != nil {
-		return status.Errorf(codes.Unauthenticated, "unauthorized: %v", err)
+		return nil, err
+	}
+	if err := pb.Authorize(principal, rule); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "forbidden: %v", err)
+	}
+	return scopeRequest(pb.WithPrincipal(ctx, principal), principal)
+}
+
+// scopeRequest scopes the context to the tenant of the request and describes the request
+// to the audit log.
+func scopeRequest(ctx context.Context, principal *pb.Principal) (context.Context, error) {
+	tenantID, err := pb.ResolveTenant(ctx, principal)
+	if err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "forbidden: %v", err)
 	}
+	ctx = pb.WithTenantID(ctx, tenantID)
+	return audit.WithRequest(ctx, pb.AuditRequest(ctx)), nil
+}
 
-	// 👇 Set current_user from claims
-	newMD := metadata.Join(md, metadata.Pairs("current_user", claims.Email))
-	newCtx := metadata.NewIncomingContext(ss.Context(), newMD)
+// stripIdentityMetadata drops the current_user header that older versions set for
+// handlers, so that a client cannot pose as another user by sending it.
+func stripIdentityMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("current_user")) == 0 {
+		return ctx
+	}
+	md = md.Copy()
+	md.Delete("current_user")
+	return metadata.NewIncomingContext(ctx, md)
+}
 
-	// 👇 Wrap the stream with overridden context
-	return handler(srv, &wrappedStream{ServerStream: ss, ctx: newCtx})
+// authenticate resolves the caller from an "authorization: Bearer <jwt>" or an
+// "x-api-key" header, which yield the same identity for the same user, or else from the
+// client certificate of the connection when certificate rules are configured.
+func authenticate(ctx context.Context, md metadata.MD) (*pb.Principal, error) {
+	if tokens := md["authorization"]; len(tokens) > 0 {
+		rawToken := strings.TrimSpace(strings.TrimPrefix(tokens[0], "Bearer "))
+		claims, err := pb.VerifyJWT(rawToken)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "unauthorized: %v", err)
+		}
+		if err := pb.CheckRevocation(ctx, claims); err != nil {
+			return nil, revocationStatus(err)
+		}
+		return pb.NewPrincipal(claims), nil
+	}
+	if keys := md["x-api-key"]; len(keys) > 0 {
+		claims, err := pb.VerifyAPIKey(ctx, strings.TrimSpace(keys[0]))
+		if err != nil {
+			if errors.Is(err, pb.ErrInvalidAPIKey) {
+				return nil, status.Errorf(codes.Unauthenticated, "unauthorized: %v", err)
+			}
+			return nil, status.Errorf(codes.Unavailable, "could not verify API key: %v", err)
+		}
+		return pb.NewPrincipal(claims), nil
+	}
+	if principal, ok := pb.CertificatePrincipal(ctx); ok {
+		return principal, nil
+	}
+	return nil, status.Error(codes.Unauthenticated, "missing token")
+}
+
+// revocationStatus maps a CheckRevocation failure to a gRPC status. Requests are rejected
+// when the revocation store cannot be reached rather than letting revoked tokens through.
+func revocationStatus(err error) error {
+	if errors.Is(err, pb.ErrTokenRevoked) {
+		return status.Errorf(codes.Unauthenticated, "unauthorized: %v", err)
+	}
+	return status.Errorf(codes.Unavailable, "could not verify token: %v", err)
 }
 
 type wrappedStream struct {
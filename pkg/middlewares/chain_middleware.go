@@ -26,3 +26,22 @@ func ChainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.Un
 		return chainHandler(ctx, req)
 	}
 }
+
+// ChainStreamInterceptors is the streaming counterpart of ChainUnaryInterceptors.
+func ChainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chainHandler := handler
+
+		// Apply interceptors in reverse order (last one runs first)
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor := interceptors[i]
+			next := chainHandler
+			chainHandler = func(s interface{}, stream grpc.ServerStream) error {
+				return interceptor(s, stream, info, next)
+			}
+		}
+
+		// Call the first interceptor
+		return chainHandler(srv, ss)
+	}
+}
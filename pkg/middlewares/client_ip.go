@@ -0,0 +1,223 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultTrustedProxies returns a list of commonly trusted proxy IPs
+func DefaultTrustedProxies() []string {
+	return []string{"127.0.0.1", "::1"}
+}
+
+// TrustedProxiesFromEnv returns the comma-separated IP addresses and CIDR ranges in
+// TRUSTED_PROXIES, or DefaultTrustedProxies when it is unset.
+func TrustedProxiesFromEnv() ([]string, error) {
+	value := os.Getenv("TRUSTED_PROXIES")
+	if value == "" {
+		return DefaultTrustedProxies(), nil
+	}
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if _, err := parseNetwork(proxy); err != nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry: %v", err)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+// parseNetwork accepts a CIDR range such as "10.0.0.0/16", or a single IP address.
+func parseNetwork(s string) (netip.Prefix, error) {
+	if prefix, err := netip.ParsePrefix(s); err == nil {
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%q is neither an IP address nor a CIDR range", s)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// networks is a list of IP ranges.
+type networks []netip.Prefix
+
+// contains reports whether ip belongs to one of the networks.
+func (n networks) contains(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range n {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// trustedProxies are the networks whose proxy headers are believed.
+type trustedProxies networks
+
+// parseTrustedProxies accepts IP addresses and CIDR ranges such as "10.0.0.0/16".
+// Invalid entries are skipped.
+func parseTrustedProxies(proxies []string) trustedProxies {
+	var trusted trustedProxies
+	for _, proxy := range proxies {
+		if prefix, err := parseNetwork(proxy); err == nil {
+			trusted = append(trusted, prefix)
+		}
+	}
+	return trusted
+}
+
+// forwardingHeaders are the headers proxies use to pass on the client address.
+type forwardingHeaders struct {
+	// forwarded holds the values of RFC 7239 Forwarded headers.
+	forwarded []string
+	// forwardedFor holds the values of X-Forwarded-For headers.
+	forwardedFor []string
+	realIP       string
+}
+
+// grpcClientIP resolves the client address of a gRPC request.
+func (t trustedProxies) grpcClientIP(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", status.Errorf(codes.Internal, "could not determine peer")
+	}
+
+	peerIP, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "invalid peer address: %v", err)
+	}
+
+	var headers forwardingHeaders
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		headers.forwarded = md.Get("forwarded")
+		headers.forwardedFor = md.Get("x-forwarded-for")
+		if xri := md.Get("x-real-ip"); len(xri) > 0 {
+			headers.realIP = xri[0]
+		}
+	}
+	return t.resolve(peerIP, headers), nil
+}
+
+// httpClientIP resolves the client address of an HTTP request.
+func (t trustedProxies) httpClientIP(ctx *fasthttp.RequestCtx) string {
+	headers := forwardingHeaders{realIP: string(ctx.Request.Header.Peek("X-Real-IP"))}
+	for _, value := range ctx.Request.Header.PeekAll("Forwarded") {
+		headers.forwarded = append(headers.forwarded, string(value))
+	}
+	for _, value := range ctx.Request.Header.PeekAll("X-Forwarded-For") {
+		headers.forwardedFor = append(headers.forwardedFor, string(value))
+	}
+	return t.resolve(ctx.RemoteIP().String(), headers)
+}
+
+// ForwardedForMiddleware replaces the proxy headers of HTTP requests by an X-Forwarded-For
+// naming only the client resolved through the trusted proxies, or none when the client
+// is the remote address itself. The REST and GraphQL gateways append the remote address
+// and pass the list on to the gRPC server, which thus resolves the same client, whether
+// the proxies in front used Forwarded or X-Forwarded-For, and whatever the client sent.
+func ForwardedForMiddleware(proxies []string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	trusted := parseTrustedProxies(proxies)
+	return func(ctx *fasthttp.RequestCtx) {
+		clientIP := trusted.httpClientIP(ctx)
+		ctx.Request.Header.Del("Forwarded")
+		ctx.Request.Header.Del("X-Real-IP")
+		ctx.Request.Header.Del("X-Forwarded-For")
+		if clientIP != ctx.RemoteIP().String() {
+			ctx.Request.Header.Set("X-Forwarded-For", clientIP)
+		}
+		next(ctx)
+	}
+}
+
+// resolve returns the client address of a request from peerIP. Proxy headers are only
+// believed when the peer is a trusted proxy. The chain of addresses in Forwarded, or
+// else X-Forwarded-For, is walked from the right, nearest hop past every trusted proxy,
+// and the first untrusted address is the client. A hop that is not an IP address, such
+// as "unknown", ends the walk, as nothing to its left can be believed.
+func (t trustedProxies) resolve(peerIP string, headers forwardingHeaders) string {
+	// Only trust proxy headers if the request is from a trusted proxy
+	if !networks(t).contains(peerIP) {
+		return peerIP
+	}
+
+	var hops []string
+	switch {
+	case len(headers.forwarded) > 0:
+		hops = parseForwarded(headers.forwarded)
+	case len(headers.forwardedFor) > 0:
+		for _, value := range headers.forwardedFor {
+			hops = append(hops, strings.Split(value, ",")...)
+		}
+	default:
+		if addr, err := netip.ParseAddr(strings.TrimSpace(headers.realIP)); err == nil {
+			return addr.Unmap().String()
+		}
+		return peerIP
+	}
+
+	client := peerIP
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap().String()
+		if !networks(t).contains(client) {
+			break
+		}
+	}
+	return client
+}
+
+// parseForwarded returns the "for" addresses of RFC 7239 Forwarded header values, from
+// the first proxy to the last. Elements without one yield "".
+func parseForwarded(values []string) []string {
+	var hops []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			var hop string
+			for _, pair := range strings.Split(element, ";") {
+				name, node, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(name, "for") {
+					hop = forwardedNode(node)
+				}
+			}
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// forwardedNode returns the address of a node such as 192.0.2.60, "192.0.2.60:4711" or
+// "[2001:db8:cafe::17]:4711".
+func forwardedNode(node string) string {
+	node = strings.Trim(node, `"`)
+	if strings.HasPrefix(node, "[") {
+		if end := strings.Index(node, "]"); end > 0 {
+			return node[1:end]
+		}
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
+	}
+	return node
+}
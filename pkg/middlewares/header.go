@@ -1,8 +1,10 @@
 package middlewares
 
 import (
+	pb "services"
 	"strings"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/valyala/fasthttp"
 )
 
@@ -26,3 +28,30 @@ func HeaderForwarderMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHan
 		next(ctx)
 	}
 }
+
+// GatewayHeaderMatcher selects the HTTP headers the gateways pass on to the gRPC server as
+// metadata. The gateways reach the server from a trusted proxy, so the proxy headers that
+// clients could send as Grpc-Metadata-* are dropped: only the X-Forwarded-For the gateway
+// appends the remote address to names the client.
+func GatewayHeaderMatcher(key string) (string, bool) {
+	key = strings.ToLower(key)
+	switch key {
+	case "authorization":
+		return "authorization", true // Return lowercase for consistency
+	case "x-api-key":
+		return "x-api-key", true
+	case pb.TenantHeader:
+		return pb.TenantHeader, true
+	case pb.RequestIDHeader:
+		return pb.RequestIDHeader, true
+	}
+	name, ok := runtime.DefaultHeaderMatcher(key)
+	if !ok {
+		return "", false
+	}
+	switch strings.ToLower(name) {
+	case "forwarded", "x-forwarded-for", "x-real-ip":
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+)
+
+// RateLimitMiddleware limits REST and GraphQL requests before they reach the gateway,
+// whose connection to the gRPC server always comes from the loopback address. Requests
+// are counted by the remote address of the connection, or by the forwarded address when
+// it is a trusted proxy, in budgets of their own: policies apply when their method
+// pattern matches the path, and the default limit otherwise.
+//
+// Responses carry the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers.
+// Refused requests get a 429 with Retry-After and a JSON body shaped like the errors of
+// the gateway.
+func (r *RateLimiter) RateLimitMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		clientIP := r.trustedProxies.httpClientIP(ctx)
+		key, limit, burst := r.budget(ctx, string(ctx.Path()), clientIP)
+		result := r.take(ctx, "http|"+key, limit, burst)
+
+		ctx.Response.Header.Set("RateLimit-Limit", strconv.Itoa(burst))
+		ctx.Response.Header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Response.Header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		if !result.Allowed {
+			if result.RetryAfter != rate.InfDuration {
+				ctx.Response.Header.Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+			}
+			writeHTTPError(ctx, fasthttp.StatusTooManyRequests, codes.ResourceExhausted, "Too many requests, slow down")
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+// writeHTTPError responds with an error body shaped like the errors of the gateway.
+func writeHTTPError(ctx *fasthttp.RequestCtx, httpStatus int, code codes.Code, message string) {
+	body, _ := json.Marshal(map[string]any{
+		"code":    code,
+		"message": message,
+		"details": []any{},
+	})
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(httpStatus)
+	ctx.SetBody(body)
+}
+
+// ceilSeconds rounds d up to whole seconds, so that clients waiting that long are not
+// refused again.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
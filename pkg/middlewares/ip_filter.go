@@ -0,0 +1,151 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	pb "services"
+
+	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IPFilterRule restricts the client addresses allowed to call the methods or paths
+// matching Method.
+type IPFilterRule struct {
+	// Method is a full gRPC method name or an HTTP path, or a pattern such as
+	// "/admin.Admin/*" or "/v1/admin/*". A trailing "*" also matches further slashes.
+	Method string `json:"method"`
+	// Allow lists the IP addresses and CIDR ranges allowed; when empty, all are.
+	Allow []string `json:"allow"`
+	// Deny lists the IP addresses and CIDR ranges refused, even when allowed.
+	Deny []string `json:"deny"`
+}
+
+type ipFilterRule struct {
+	method string
+	allow  networks
+	deny   networks
+}
+
+// allows reports whether the rule lets ip through.
+func (r ipFilterRule) allows(ip string) bool {
+	if r.deny.contains(ip) {
+		return false
+	}
+	return len(r.allow) == 0 || r.allow.contains(ip)
+}
+
+// IPFilter refuses requests whose client address is not allowed by the first rule
+// matching their method or path. Requests matching no rule are let through. gRPC calls
+// are filtered by the address ClientIPInterceptor resolved, HTTP requests by the one
+// resolved through the trusted proxies of the filter.
+type IPFilter struct {
+	rules          []ipFilterRule
+	trustedProxies trustedProxies
+}
+
+// NewIPFilter returns a filter applying rules in order, that believes the proxy headers
+// of the trusted proxies, given as IP addresses or CIDR ranges.
+func NewIPFilter(proxies []string, rules ...IPFilterRule) (*IPFilter, error) {
+	filter := &IPFilter{trustedProxies: parseTrustedProxies(proxies)}
+	for _, rule := range rules {
+		if rule.Method == "" {
+			return nil, fmt.Errorf("method is required")
+		}
+		if err := validatePattern(rule.Method); err != nil {
+			return nil, err
+		}
+		parsed := ipFilterRule{method: rule.Method}
+		for _, entry := range rule.Allow {
+			prefix, err := parseNetwork(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allow entry of %q: %v", rule.Method, err)
+			}
+			parsed.allow = append(parsed.allow, prefix)
+		}
+		for _, entry := range rule.Deny {
+			prefix, err := parseNetwork(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deny entry of %q: %v", rule.Method, err)
+			}
+			parsed.deny = append(parsed.deny, prefix)
+		}
+		filter.rules = append(filter.rules, parsed)
+	}
+	return filter, nil
+}
+
+// LoadIPFilterFromEnv builds a filter from the JSON list of rules in
+// IP_FILTER_RULES_FILE. Without it, the filter lets every request through.
+func LoadIPFilterFromEnv(proxies []string) (*IPFilter, error) {
+	var rules []IPFilterRule
+	if file := os.Getenv("IP_FILTER_RULES_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read IP filter rules: %v", err)
+		}
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return nil, fmt.Errorf("failed to parse IP filter rules: %v", err)
+		}
+	}
+	filter, err := NewIPFilter(proxies, rules...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP filter rule: %v", err)
+	}
+	return filter, nil
+}
+
+// allowed reports whether clientIP may call method.
+func (f *IPFilter) allowed(method, clientIP string) bool {
+	for _, rule := range f.rules {
+		if matchPattern(rule.method, method) {
+			return rule.allows(clientIP)
+		}
+	}
+	return true
+}
+
+// check refuses the gRPC request with PermissionDenied when its client may not call
+// fullMethod.
+func (f *IPFilter) check(ctx context.Context, fullMethod string) error {
+	if len(f.rules) == 0 {
+		return nil
+	}
+	if !f.allowed(fullMethod, pb.ClientIP(ctx)) {
+		return status.Errorf(codes.PermissionDenied, "Access from this address is not allowed")
+	}
+	return nil
+}
+
+// IPFilterInterceptor applies the filter to unary calls. It must run after
+// ClientIPInterceptor, which resolves the client address through the trusted proxies.
+func (f *IPFilter) IPFilterInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := f.check(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// IPFilterStreamInterceptor applies the filter to streams when they are opened.
+func (f *IPFilter) IPFilterStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := f.check(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// IPFilterMiddleware applies the filter to HTTP requests by their path, refusing them
+// with a 403 and a JSON body shaped like the errors of the gateway.
+func (f *IPFilter) IPFilterMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if len(f.rules) > 0 && !f.allowed(string(ctx.Path()), f.trustedProxies.httpClientIP(ctx)) {
+			writeHTTPError(ctx, fasthttp.StatusForbidden, codes.PermissionDenied, "Access from this address is not allowed")
+			return
+		}
+		next(ctx)
+	}
+}
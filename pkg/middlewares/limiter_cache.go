@@ -0,0 +1,148 @@
+package middlewares
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limiterCacheShards spreads the limiters over that many locks.
+const limiterCacheShards = 32
+
+// LimiterStats describes the limiters kept by a MemoryLimiterStore.
+type LimiterStats struct {
+	// Limiters is the number of limiters held.
+	Limiters int
+	// Evictions counts limiters dropped to stay within the maximum.
+	Evictions uint64
+	// Expirations counts limiters dropped after being idle for the idle TTL.
+	Expirations uint64
+}
+
+// limiterCache holds limiters in shards, each a map with a list from the most to the
+// least recently used limiter. A shard over its share of the maximum drops its least
+// recently used limiter, and a single sweeper drops limiters idle for longer than ttl.
+type limiterCache struct {
+	seed        maphash.Seed
+	shards      [limiterCacheShards]limiterShard
+	ttl         time.Duration
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type limiterShard struct {
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      list.List
+	capacity int
+}
+
+type limiterEntry struct {
+	key      string
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// newLimiterCache returns a cache of at most maxLimiters limiters, and starts its
+// sweeper.
+func newLimiterCache(maxLimiters int, ttl time.Duration) *limiterCache {
+	c := &limiterCache{
+		seed: maphash.MakeSeed(),
+		ttl:  ttl,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	capacity := max(1, (maxLimiters+limiterCacheShards-1)/limiterCacheShards)
+	for i := range c.shards {
+		c.shards[i].entries = make(map[string]*list.Element)
+		c.shards[i].capacity = capacity
+	}
+	go c.sweep(max(time.Second, min(ttl/2, time.Minute)))
+	return c
+}
+
+// get returns the limiter of key, creating it with limit and burst when there is none.
+func (c *limiterCache) get(key string, limit rate.Limit, burst int, now time.Time) *rate.Limiter {
+	shard := &c.shards[maphash.String(c.seed, key)%limiterCacheShards]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if element, ok := shard.entries[key]; ok {
+		entry := element.Value.(*limiterEntry)
+		entry.lastUsed = now
+		shard.lru.MoveToFront(element)
+		return entry.limiter
+	}
+
+	if shard.lru.Len() >= shard.capacity {
+		oldest := shard.lru.Back()
+		shard.lru.Remove(oldest)
+		delete(shard.entries, oldest.Value.(*limiterEntry).key)
+		c.evictions.Add(1)
+	}
+	entry := &limiterEntry{key: key, limiter: rate.NewLimiter(limit, burst), lastUsed: now}
+	shard.entries[key] = shard.lru.PushFront(entry)
+	return entry.limiter
+}
+
+// sweep drops idle limiters every interval until the cache is closed.
+func (c *limiterCache) sweep(interval time.Duration) {
+	defer close(c.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-ticker.C:
+			c.expire(now)
+		}
+	}
+}
+
+// expire drops the limiters last used before now minus the TTL. As the lists are in
+// order of use, each shard is walked from its back only up to the first recent limiter.
+func (c *limiterCache) expire(now time.Time) {
+	cutoff := now.Add(-c.ttl)
+	for i := range c.shards {
+		shard := &c.shards[i]
+		shard.mu.Lock()
+		for element := shard.lru.Back(); element != nil; element = shard.lru.Back() {
+			entry := element.Value.(*limiterEntry)
+			if entry.lastUsed.After(cutoff) {
+				break
+			}
+			shard.lru.Remove(element)
+			delete(shard.entries, entry.key)
+			c.expirations.Add(1)
+		}
+		shard.mu.Unlock()
+	}
+}
+
+// stats returns the current counters.
+func (c *limiterCache) stats() LimiterStats {
+	stats := LimiterStats{Evictions: c.evictions.Load(), Expirations: c.expirations.Load()}
+	for i := range c.shards {
+		c.shards[i].mu.Lock()
+		stats.Limiters += c.shards[i].lru.Len()
+		c.shards[i].mu.Unlock()
+	}
+	return stats
+}
+
+// close stops the sweeper and waits for it to return.
+func (c *limiterCache) close() {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+	})
+}
@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// LimitResult is the outcome of counting a request against its budget.
+type LimitResult struct {
+	// Allowed reports whether the request fits in the budget.
+	Allowed bool
+	// Remaining is the number of requests that would still be allowed right away.
+	Remaining int
+	// RetryAfter is the wait until the next request is allowed; zero when Allowed.
+	RetryAfter time.Duration
+	// ResetAfter is the wait until the budget is full again.
+	ResetAfter time.Duration
+}
+
+// LimiterStore keeps the token buckets of rate limit budgets. A bucket refills at limit
+// tokens per second and holds at most burst of them.
+type LimiterStore interface {
+	// Allow takes a token from the bucket of key if it has one.
+	Allow(ctx context.Context, key string, limit rate.Limit, burst int) (LimitResult, error)
+}
+
+// MemoryLimiterStore keeps the buckets in the process, so every replica of the server
+// enforces its own budget. It holds a bounded number of limiters and drops idle ones in
+// the background until it is closed.
+type MemoryLimiterStore struct {
+	cache *limiterCache
+}
+
+// MemoryLimiterStoreOption configures a MemoryLimiterStore.
+type MemoryLimiterStoreOption func(*memoryLimiterStoreConfig)
+
+type memoryLimiterStoreConfig struct {
+	maxLimiters int
+	idleTTL     time.Duration
+}
+
+// WithMaxLimiters bounds the number of limiters held (default 10000). The least
+// recently used ones are dropped first.
+func WithMaxLimiters(n int) MemoryLimiterStoreOption {
+	return func(c *memoryLimiterStoreConfig) {
+		c.maxLimiters = n
+	}
+}
+
+// WithIdleTTL drops limiters unused for d (default 10 minutes). It should be longer
+// than the time a bucket takes to refill, or idle clients get a full bucket early.
+func WithIdleTTL(d time.Duration) MemoryLimiterStoreOption {
+	return func(c *memoryLimiterStoreConfig) {
+		c.idleTTL = d
+	}
+}
+
+// NewMemoryLimiterStore returns an empty in-memory store. Close stops its sweeper.
+func NewMemoryLimiterStore(opts ...MemoryLimiterStoreOption) *MemoryLimiterStore {
+	config := memoryLimiterStoreConfig{maxLimiters: 10000, idleTTL: 10 * time.Minute}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &MemoryLimiterStore{cache: newLimiterCache(config.maxLimiters, config.idleTTL)}
+}
+
+// Allow implements LimiterStore.
+func (s *MemoryLimiterStore) Allow(ctx context.Context, key string, limit rate.Limit, burst int) (LimitResult, error) {
+	limiter := s.Limiter(key, limit, burst)
+	now := time.Now()
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return LimitResult{RetryAfter: rate.InfDuration}, nil
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return LimitResult{RetryAfter: delay, ResetAfter: refillTime(limiter, now)}, nil
+	}
+	return LimitResult{
+		Allowed:    true,
+		Remaining:  int(math.Max(0, math.Floor(limiter.TokensAt(now)))),
+		ResetAfter: refillTime(limiter, now),
+	}, nil
+}
+
+// refillTime returns how long the limiter takes to fill up from now.
+func refillTime(limiter *rate.Limiter, now time.Time) time.Duration {
+	missing := float64(limiter.Burst()) - limiter.TokensAt(now)
+	if missing <= 0 || limiter.Limit() <= 0 {
+		return 0
+	}
+	return time.Duration(missing / float64(limiter.Limit()) * float64(time.Second))
+}
+
+// Limiter gets or creates the rate limiter of a budget key.
+func (s *MemoryLimiterStore) Limiter(clientID string, limit rate.Limit, burst int) *rate.Limiter {
+	return s.cache.get(clientID, limit, burst, time.Now())
+}
+
+// Stats returns the number of limiters held and how many were dropped, to be exported
+// as metrics.
+func (s *MemoryLimiterStore) Stats() LimiterStats {
+	return s.cache.stats()
+}
+
+// Close stops dropping idle limiters in the background.
+func (s *MemoryLimiterStore) Close() {
+	s.cache.close()
+}
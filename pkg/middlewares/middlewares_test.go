@@ -1,10 +1,42 @@
 package middlewares
 
 import (
+	"audit"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"hash/maphash"
+	"helpers"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	pb "services"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/redis/go-redis/v9"
 	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 // Test CORS Middleware with a simulated OPTIONS request
@@ -28,8 +60,8 @@ func TestCORSMiddleware(t *testing.T) {
 	// Expected CORS headers
 	expectedHeaders := map[string]string{
 		"Access-Control-Allow-Origin":  "*",
-		"Access-Control-Allow-Methods": "GET, POST, PUT, DELETE, OPTIONS",
-		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+		"Access-Control-Allow-Methods": "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization, X-API-Key, X-Tenant-ID",
 	}
 
 	// Check if headers are correctly set
@@ -69,3 +101,919 @@ func TestRateLimiter(t *testing.T) {
 		t.Error("Expected request after reset to pass")
 	}
 }
+
+// peerContext returns the context of a request from ip.
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4242}})
+}
+
+// Test that rate limit policies apply to matching methods only, and that rejections
+// tell the client when to retry
+func TestRateLimiterPolicies(t *testing.T) {
+	limiter := NewRateLimiter(100, 100, DefaultTrustedProxies(), WithPolicies(RateLimitPolicy{
+		Method: "/authenticator.Auth/Login",
+		Rate:   1,
+		Burst:  1,
+	}))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	login := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/Login"}
+	other := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/Register"}
+	ctx := peerContext("203.0.113.7")
+
+	if _, err := limiter.RateLimiterInterceptor(ctx, nil, login, handler); err != nil {
+		t.Fatalf("Expected first login to pass, got %v", err)
+	}
+	_, err := limiter.RateLimiterInterceptor(ctx, nil, login, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected second login to be rejected with ResourceExhausted, got %v", err)
+	}
+	var retry *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	if retry == nil || retry.RetryDelay.AsDuration() <= 0 || retry.RetryDelay.AsDuration() > time.Second {
+		t.Errorf("Expected a RetryInfo of at most a second, got %v", retry)
+	}
+
+	if _, err := limiter.RateLimiterInterceptor(ctx, nil, other, handler); err != nil {
+		t.Errorf("Expected other methods to keep the default limit, got %v", err)
+	}
+	if _, err := limiter.RateLimiterInterceptor(peerContext("203.0.113.8"), nil, login, handler); err != nil {
+		t.Errorf("Expected another IP to have its own budget, got %v", err)
+	}
+}
+
+// Test that policies keyed by user count every user on their own, and fall back to the
+// IP for anonymous requests
+func TestRateLimiterPolicyPerUser(t *testing.T) {
+	limiter := NewRateLimiter(100, 100, nil, WithPolicies(RateLimitPolicy{
+		Method: "/authenticator.Auth/*",
+		Rate:   1,
+		Burst:  1,
+		Key:    []string{RateLimitKeyUser},
+	}))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/GetMe"}
+	as := func(userID string) context.Context {
+		return pb.WithPrincipal(peerContext("198.51.100.1"), &pb.Principal{UserID: userID, Method: pb.AuthMethodJWT})
+	}
+
+	if _, err := limiter.RateLimiterInterceptor(as("user-1"), nil, info, handler); err != nil {
+		t.Fatalf("Expected first request of user-1 to pass, got %v", err)
+	}
+	if _, err := limiter.RateLimiterInterceptor(as("user-1"), nil, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected second request of user-1 to be rejected, got %v", err)
+	}
+	if _, err := limiter.RateLimiterInterceptor(as("user-2"), nil, info, handler); err != nil {
+		t.Errorf("Expected user-2 behind the same IP to have its own budget, got %v", err)
+	}
+	if _, err := limiter.RateLimiterInterceptor(peerContext("198.51.100.1"), nil, info, handler); err != nil {
+		t.Errorf("Expected anonymous requests to be counted by IP, got %v", err)
+	}
+}
+
+// Test that failed authentications are counted against the client IP, and that the
+// client is refused before the handler runs once they used up its budget
+func TestAuthFailureInterceptor(t *testing.T) {
+	limiter := NewRateLimiter(0.001, 2, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/GetMe"}
+	calls := 0
+	unauthenticated := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	ctx := peerContext("203.0.113.9")
+
+	for i := 0; i < 3; i++ {
+		if _, err := limiter.AuthFailureInterceptor(ctx, nil, info, ok); err != nil {
+			t.Fatalf("Expected authenticated requests not to be counted, got %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := limiter.AuthFailureInterceptor(ctx, nil, info, unauthenticated); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("Expected failure %d to reach the handler, got %v", i+1, err)
+		}
+	}
+	if _, err := limiter.AuthFailureInterceptor(ctx, nil, info, unauthenticated); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected the client to be refused once its failures are used up, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the refused request not to reach the handler, got %d calls", calls)
+	}
+	if _, err := limiter.AuthFailureInterceptor(ctx, nil, info, ok); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected valid credentials to be refused as well, got %v", err)
+	}
+	if _, err := limiter.AuthFailureInterceptor(peerContext("203.0.113.10"), nil, info, unauthenticated); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected another IP to have its own budget, got %v", err)
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+// Test that opening a stream counts against the rate limit
+func TestRateLimiterStreamInterceptor(t *testing.T) {
+	limiter := NewRateLimiter(1, 1, nil)
+	info := &grpc.StreamServerInfo{FullMethod: "/authenticator.Auth/StreamSampleProtected"}
+	var clientIP string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		clientIP = pb.ClientIP(ss.Context())
+		return nil
+	}
+	stream := &fakeServerStream{ctx: peerContext("192.0.2.1")}
+
+	if err := limiter.RateLimiterStreamInterceptor(nil, stream, info, handler); err != nil {
+		t.Fatalf("Expected first stream to open, got %v", err)
+	}
+	if clientIP != "192.0.2.1" {
+		t.Errorf("Expected the stream to see the client address, got %q", clientIP)
+	}
+	if err := limiter.RateLimiterStreamInterceptor(nil, stream, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected second stream to be rejected with ResourceExhausted, got %v", err)
+	}
+}
+
+func TestLoadRateLimiterFromEnv(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policies.json")
+	write := func(body string) {
+		if err := os.WriteFile(file, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("RATE_LIMIT_POLICIES_FILE", file)
+
+	write(`[{"method": "/authenticator.Auth/Login", "rate": 0.5, "burst": 3, "key": ["ip", "user"]}]`)
+	limiter, err := LoadRateLimiterFromEnv(nil)
+	if err != nil {
+		t.Fatalf("Expected policies to load, got %v", err)
+	}
+	if len(limiter.policies) != 1 || limiter.policies[0].Burst != 3 {
+		t.Errorf("Expected the policy of the file, got %+v", limiter.policies)
+	}
+
+	write(`[{"method": "/authenticator.Auth/Login", "rate": 1, "burst": 1, "key": ["session"]}]`)
+	if _, err := LoadRateLimiterFromEnv(nil); err == nil {
+		t.Error("Expected an unknown key to be refused")
+	}
+}
+
+// Test that replicas sharing a Redis store share one budget
+func TestRedisLimiterStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.SetTime(time.Unix(1700000000, 0))
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	replica1, replica2 := NewRedisLimiterStore(client), NewRedisLimiterStore(client)
+	ctx := context.Background()
+
+	first, err := replica1.Allow(ctx, "203.0.113.7", 1, 2)
+	if err != nil || !first.Allowed || first.Remaining != 1 {
+		t.Fatalf("Expected first request to pass with one remaining, got %+v, %v", first, err)
+	}
+	if second, err := replica2.Allow(ctx, "203.0.113.7", 1, 2); err != nil || !second.Allowed || second.Remaining != 0 {
+		t.Fatalf("Expected second request to pass on the other replica, got %+v, %v", second, err)
+	}
+	third, err := replica1.Allow(ctx, "203.0.113.7", 1, 2)
+	if err != nil || third.Allowed {
+		t.Fatalf("Expected third request to exceed the shared budget, got %+v, %v", third, err)
+	}
+	if third.RetryAfter != time.Second || third.ResetAfter != 2*time.Second {
+		t.Errorf("Expected to retry after 1s and reset after 2s, got %+v", third)
+	}
+
+	server.SetTime(time.Unix(1700000001, 0))
+	if next, err := replica2.Allow(ctx, "203.0.113.7", 1, 2); err != nil || !next.Allowed {
+		t.Errorf("Expected a request to pass once a token refilled, got %+v, %v", next, err)
+	}
+}
+
+// Test that requests are limited in memory while Redis is down
+func TestRateLimiterFallsBackToMemory(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	var storeErrors int
+	limiter := NewRateLimiter(1, 1, nil,
+		WithLimiterStore(NewRedisLimiterStore(client)),
+		WithStoreErrorHandler(func(error) { storeErrors++ }),
+	)
+	server.Close()
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/Login"}
+	ctx := peerContext("192.0.2.9")
+	if _, err := limiter.RateLimiterInterceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("Expected first request to pass while Redis is down, got %v", err)
+	}
+	if _, err := limiter.RateLimiterInterceptor(ctx, nil, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected second request to be limited locally, got %v", err)
+	}
+	if storeErrors != 2 {
+		t.Errorf("Expected both store failures to be reported, got %d", storeErrors)
+	}
+}
+
+// httpRequest returns a request to path from remote, forwarded for forwardedFor when set.
+func httpRequest(path, remote, forwardedFor string) *fasthttp.RequestCtx {
+	var req fasthttp.Request
+	req.SetRequestURI(path)
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&req, &net.TCPAddr{IP: net.ParseIP(remote), Port: 4242}, nil)
+	return ctx
+}
+
+// Test that the HTTP middleware limits by remote address and sets the RateLimit headers
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := NewRateLimiter(1, 2, []string{"10.1.0.0/16"})
+	handler := limiter.RateLimitMiddleware(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	})
+
+	ctx := httpRequest("/v1/auth/login", "203.0.113.7", "")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusOK {
+		t.Fatalf("Expected first request to pass, got %d", ctx.Response.StatusCode())
+	}
+	for header, want := range map[string]string{"RateLimit-Limit": "2", "RateLimit-Remaining": "1", "RateLimit-Reset": "1"} {
+		if got := string(ctx.Response.Header.Peek(header)); got != want {
+			t.Errorf("Expected %s: %s, got %q", header, want, got)
+		}
+	}
+
+	// Headers of untrusted peers cannot move requests to another budget.
+	handler(httpRequest("/v1/auth/login", "203.0.113.7", "198.51.100.1"))
+	ctx = httpRequest("/v1/auth/login", "203.0.113.7", "198.51.100.2")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusTooManyRequests {
+		t.Fatalf("Expected third request to be rejected with 429, got %d", ctx.Response.StatusCode())
+	}
+	if got := string(ctx.Response.Header.Peek("Retry-After")); got != "1" {
+		t.Errorf("Expected Retry-After: 1, got %q", got)
+	}
+	if got := string(ctx.Response.Header.Peek("RateLimit-Remaining")); got != "0" {
+		t.Errorf("Expected RateLimit-Remaining: 0, got %q", got)
+	}
+	var body struct {
+		Code    codes.Code `json:"code"`
+		Message string     `json:"message"`
+	}
+	if err := json.Unmarshal(ctx.Response.Body(), &body); err != nil || body.Code != codes.ResourceExhausted {
+		t.Errorf("Expected a JSON body with code 8, got %s (%v)", ctx.Response.Body(), err)
+	}
+
+	// Requests through a trusted proxy are counted by the forwarded address.
+	ctx = httpRequest("/v1/auth/login", "10.1.2.3", "198.51.100.1")
+	handler(ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusOK {
+		t.Errorf("Expected request forwarded by a trusted proxy to pass, got %d", ctx.Response.StatusCode())
+	}
+}
+
+// Test that proxy headers are walked from the right past trusted hops only
+func TestTrustedProxiesResolve(t *testing.T) {
+	proxies := parseTrustedProxies([]string{"10.1.0.0/16", "::1", "not-an-ip"})
+	tests := []struct {
+		name    string
+		peer    string
+		headers forwardingHeaders
+		want    string
+	}{
+		{"untrusted peer", "203.0.113.7", forwardingHeaders{forwardedFor: []string{"198.51.100.1"}}, "203.0.113.7"},
+		{"no headers", "10.1.2.3", forwardingHeaders{}, "10.1.2.3"},
+		{"x-real-ip", "10.1.2.3", forwardingHeaders{realIP: "198.51.100.1"}, "198.51.100.1"},
+		{"spoofed leftmost hop", "10.1.2.3", forwardingHeaders{forwardedFor: []string{"192.0.2.1, 198.51.100.1, 10.1.9.9"}}, "198.51.100.1"},
+		{"repeated headers", "10.1.2.3", forwardingHeaders{forwardedFor: []string{"192.0.2.1", "198.51.100.1"}}, "198.51.100.1"},
+		{"all hops trusted", "10.1.2.3", forwardingHeaders{forwardedFor: []string{"10.1.0.5, 10.1.0.6"}}, "10.1.0.5"},
+		{"invalid hop", "10.1.2.3", forwardingHeaders{forwardedFor: []string{"198.51.100.1, unknown, 10.1.0.5"}}, "10.1.0.5"},
+		{"forwarded", "10.1.2.3", forwardingHeaders{
+			forwarded:    []string{`for=192.0.2.1;proto=https, for="198.51.100.1:4711", for=10.1.0.5`},
+			forwardedFor: []string{"192.0.2.99"},
+		}, "198.51.100.1"},
+		{"forwarded ipv6", "::1", forwardingHeaders{forwarded: []string{`for="[2001:db8:cafe::17]:4711"`}}, "2001:db8:cafe::17"},
+		{"forwarded obfuscated", "10.1.2.3", forwardingHeaders{forwarded: []string{"for=198.51.100.1, for=_hidden"}}, "10.1.2.3"},
+		{"ipv4-mapped", "10.1.2.3", forwardingHeaders{forwardedFor: []string{"::ffff:198.51.100.1"}}, "198.51.100.1"},
+	}
+	for _, tt := range tests {
+		if got := proxies.resolve(tt.peer, tt.headers); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	ctx := metadata.NewIncomingContext(peerContext("10.1.2.3"), metadata.Pairs("forwarded", "for=198.51.100.1"))
+	if ip, err := proxies.grpcClientIP(ctx); err != nil || ip != "198.51.100.1" {
+		t.Errorf("Expected gRPC client 198.51.100.1, got %s (%v)", ip, err)
+	}
+	if ip := proxies.httpClientIP(httpRequest("/", "10.1.2.3", "192.0.2.1, 198.51.100.1")); ip != "198.51.100.1" {
+		t.Errorf("Expected HTTP client 198.51.100.1, got %s", ip)
+	}
+}
+
+// Test that TRUSTED_PROXIES is validated and defaults to the loopback addresses
+func TestTrustedProxiesFromEnv(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "")
+	if proxies, err := TrustedProxiesFromEnv(); err != nil || len(proxies) != 2 {
+		t.Errorf("Expected the default trusted proxies, got %v (%v)", proxies, err)
+	}
+	t.Setenv("TRUSTED_PROXIES", "127.0.0.1, 10.1.0.0/16")
+	if proxies, err := TrustedProxiesFromEnv(); err != nil || len(proxies) != 2 || proxies[1] != "10.1.0.0/16" {
+		t.Errorf("Expected two trusted proxies, got %v (%v)", proxies, err)
+	}
+	t.Setenv("TRUSTED_PROXIES", "10.1.0.0/16,lb")
+	if _, err := TrustedProxiesFromEnv(); err == nil {
+		t.Error("Expected an invalid entry to be rejected")
+	}
+}
+
+// Test that the IP filter applies the first matching rule to gRPC calls
+func TestIPFilterInterceptor(t *testing.T) {
+	filter, err := NewIPFilter([]string{"10.1.0.0/16"},
+		IPFilterRule{Method: "/admin.Admin/Ping", Allow: []string{"0.0.0.0/0"}},
+		IPFilterRule{Method: "/admin.Admin/*", Allow: []string{"192.0.2.0/24"}, Deny: []string{"192.0.2.66"}},
+		IPFilterRule{Method: "/*", Deny: []string{"198.51.100.0/24"}},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create IP filter: %v", err)
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	// The filter sees the client address resolved by ClientIPInterceptor.
+	limiter := NewRateLimiter(1, 1, []string{"10.1.0.0/16"})
+	interceptor := ChainUnaryInterceptors(limiter.ClientIPInterceptor, filter.IPFilterInterceptor)
+
+	tests := []struct {
+		method string
+		ctx    context.Context
+		want   codes.Code
+	}{
+		{"/admin.Admin/DeleteTenant", peerContext("192.0.2.10"), codes.OK},
+		{"/admin.Admin/DeleteTenant", peerContext("203.0.113.7"), codes.PermissionDenied},
+		{"/admin.Admin/DeleteTenant", peerContext("192.0.2.66"), codes.PermissionDenied},
+		{"/admin.Admin/Ping", peerContext("203.0.113.7"), codes.OK},
+		{"/authenticator.Auth/Login", peerContext("203.0.113.7"), codes.OK},
+		{"/authenticator.Auth/Login", peerContext("198.51.100.1"), codes.PermissionDenied},
+		// Addresses forwarded by trusted proxies are filtered, not the proxies.
+		{"/admin.Admin/DeleteTenant", metadata.NewIncomingContext(peerContext("10.1.2.3"), metadata.Pairs("x-forwarded-for", "192.0.2.10")), codes.OK},
+		{"/admin.Admin/DeleteTenant", metadata.NewIncomingContext(peerContext("10.1.2.3"), metadata.Pairs("x-forwarded-for", "192.0.2.10, 203.0.113.7")), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		_, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.method, tt.want, got)
+		}
+	}
+
+	err = filter.IPFilterStreamInterceptor(nil, &fakeServerStream{ctx: peerContext("203.0.113.7")},
+		&grpc.StreamServerInfo{FullMethod: "/admin.Admin/Watch"},
+		func(srv interface{}, stream grpc.ServerStream) error { return nil })
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected stream to be denied, got %v", err)
+	}
+
+	if _, err := NewIPFilter(nil, IPFilterRule{Method: "/admin.Admin/*", Allow: []string{"office"}}); err == nil {
+		t.Error("Expected an invalid allow entry to be rejected")
+	}
+}
+
+// Test that clients calling through the gateway cannot choose the address the gRPC
+// server sees, while proxies in front of it still can
+func TestGatewayClientIP(t *testing.T) {
+	proxies := []string{"127.0.0.1", "10.1.0.0/16"}
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(GatewayHeaderMatcher))
+	var forwarded metadata.MD
+	gateway := ForwardedForMiddleware(proxies, fasthttpadaptor.NewFastHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, err := runtime.AnnotateContext(req.Context(), mux, req, "/admin.Admin/DeleteTenant")
+		if err != nil {
+			t.Fatalf("Failed to annotate context: %v", err)
+		}
+		forwarded, _ = metadata.FromOutgoingContext(ctx)
+	})))
+
+	// The gRPC server is reached by the gateway from the loopback address.
+	filter, err := NewIPFilter(proxies, IPFilterRule{Method: "/admin.Admin/*", Allow: []string{"192.0.2.0/24"}})
+	if err != nil {
+		t.Fatalf("Failed to create IP filter: %v", err)
+	}
+	limiter := NewRateLimiter(100, 100, proxies)
+	interceptor := ChainUnaryInterceptors(limiter.ClientIPInterceptor, filter.IPFilterInterceptor)
+	var clientIP string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		clientIP = pb.ClientIP(ctx)
+		return "ok", nil
+	}
+	call := func(ctx *fasthttp.RequestCtx) error {
+		forwarded = nil
+		gateway(ctx)
+		grpcCtx := metadata.NewIncomingContext(peerContext("127.0.0.1"), forwarded)
+		_, err := interceptor(grpcCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/admin.Admin/DeleteTenant"}, handler)
+		return err
+	}
+
+	spoofed := httpRequest("/v1/admin/tenants/1", "203.0.113.7", "192.0.2.10")
+	spoofed.Request.Header.Set("Forwarded", "for=192.0.2.10")
+	spoofed.Request.Header.Set("Grpc-Metadata-Forwarded", "for=192.0.2.10")
+	spoofed.Request.Header.Set("Grpc-Metadata-X-Forwarded-For", "192.0.2.10")
+	spoofed.Request.Header.Set("Grpc-Metadata-X-Real-Ip", "192.0.2.10")
+	if err := call(spoofed); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected spoofed proxy headers to be ignored, got %v", err)
+	}
+	for _, key := range []string{"forwarded", "x-real-ip"} {
+		if values := forwarded.Get(key); len(values) > 0 {
+			t.Errorf("Expected the gateway to drop %s, got %v", key, values)
+		}
+	}
+
+	proxied := httpRequest("/v1/admin/tenants/1", "10.1.2.3", "")
+	proxied.Request.Header.Set("Forwarded", "for=192.0.2.10")
+	if err := call(proxied); err != nil || clientIP != "192.0.2.10" {
+		t.Errorf("Expected the client named by a trusted proxy, got %q (%v)", clientIP, err)
+	}
+}
+
+// graphqlProbe is a GraphQL handler whose probe query calls resolve with the context of
+// the request, as the generated handlers call the gRPC server with it.
+type graphqlProbe struct {
+	resolve func(ctx context.Context) error
+}
+
+func (h graphqlProbe) CreateConnection(context.Context) (*grpc.ClientConn, func(), error) {
+	return nil, func() {}, nil
+}
+
+func (h graphqlProbe) GetQueries(*grpc.ClientConn) graphql.Fields {
+	return graphql.Fields{"probe": &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return "ok", h.resolve(p.Context)
+		},
+	}}
+}
+
+func (h graphqlProbe) GetMutations(*grpc.ClientConn) graphql.Fields { return nil }
+
+func (h graphqlProbe) GetSubscriptions(*grpc.ClientConn) graphql.Fields { return nil }
+
+// graphqlGateway returns a function sending a query to the GraphQL endpoint, mounted as
+// the server does, whose resolver calls method through interceptor from the loopback
+// address. It returns the error of the call.
+func graphqlGateway(t *testing.T, proxies []string, method string, interceptor grpc.UnaryServerInterceptor, handler grpc.UnaryHandler) func(*fasthttp.RequestCtx) error {
+	mux := helpers.NewGraphqlServeMux()
+	mux.SetIncomingHeaderMatcher(GatewayHeaderMatcher)
+	var callErr error
+	err := mux.AddHandler(graphqlProbe{resolve: func(ctx context.Context) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		_, callErr = interceptor(metadata.NewIncomingContext(peerContext("127.0.0.1"), md), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return callErr
+	}})
+	if err != nil {
+		t.Fatalf("Failed to add GraphQL handler: %v", err)
+	}
+	endpoint := ForwardedForMiddleware(proxies, HeaderForwarderMiddleware(fasthttpadaptor.NewFastHTTPHandler(mux)))
+	return func(ctx *fasthttp.RequestCtx) error {
+		callErr = status.Error(codes.Unknown, "query was not resolved")
+		ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+		ctx.Request.Header.SetContentType("application/json")
+		ctx.Request.SetBodyString(`{"query":"{ probe }"}`)
+		endpoint(ctx)
+		return callErr
+	}
+}
+
+// Test that GraphQL queries reach the gRPC server on behalf of their client, so that the
+// IP filter applies to them
+func TestGraphqlGatewayClientIP(t *testing.T) {
+	proxies := []string{"127.0.0.1", "10.1.0.0/16"}
+	filter, err := NewIPFilter(proxies, IPFilterRule{Method: "/authenticator.Auth/*", Allow: []string{"192.0.2.0/24"}})
+	if err != nil {
+		t.Fatalf("Failed to create IP filter: %v", err)
+	}
+	limiter := NewRateLimiter(100, 100, proxies)
+	var clientIP string
+	call := graphqlGateway(t, proxies, "/authenticator.Auth/Login",
+		ChainUnaryInterceptors(limiter.ClientIPInterceptor, filter.IPFilterInterceptor),
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			clientIP = pb.ClientIP(ctx)
+			return "ok", nil
+		})
+
+	denied := httpRequest("/graphql", "203.0.113.7", "192.0.2.10")
+	denied.Request.Header.Set("Grpc-Metadata-X-Forwarded-For", "192.0.2.10")
+	if err := call(denied); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected the IP filter to deny the GraphQL client, got %v", err)
+	}
+	if err := call(httpRequest("/graphql", "192.0.2.10", "")); err != nil || clientIP != "192.0.2.10" {
+		t.Errorf("Expected the remote address of the GraphQL client, got %q (%v)", clientIP, err)
+	}
+	if err := call(httpRequest("/graphql", "10.1.2.3", "192.0.2.11")); err != nil || clientIP != "192.0.2.11" {
+		t.Errorf("Expected the client named by a trusted proxy, got %q (%v)", clientIP, err)
+	}
+}
+
+// Test that the HTTP middleware filters by path and refuses with a 403
+func TestIPFilterMiddleware(t *testing.T) {
+	filter, err := NewIPFilter([]string{"10.1.0.0/16"}, IPFilterRule{Method: "/v1/admin/*", Allow: []string{"192.0.2.0/24"}})
+	if err != nil {
+		t.Fatalf("Failed to create IP filter: %v", err)
+	}
+	handler := filter.IPFilterMiddleware(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	})
+
+	tests := []struct {
+		path, remote, forwardedFor string
+		want                       int
+	}{
+		{"/v1/admin/tenants/1", "192.0.2.10", "", fasthttp.StatusOK},
+		{"/v1/admin/tenants/1", "203.0.113.7", "", fasthttp.StatusForbidden},
+		{"/v1/admin/tenants/1", "203.0.113.7", "192.0.2.10", fasthttp.StatusForbidden},
+		{"/v1/admin/tenants/1", "10.1.2.3", "192.0.2.10", fasthttp.StatusOK},
+		{"/v1/auth/login", "203.0.113.7", "", fasthttp.StatusOK},
+	}
+	for _, tt := range tests {
+		ctx := httpRequest(tt.path, tt.remote, tt.forwardedFor)
+		handler(ctx)
+		if got := ctx.Response.StatusCode(); got != tt.want {
+			t.Errorf("%s from %s (%s): expected %d, got %d", tt.path, tt.remote, tt.forwardedFor, tt.want, got)
+		}
+	}
+
+	ctx := httpRequest("/v1/admin/tenants", "203.0.113.7", "")
+	handler(ctx)
+	var body struct {
+		Code codes.Code `json:"code"`
+	}
+	if err := json.Unmarshal(ctx.Response.Body(), &body); err != nil || body.Code != codes.PermissionDenied {
+		t.Errorf("Expected a JSON body with code 7, got %s (%v)", ctx.Response.Body(), err)
+	}
+}
+
+// Test that the memory store drops the least recently used and idle limiters
+func TestMemoryLimiterStoreEviction(t *testing.T) {
+	store := NewMemoryLimiterStore(WithMaxLimiters(limiterCacheShards), WithIdleTTL(time.Minute))
+	defer store.Close()
+
+	// Every shard holds a single limiter, so a second key of a shard evicts the first.
+	var keys []string
+	for i := 0; len(keys) < 2; i++ {
+		key := fmt.Sprintf("client-%d", i)
+		if maphash.String(store.cache.seed, key)%limiterCacheShards == 0 {
+			keys = append(keys, key)
+		}
+	}
+	first := store.Limiter(keys[0], 1, 1)
+	if store.Limiter(keys[0], 1, 1) != first {
+		t.Fatal("Expected the same limiter for the same key")
+	}
+	store.Limiter(keys[1], 1, 1)
+	if store.Limiter(keys[0], 1, 1) == first {
+		t.Error("Expected the least recently used limiter to be evicted")
+	}
+	if stats := store.Stats(); stats.Evictions != 2 || stats.Limiters != 1 {
+		t.Errorf("Expected 2 evictions and 1 limiter, got %+v", stats)
+	}
+
+	store.cache.expire(time.Now().Add(2 * time.Minute))
+	if stats := store.Stats(); stats.Limiters != 0 || stats.Expirations != 1 {
+		t.Errorf("Expected the idle limiter to expire, got %+v", stats)
+	}
+
+	store.Close()
+	store.Close() // Closing twice is harmless.
+}
+
+// legacyLimiterMap is the limiter map the memory store replaced: one lock, random
+// eviction and a goroutine per client. It is kept as a baseline for the benchmarks.
+type legacyLimiterMap struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func (m *legacyLimiterMap) get(clientID string) *rate.Limiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if limiter, exists := m.limiters[clientID]; exists {
+		return limiter
+	}
+	if len(m.limiters) >= 10000 {
+		for k := range m.limiters {
+			delete(m.limiters, k)
+			break
+		}
+	}
+	limiter := rate.NewLimiter(5, 10)
+	m.limiters[clientID] = limiter
+	go func() {
+		time.Sleep(10 * time.Minute)
+		m.mu.Lock()
+		delete(m.limiters, clientID)
+		m.mu.Unlock()
+	}()
+	return limiter
+}
+
+// benchmarkKeys are more clients than the limiters held, as during a scan.
+var benchmarkKeys = func() []string {
+	keys := make([]string, 50000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("198.51.%d.%d", i/256, i%256)
+	}
+	return keys
+}()
+
+func BenchmarkLegacyLimiterMap(b *testing.B) {
+	m := &legacyLimiterMap{limiters: make(map[string]*rate.Limiter)}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			m.get(benchmarkKeys[i%len(benchmarkKeys)]).Allow()
+		}
+	})
+}
+
+func BenchmarkMemoryLimiterStore(b *testing.B) {
+	store := NewMemoryLimiterStore()
+	defer store.Close()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			store.Limiter(benchmarkKeys[i%len(benchmarkKeys)], 5, 10).Allow()
+		}
+	})
+}
+
+// Test that the auth interceptor rejects revoked tokens
+func TestAuthUnaryInterceptorRevokedToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	pb.SetRevocationStore(pb.NewMemoryRevocationStore())
+	defer pb.SetRevocationStore(pb.NewMemoryRevocationStore())
+
+	token, err := pb.GenerateJWT("revoked@test.com")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	parsed, err := pb.VerifyJWT(token)
+	if err != nil {
+		t.Fatalf("Failed to verify token: %v", err)
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/SampleProtected"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+
+	if _, err := AuthUnaryInterceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("Expected valid token to pass, got %v", err)
+	}
+
+	if err := pb.Revocations().RevokeToken(ctx, parsed.ID, parsed.ExpiresAt.Time); err != nil {
+		t.Fatalf("Failed to revoke token: %v", err)
+	}
+	if _, err := AuthUnaryInterceptor(ctx, nil, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected revoked token to be rejected with Unauthenticated, got %v", err)
+	}
+}
+
+// Test that the auth interceptor follows the (thunder.auth) rule of the method
+func TestAuthUnaryInterceptorPublicMethod(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	ctx := context.Background()
+
+	public := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/Login"}
+	if _, err := AuthUnaryInterceptor(ctx, nil, public, handler); err != nil {
+		t.Errorf("Expected public method to pass without a token, got %v", err)
+	}
+
+	protected := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/SampleProtected"}
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{})
+	if _, err := AuthUnaryInterceptor(ctx, nil, protected, handler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected protected method without token to be rejected with Unauthenticated, got %v", err)
+	}
+}
+
+type stubAPIKeyVerifier struct{}
+
+func (stubAPIKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (*pb.Claims, error) {
+	if key != "thk_valid" {
+		return nil, pb.ErrInvalidAPIKey
+	}
+	return pb.NewClaims("ci@test.com"), nil
+}
+
+// Test that the auth interceptor accepts API keys sent in x-api-key
+func TestAuthUnaryInterceptorAPIKey(t *testing.T) {
+	pb.SetAPIKeyVerifier(stubAPIKeyVerifier{})
+	defer pb.SetAPIKeyVerifier(nil)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/SampleProtected"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "thk_valid"))
+	if _, err := AuthUnaryInterceptor(ctx, nil, info, handler); err != nil {
+		t.Errorf("Expected valid API key to pass, got %v", err)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "thk_unknown"))
+	if _, err := AuthUnaryInterceptor(ctx, nil, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected unknown API key to be rejected with Unauthenticated, got %v", err)
+	}
+}
+
+// Test that API keys authenticate GraphQL queries as they do gRPC calls
+func TestGraphqlGatewayAPIKey(t *testing.T) {
+	pb.SetAPIKeyVerifier(stubAPIKeyVerifier{})
+	defer pb.SetAPIKeyVerifier(nil)
+
+	var principal *pb.Principal
+	call := graphqlGateway(t, DefaultTrustedProxies(), "/authenticator.Auth/SampleProtected", AuthUnaryInterceptor,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			principal, _ = pb.FromContext(ctx)
+			return "ok", nil
+		})
+
+	valid := httpRequest("/graphql", "192.0.2.10", "")
+	valid.Request.Header.Set("X-Api-Key", "thk_valid")
+	if err := call(valid); err != nil {
+		t.Fatalf("Expected valid API key to pass, got %v", err)
+	}
+	if principal == nil || principal.Email != "ci@test.com" {
+		t.Errorf("Expected the owner of the API key, got %+v", principal)
+	}
+
+	unknown := httpRequest("/graphql", "192.0.2.10", "")
+	unknown.Request.Header.Set("X-Api-Key", "thk_unknown")
+	if err := call(unknown); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected unknown API key to be rejected with Unauthenticated, got %v", err)
+	}
+}
+
+// Test that handlers see the principal of the token, never a client-sent current_user
+func TestAuthUnaryInterceptorPrincipal(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	token, err := pb.GenerateJWT("principal@test.com", pb.WithSubject("user-1"), pb.WithRoles("admin"))
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	var got *pb.Principal
+	var forwarded []string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = pb.FromContext(ctx)
+		md, _ := metadata.FromIncomingContext(ctx)
+		forwarded = md.Get("current_user")
+		return "ok", nil
+	}
+
+	protected := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/SampleProtected"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer "+token,
+		"current_user", "attacker@test.com",
+	))
+	if _, err := AuthUnaryInterceptor(ctx, nil, protected, handler); err != nil {
+		t.Fatalf("Expected valid token to pass, got %v", err)
+	}
+	if got == nil || got.UserID != "user-1" || got.Email != "principal@test.com" || got.Method != pb.AuthMethodJWT {
+		t.Errorf("Expected principal of the token, got %+v", got)
+	}
+	if !got.HasRole("admin") {
+		t.Errorf("Expected principal to carry the roles of the token")
+	}
+	if len(forwarded) != 0 {
+		t.Errorf("Expected client-sent current_user to be stripped, got %v", forwarded)
+	}
+
+	public := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/Login"}
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("current_user", "attacker@test.com"))
+	if _, err := AuthUnaryInterceptor(ctx, nil, public, handler); err != nil {
+		t.Fatalf("Expected public method to pass, got %v", err)
+	}
+	if got != nil || len(forwarded) != 0 {
+		t.Errorf("Expected no identity on public methods, got %+v and %v", got, forwarded)
+	}
+}
+
+func TestAuthUnaryInterceptorClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "billing-worker"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	auth, err := pb.NewCertificateAuthenticator([]pb.CertificateRule{{CN: "billing-*", Roles: []string{"admin"}}})
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+	pb.SetCertificateAuthenticator(auth)
+	t.Cleanup(func() { pb.SetCertificateAuthenticator(nil) })
+
+	var got *pb.Principal
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = pb.FromContext(ctx)
+		return "ok", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/SampleProtected"}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+	if _, err := AuthUnaryInterceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("Expected matching client certificate to pass, got %v", err)
+	}
+	if got == nil || got.UserID != "billing-worker" || got.Method != pb.AuthMethodCertificate {
+		t.Errorf("Expected principal of the certificate, got %+v", got)
+	}
+
+	// The certificate of the gateway never stands in for the callers it forwards.
+	auth.Exclude(cert.Raw)
+	_, err = AuthUnaryInterceptor(ctx, nil, info, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with the gateway certificate, got %v", err)
+	}
+
+	pb.SetCertificateAuthenticator(nil)
+	_, err = AuthUnaryInterceptor(ctx, nil, info, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without certificate rules, got %v", err)
+	}
+}
+
+func TestAuthUnaryInterceptorTenant(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	token, err := pb.GenerateJWT("tenant@test.com", pb.WithSubject("user-1"), pb.WithTenant("acme"))
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	var tenantID string
+	var request audit.Request
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		tenantID = pb.TenantID(ctx)
+		request, _ = audit.RequestFromContext(ctx)
+		return "ok", nil
+	}
+
+	protected := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/SampleProtected"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	if _, err := AuthUnaryInterceptor(ctx, nil, protected, handler); err != nil {
+		t.Fatalf("Expected valid token to pass, got %v", err)
+	}
+	if tenantID != "acme" {
+		t.Errorf("Expected the tenant of the token, got %q", tenantID)
+	}
+	if request.ActorID != "user-1" || request.TenantID != "acme" {
+		t.Errorf("Expected the caller to be described to the audit log, got %+v", request)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer "+token,
+		pb.TenantHeader, "globex",
+	))
+	_, err = AuthUnaryInterceptor(ctx, nil, protected, handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a token of another tenant, got %v", err)
+	}
+
+	public := &grpc.UnaryServerInfo{FullMethod: "/authenticator.Auth/Login"}
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(pb.TenantHeader, "globex"))
+	if _, err := AuthUnaryInterceptor(ctx, nil, public, handler); err != nil {
+		t.Fatalf("Expected public method to pass, got %v", err)
+	}
+	if tenantID != "globex" {
+		t.Errorf("Expected the tenant of the header on public methods, got %q", tenantID)
+	}
+}
+
+// Test that GraphQL queries name their tenant in the x-tenant-id header as gRPC calls do
+func TestGraphqlGatewayTenant(t *testing.T) {
+	var tenantID string
+	call := graphqlGateway(t, DefaultTrustedProxies(), "/authenticator.Auth/Login", AuthUnaryInterceptor,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			tenantID = pb.TenantID(ctx)
+			return "ok", nil
+		})
+
+	ctx := httpRequest("/graphql", "192.0.2.10", "")
+	ctx.Request.Header.Set("X-Tenant-Id", "globex")
+	if err := call(ctx); err != nil {
+		t.Fatalf("Expected public method to pass, got %v", err)
+	}
+	if tenantID != "globex" {
+		t.Errorf("Expected the tenant of the header, got %q", tenantID)
+	}
+}
@@ -0,0 +1,169 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	pb "services"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// Parts of a request that rate limit budgets can be keyed by.
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"
+	RateLimitKeyAPIKey = "api_key"
+)
+
+// RateLimitPolicy limits the methods matching Method. Requests share a budget when all
+// parts named by Key are equal.
+type RateLimitPolicy struct {
+	// Method is a full method name such as "/authenticator.Auth/Login", or a pattern
+	// such as "/authenticator.Auth/*". A trailing "*" also matches further slashes.
+	Method string `json:"method"`
+	// Rate is the sustained number of requests per second; Burst the most at once.
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+	// Key lists "ip", "user" and "api_key" (default: "ip"). Requests without a user or
+	// API key are counted by IP for that part.
+	Key []string `json:"key"`
+}
+
+// Validate reports a policy that cannot be applied.
+func (p RateLimitPolicy) Validate() error {
+	if p.Method == "" {
+		return fmt.Errorf("method is required")
+	}
+	if err := validatePattern(p.Method); err != nil {
+		return err
+	}
+	if p.Rate <= 0 || p.Burst <= 0 {
+		return fmt.Errorf("rate and burst of %q must be positive", p.Method)
+	}
+	for _, part := range p.Key {
+		switch part {
+		case RateLimitKeyIP, RateLimitKeyUser, RateLimitKeyAPIKey:
+		default:
+			return fmt.Errorf("unknown key %q in policy of %q", part, p.Method)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the policy applies to fullMethod.
+func (p RateLimitPolicy) matches(fullMethod string) bool {
+	return matchPattern(p.Method, fullMethod)
+}
+
+// validatePattern reports a method or path pattern that matchPattern cannot use.
+func validatePattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid method pattern %q: %v", pattern, err)
+	}
+	return nil
+}
+
+// matchPattern reports whether name matches pattern in the syntax of path.Match, except
+// that a pattern ending in "*" without other wildcards matches every name with its
+// prefix, so that "/admin/*" also covers "/admin/users/1".
+func matchPattern(pattern, name string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok && !strings.ContainsAny(prefix, `*?[\`) {
+		return strings.HasPrefix(name, prefix)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// budgetKey returns the key of the budget the request is counted against.
+func (p RateLimitPolicy) budgetKey(ctx context.Context, clientIP string) string {
+	parts := p.Key
+	if len(parts) == 0 {
+		parts = []string{RateLimitKeyIP}
+	}
+	principal, authenticated := pb.FromContext(ctx)
+	var b strings.Builder
+	b.WriteString(p.Method)
+	for _, part := range parts {
+		b.WriteByte('|')
+		switch {
+		case part == RateLimitKeyUser && authenticated:
+			b.WriteString("user:" + principal.TenantID + "/" + principal.UserID)
+		case part == RateLimitKeyAPIKey && authenticated && principal.Method == pb.AuthMethodAPIKey:
+			b.WriteString("api_key:" + principal.TokenID)
+		default:
+			b.WriteString("ip:" + clientIP)
+		}
+	}
+	return b.String()
+}
+
+// WithPolicies limits the methods matching a policy by the first matching policy, in
+// order, instead of the default limit of the rate limiter.
+func WithPolicies(policies ...RateLimitPolicy) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.policies = append(r.policies, policies...)
+	}
+}
+
+// LoadRateLimiterFromEnv builds a rate limiter whose default limit is RATE_LIMIT_RATE
+// requests per second (default 5) with bursts of RATE_LIMIT_BURST (default 10), and
+// whose policies are read from the JSON list in RATE_LIMIT_POLICIES_FILE. Budgets are
+// kept in the store selected by RATE_LIMIT_STORE: "memory", the default, or "redis" at
+// RATE_LIMIT_REDIS_URL.
+func LoadRateLimiterFromEnv(proxies []string, opts ...RateLimiterOption) (*RateLimiter, error) {
+	limit, burst := rate.Limit(5), 10
+	if v := os.Getenv("RATE_LIMIT_RATE"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("RATE_LIMIT_RATE must be a positive number")
+		}
+		limit = rate.Limit(n)
+	}
+	if v := os.Getenv("RATE_LIMIT_BURST"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("RATE_LIMIT_BURST must be a positive number")
+		}
+		burst = n
+	}
+
+	var policies []RateLimitPolicy
+	if file := os.Getenv("RATE_LIMIT_POLICIES_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rate limit policies: %v", err)
+		}
+		if err := json.Unmarshal(data, &policies); err != nil {
+			return nil, fmt.Errorf("failed to parse rate limit policies: %v", err)
+		}
+		for _, policy := range policies {
+			if err := policy.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid rate limit policy: %v", err)
+			}
+		}
+	}
+	opts = append(opts, WithPolicies(policies...))
+
+	switch kind := os.Getenv("RATE_LIMIT_STORE"); kind {
+	case "", "memory":
+	case "redis":
+		url := os.Getenv("RATE_LIMIT_REDIS_URL")
+		if url == "" {
+			return nil, fmt.Errorf("RATE_LIMIT_REDIS_URL is not set")
+		}
+		options, err := redis.ParseURL(url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_REDIS_URL: %v", err)
+		}
+		opts = append(opts, WithLimiterStore(NewRedisLimiterStore(redis.NewClient(options))))
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE %q", kind)
+	}
+	return NewRateLimiter(limit, burst, proxies, opts...), nil
+}
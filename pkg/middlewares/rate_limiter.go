@@ -2,141 +2,226 @@ package middlewares
 
 import (
 	"context"
-	"net"
-	"strings"
-	"sync"
+	pb "services"
 	"time"
 
 	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
-// RateLimiter structure
+// RateLimiter limits requests per client. Methods matching one of its policies are
+// limited by that policy, all others by the default rate and burst per client IP.
 type RateLimiter struct {
-	mu             sync.Mutex
-	limiters       map[string]*rate.Limiter
 	rate           rate.Limit
 	burst          int
-	trustedProxies map[string]bool
-	maxLimiters    int
+	policies       []RateLimitPolicy
+	trustedProxies trustedProxies
+	// store keeps the budgets; local takes over when it fails.
+	store        LimiterStore
+	local        *MemoryLimiterStore
+	onStoreError func(error)
 }
 
-// NewRateLimiter initializes a rate limiter with configurable rate, burst, and trusted proxies
-func NewRateLimiter(r rate.Limit, b int, proxies []string) *RateLimiter {
-	trusted := make(map[string]bool)
-	for _, proxy := range proxies {
-		if isValidIP(proxy) {
-			trusted[proxy] = true
-		}
+// RateLimiterOption configures a RateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// WithLimiterStore keeps the budgets in store instead of in memory, such as a
+// RedisLimiterStore shared by all replicas. While store fails, requests are limited in
+// memory.
+func WithLimiterStore(store LimiterStore) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.store = store
+	}
+}
+
+// WithStoreErrorHandler is called with the errors of the limiter store.
+func WithStoreErrorHandler(handler func(error)) RateLimiterOption {
+	return func(r *RateLimiter) {
+		r.onStoreError = handler
 	}
+}
 
-	return &RateLimiter{
-		limiters:       make(map[string]*rate.Limiter),
+// NewRateLimiter initializes a rate limiter with configurable rate, burst, and trusted
+// proxies, given as IP addresses or CIDR ranges
+func NewRateLimiter(r rate.Limit, b int, proxies []string, opts ...RateLimiterOption) *RateLimiter {
+	local := NewMemoryLimiterStore()
+	limiter := &RateLimiter{
 		rate:           r,
 		burst:          b,
-		trustedProxies: trusted,
-		maxLimiters:    10000, // Default maximum number of limiters to prevent memory leaks
+		trustedProxies: parseTrustedProxies(proxies),
+		store:          local,
+		local:          local,
 	}
+	for _, opt := range opts {
+		opt(limiter)
+	}
+	return limiter
 }
 
-// isValidIP checks if a string is a valid IP address
-func isValidIP(ip string) bool {
-	return net.ParseIP(ip) != nil
+// Stats returns the limiters held in memory by the rate limiter.
+func (r *RateLimiter) Stats() LimiterStats {
+	return r.local.Stats()
 }
 
-// GetLimiter gets or creates a rate limiter for a specific client
-func (r *RateLimiter) GetLimiter(clientID string) *rate.Limiter {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// Close releases the in-memory limiters of the rate limiter.
+func (r *RateLimiter) Close() {
+	r.local.Close()
+}
 
-	// Return existing limiter if it exists
-	if limiter, exists := r.limiters[clientID]; exists {
-		return limiter
-	}
+// GetLimiter gets or creates the default in-memory rate limiter for a specific client
+func (r *RateLimiter) GetLimiter(clientID string) *rate.Limiter {
+	return r.local.Limiter(clientID, r.rate, r.burst)
+}
 
-	// Prevent memory leaks by enforcing a maximum number of limiters
-	if len(r.limiters) >= r.maxLimiters {
-		// Simple eviction strategy: remove one random entry
-		// For production, consider using LRU or similar algorithm
-		for k := range r.limiters {
-			delete(r.limiters, k)
-			break
+// budget returns the key and the limit of the budget that counts the request: the one
+// of the first policy matching fullMethod, or the default budget of the client IP.
+func (r *RateLimiter) budget(ctx context.Context, fullMethod, clientIP string) (string, rate.Limit, int) {
+	for _, policy := range r.policies {
+		if policy.matches(fullMethod) {
+			return policy.budgetKey(ctx, clientIP), rate.Limit(policy.Rate), policy.Burst
 		}
 	}
+	return clientIP, r.rate, r.burst
+}
 
-	limiter := rate.NewLimiter(r.rate, r.burst)
-	r.limiters[clientID] = limiter
+// take counts a request against the budget of key in the store, or in memory when the
+// store fails.
+func (r *RateLimiter) take(ctx context.Context, key string, limit rate.Limit, burst int) LimitResult {
+	result, err := r.store.Allow(ctx, key, limit, burst)
+	if err == nil {
+		return result
+	}
+	if r.onStoreError != nil {
+		r.onStoreError(err)
+	}
+	// The memory store does not fail.
+	result, _ = r.local.Allow(ctx, key, limit, burst)
+	return result
+}
 
-	// Cleanup old limiters after a timeout
-	go func() {
-		time.Sleep(10 * time.Minute)
-		r.mu.Lock()
-		delete(r.limiters, clientID)
-		r.mu.Unlock()
-	}()
+// allow counts the request against its budget and returns the context carrying the
+// resolved client address. Requests over budget are rejected with ResourceExhausted
+// and a RetryInfo detail.
+func (r *RateLimiter) allow(ctx context.Context, fullMethod string) (context.Context, error) {
+	clientID, err := r.trustedProxies.grpcClientIP(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	return limiter
+	key, limit, burst := r.budget(ctx, fullMethod, clientID)
+	if result := r.take(ctx, key, limit, burst); !result.Allowed {
+		return nil, rateLimitedStatus(result.RetryAfter)
+	}
+	return pb.WithClientIP(ctx, clientID), nil
 }
 
-// DefaultTrustedProxies returns a list of commonly trusted proxy IPs
-func DefaultTrustedProxies() []string {
-	return []string{"127.0.0.1", "::1"}
+// rateLimitedStatus returns a ResourceExhausted status telling the client when to retry.
+func rateLimitedStatus(delay time.Duration) error {
+	st := status.New(codes.ResourceExhausted, "Too many requests, slow down")
+	if delay == rate.InfDuration {
+		return st.Err()
+	}
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
 }
 
-// RateLimiterInterceptor applies rate limiting
+// RateLimiterInterceptor applies rate limiting. Policies keyed by user or API key need
+// it to run after AuthUnaryInterceptor.
 func (r *RateLimiter) RateLimiterInterceptor(
 	ctx context.Context,
 	req interface{},
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler) (interface{}, error) {
 
-	p, ok := peer.FromContext(ctx)
-	if !ok {
-		return nil, status.Errorf(codes.Internal, "could not determine peer")
+	ctx, err := r.allow(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
 	}
 
-	peerIP, _, err := net.SplitHostPort(p.Addr.String())
+	// Proceed to the next handler, letting it see the resolved client address
+	return handler(ctx, req)
+}
+
+// RateLimiterStreamInterceptor is the streaming counterpart of RateLimiterInterceptor.
+// Opening a stream counts as one request.
+func (r *RateLimiter) RateLimiterStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := r.allow(ss.Context(), info.FullMethod)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "invalid peer address: %v", err)
-	}
-
-	var clientID string
-
-	// Only trust proxy headers if the request is from a trusted proxy
-	if r.trustedProxies[peerIP] {
-		if md, ok := metadata.FromIncomingContext(ctx); ok {
-			// Check X-Forwarded-For (may be a comma-separated list)
-			if xff := md.Get("x-forwarded-for"); len(xff) > 0 && xff[0] != "" {
-				ips := strings.Split(xff[0], ",")
-				if len(ips) > 0 && strings.TrimSpace(ips[0]) != "" {
-					cleanIP := strings.TrimSpace(ips[0])
-					if isValidIP(cleanIP) {
-						clientID = cleanIP
-					}
-				}
-			} else if xri := md.Get("x-real-ip"); len(xri) > 0 && xri[0] != "" {
-				cleanIP := strings.TrimSpace(xri[0])
-				if isValidIP(cleanIP) {
-					clientID = cleanIP
-				}
-			}
-		}
+		return err
 	}
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+}
 
-	// If no trusted clientID was found from headers, use the peer's IP
-	if clientID == "" {
-		clientID = peerIP
+// ClientIPInterceptor stores the resolved client address in the context without
+// limiting, so that interceptors running before RateLimiterInterceptor see it.
+func (r *RateLimiter) ClientIPInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	clientID, err := r.trustedProxies.grpcClientIP(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return handler(pb.WithClientIP(ctx, clientID), req)
+}
 
-	limiter := r.GetLimiter(clientID)
-	if !limiter.Allow() {
-		return nil, status.Errorf(codes.ResourceExhausted, "Too many requests, slow down")
+// ClientIPStreamInterceptor is the streaming counterpart of ClientIPInterceptor.
+func (r *RateLimiter) ClientIPStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	clientID, err := r.trustedProxies.grpcClientIP(ss.Context())
+	if err != nil {
+		return err
 	}
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: pb.WithClientIP(ss.Context(), clientID)})
+}
 
-	// Proceed to the next handler
-	return handler(ctx, req)
+// authFailureKey is the budget of failed authentications of a client IP.
+func authFailureKey(clientIP string) string {
+	return "auth_failure|ip:" + clientIP
+}
+
+// authenticate refuses clients that used up the default budget of their IP with failed
+// authentications, then calls handler and counts its Unauthenticated errors against that
+// budget. The budgets are kept in memory by every replica, so that checking them does
+// not take from them.
+func (r *RateLimiter) authenticate(ctx context.Context, handler func(context.Context) error) error {
+	clientIP, err := r.trustedProxies.grpcClientIP(ctx)
+	if err != nil {
+		return err
+	}
+	limiter := r.GetLimiter(authFailureKey(clientIP))
+	if tokens := limiter.Tokens(); tokens < 1 {
+		if limiter.Limit() <= 0 {
+			return rateLimitedStatus(rate.InfDuration)
+		}
+		return rateLimitedStatus(time.Duration((1 - tokens) / float64(limiter.Limit()) * float64(time.Second)))
+	}
+	err = handler(pb.WithClientIP(ctx, clientIP))
+	if status.Code(err) == codes.Unauthenticated {
+		limiter.Allow()
+	}
+	return err
+}
+
+// AuthFailureInterceptor limits failed authentications per client IP, so that forged
+// tokens and guessed API keys are refused before they are looked up. It must run before
+// AuthUnaryInterceptor, as RateLimiterInterceptor, which runs after it to count requests
+// per user or API key, never sees the requests it rejects.
+func (r *RateLimiter) AuthFailureInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var resp interface{}
+	err := r.authenticate(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// AuthFailureStreamInterceptor is the streaming counterpart of AuthFailureInterceptor.
+func (r *RateLimiter) AuthFailureStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return r.authenticate(ss.Context(), func(ctx context.Context) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	})
 }
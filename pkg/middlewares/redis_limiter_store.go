@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// ErrLimiterStoreUnavailable is returned by RedisLimiterStore while it waits to retry
+// Redis after a failure.
+var ErrLimiterStoreUnavailable = errors.New("rate limit store unavailable")
+
+// gcraScript implements the generic cell rate algorithm: a budget is the theoretical
+// arrival time (TAT) of the next request, in microseconds of the Redis clock, so all
+// replicas agree on it. A request is allowed while the TAT is at most burst emission
+// intervals ahead of now.
+//
+// KEYS[1] is the budget; ARGV[1] the emission interval and ARGV[2] the burst. It returns
+// {allowed, remaining, retry after, reset after}, with durations in microseconds.
+var gcraScript = redis.NewScript(`
+if redis.replicate_commands then redis.replicate_commands() end
+local emission = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local tolerance = emission * burst
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+  tat = now
+end
+local new_tat = tat + emission
+local allow_at = new_tat - tolerance
+if allow_at > now then
+  return {0, 0, allow_at - now, tat - now}
+end
+
+redis.call("SET", KEYS[1], string.format("%.0f", new_tat), "PX", math.ceil((new_tat - now) / 1000))
+return {1, math.floor((now - allow_at) / emission), 0, new_tat - now}
+`)
+
+// RedisLimiterStore keeps the buckets in Redis, so that all replicas of the server share
+// one budget per key.
+type RedisLimiterStore struct {
+	client redis.UniversalClient
+	prefix string
+	// retryInterval is how long Redis is skipped after a failure.
+	retryInterval time.Duration
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+// NewRedisLimiterStore returns a store keeping its buckets under "thunder:ratelimit:"
+// in client.
+func NewRedisLimiterStore(client redis.UniversalClient) *RedisLimiterStore {
+	return &RedisLimiterStore{client: client, prefix: "thunder:ratelimit:", retryInterval: 5 * time.Second}
+}
+
+// Allow implements LimiterStore. After Redis fails, it returns
+// ErrLimiterStoreUnavailable without contacting Redis for a few seconds, so that an
+// outage does not slow down every request.
+func (s *RedisLimiterStore) Allow(ctx context.Context, key string, limit rate.Limit, burst int) (LimitResult, error) {
+	if limit <= 0 || burst <= 0 {
+		return LimitResult{RetryAfter: rate.InfDuration}, nil
+	}
+	s.mu.Lock()
+	down := time.Now().Before(s.downUntil)
+	s.mu.Unlock()
+	if down {
+		return LimitResult{}, ErrLimiterStoreUnavailable
+	}
+
+	emission := int64(math.Ceil(float64(time.Second/time.Microsecond) / float64(limit)))
+	values, err := gcraScript.Run(ctx, s.client, []string{s.prefix + key}, emission, burst).Int64Slice()
+	if err != nil {
+		s.mu.Lock()
+		s.downUntil = time.Now().Add(s.retryInterval)
+		s.mu.Unlock()
+		return LimitResult{}, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+	if len(values) != 4 {
+		return LimitResult{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+	return LimitResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
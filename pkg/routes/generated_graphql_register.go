@@ -18,4 +18,9 @@ func RegisterGraphQLHandlers(mux *runtime.ServeMux, conn *grpc.ClientConn) {
 		log.Fatalln("Failed to register GraphQL gateway:", err)
 	}
 	
+	err = RegisterUserAdminGraphqlHandler(mux, conn)
+	if err != nil {
+		log.Fatalln("Failed to register GraphQL gateway:", err)
+	}
+	
 }
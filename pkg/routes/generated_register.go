@@ -20,6 +20,11 @@ func RegisterServers(server *grpc.Server, client *db.PrismaClient, sugar *zap.Su
 		Logger:       sugar,
 	})
 	
+	RegisterUserAdminServer(server, &pb.UserAdminServiceServer{
+		PrismaClient: client,
+		Logger:       sugar,
+	})
+	
 }
 
 // RegisterHandlers registers gRPC-Gateway handlers.
@@ -31,4 +36,9 @@ func RegisterHandlers(gwmux *runtime.ServeMux, conn *grpc.ClientConn) {
 		log.Fatalln("Failed to register gateway:", err)
 	}
 	
+	err = RegisterUserAdminHandler(context.Background(), gwmux, conn)
+	if err != nil {
+		log.Fatalln("Failed to register gateway:", err)
+	}
+	
 }
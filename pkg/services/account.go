@@ -0,0 +1,222 @@
+package services
+
+import (
+	"audit"
+	"context"
+	"db"
+	. "generated"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userToProto converts a stored user; the password hash and secrets never leave the service.
+func userToProto(user *db.UserModel) *User {
+	desc, _ := user.Desc()
+	_, disabled := user.DisabledAt()
+	return &User{
+		Id:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Name:          user.Name,
+		Surname:       user.Surname,
+		Age:           int32(user.Age),
+		Desc:          desc,
+		Roles:         user.Roles,
+		TotpEnabled:   user.TotpEnabled,
+		CreatedAt:     user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.Format(time.RFC3339),
+		Disabled:      disabled,
+	}
+}
+
+// checkCurrentPassword confirms a sensitive change with the password of the user.
+// Wrong passwords count as failed logins, so a stolen token cannot be used to guess it.
+func (s *AuthServiceServer) checkCurrentPassword(ctx context.Context, user *db.UserModel, password string) error {
+	guard := newLoginGuard(ctx, user.Email)
+	if err := guard.check(ctx); err != nil {
+		s.Logger.Warnw("Password check refused: too many failed attempts", "user", user.ID)
+		return err
+	}
+	if _, err := verifyPassword(user.Password, password); err != nil {
+		s.Logger.Warnw("Invalid password attempt", "user", user.ID)
+		s.recordLoginFailure(ctx, guard, user.Email)
+		return status.Errorf(codes.Unauthenticated, "incorrect password")
+	}
+	if err := guard.succeed(ctx, user.Email); err != nil {
+		s.Logger.Warnw("Failed to clear failed logins", "user", user.ID, "error", err)
+	}
+	return nil
+}
+
+// GetMe returns the account of the current user.
+func (s *AuthServiceServer) GetMe(ctx context.Context, in *GetMeRequest) (*User, error) {
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return userToProto(user), nil
+}
+
+// UpdateProfile updates the fields of the current user named in the update mask.
+func (s *AuthServiceServer) UpdateProfile(ctx context.Context, in *UpdateProfileRequest) (*User, error) {
+	if len(in.UpdateMask) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "update_mask is required")
+	}
+	profile := in.GetUser()
+	if profile == nil {
+		profile = &User{}
+	}
+	params := make([]db.UserSetParam, 0, len(in.UpdateMask))
+	for _, path := range in.UpdateMask {
+		switch path {
+		case "name":
+			if profile.Name == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "name must not be empty")
+			}
+			params = append(params, db.User.Name.Set(profile.Name))
+		case "surname":
+			params = append(params, db.User.Surname.Set(profile.Surname))
+		case "age":
+			if profile.Age < 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "age must not be negative")
+			}
+			params = append(params, db.User.Age.Set(int(profile.Age)))
+		case "desc":
+			if profile.Desc == "" {
+				params = append(params, db.User.Desc.SetOptional(nil))
+			} else {
+				params = append(params, db.User.Desc.Set(profile.Desc))
+			}
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+		}
+	}
+
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := Scope(ctx, s.PrismaClient).UpdateUser(ctx, user, params...)
+	if err != nil {
+		s.Logger.Errorw("Failed to update profile", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not update profile")
+	}
+
+	s.Logger.Infow("Profile updated", "user", user.ID, "fields", in.UpdateMask)
+	return userToProto(updated), nil
+}
+
+// ChangePassword replaces the password of the current user and logs them out of every
+// other session. The access token of the current session is replaced by a new one.
+func (s *AuthServiceServer) ChangePassword(ctx context.Context, in *ChangePasswordRequest) (*ChangePasswordReply, error) {
+	if in.NewPassword == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "new password is required")
+	}
+	if err := currentPasswordPolicy().Check(in.NewPassword); err != nil {
+		return nil, err
+	}
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCurrentPassword(ctx, user, in.CurrentPassword); err != nil {
+		audit.Record(ctx, audit.Event{
+			Action:  audit.ActionPasswordChange,
+			Outcome: audit.OutcomeFailure,
+			Reason:  "invalid current password",
+		})
+		return nil, err
+	}
+
+	hashedPassword, err := hashPassword(in.NewPassword)
+	if err != nil {
+		s.Logger.Errorw("Failed to hash password", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not change password")
+	}
+	if _, err := Scope(ctx, s.PrismaClient).UpdateUser(ctx, user, db.User.Password.Set(hashedPassword)); err != nil {
+		s.Logger.Errorw("Failed to update password", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not change password")
+	}
+
+	sessionID := MustFromContext(ctx).SessionID
+	if _, err := s.revokeOtherSessions(ctx, user.ID, sessionID); err != nil {
+		s.Logger.Errorw("Failed to revoke sessions after password change", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "password changed, but other sessions could not be revoked")
+	}
+	// Tokens outside of a session, such as impersonation tokens, are revoked as well.
+	cutoff := revocationCutoff()
+	if err := Revocations().RevokeUserTokens(ctx, user.ID, cutoff); err != nil {
+		s.Logger.Errorw("Failed to revoke access tokens after password change", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "password changed, but access tokens could not be revoked")
+	}
+
+	reply := &ChangePasswordReply{Reply: "Password changed"}
+	if sessionID != "" {
+		token, err := reissueAccessToken(user, sessionID, cutoff)
+		if err != nil {
+			s.Logger.Errorw("Failed to reissue access token after password change", "user", user.ID, "error", err)
+			return nil, status.Errorf(codes.Internal, "password changed, but no new token could be issued")
+		}
+		reply.Token = token
+		reply.ExpiresIn = int64(AccessTokenTTL().Seconds())
+	}
+
+	s.Logger.Infow("Password changed", "user", user.ID)
+	audit.Record(ctx, audit.Event{Action: audit.ActionPasswordChange, Outcome: audit.OutcomeSuccess})
+	return reply, nil
+}
+
+// reissueAccessToken signs a new access token of the session after the tokens of the
+// user were revoked at cutoff. It is dated from at least a millisecond after the cutoff,
+// so that it outlives it despite the rounding of iat.
+func reissueAccessToken(user *db.UserModel, sessionID string, cutoff time.Time) (string, error) {
+	claims := NewClaims(user.Email,
+		WithSubject(user.ID),
+		WithRoles(user.Roles...),
+		WithEmailVerified(user.EmailVerified),
+		WithSession(sessionID),
+		WithTenant(user.TenantID),
+	)
+	if issuedAt := cutoff.Add(time.Millisecond); claims.IssuedAt.Before(issuedAt) {
+		claims.IssuedAt = jwt.NewNumericDate(issuedAt)
+		claims.ExpiresAt = jwt.NewNumericDate(issuedAt.Add(AccessTokenTTL()))
+	}
+	return signClaims(claims)
+}
+
+// DeleteAccount deletes the current user and everything that belongs to them. The
+// tokens of their sessions are revoked first, since the user row that records other
+// revocations is deleted with the account.
+func (s *AuthServiceServer) DeleteAccount(ctx context.Context, in *DeleteAccountRequest) (*DeleteAccountReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCurrentPassword(ctx, user, in.Password); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.revokeOtherSessions(ctx, user.ID, ""); err != nil {
+		s.Logger.Errorw("Failed to revoke sessions before account deletion", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not delete account")
+	}
+	if err := Scope(ctx, s.PrismaClient).DeleteUser(ctx, user); err != nil {
+		s.Logger.Errorw("Failed to delete user", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not delete account")
+	}
+
+	s.Logger.Infow("Account deleted", "user", user.ID)
+	return &DeleteAccountReply{Reply: "Account deleted"}, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"db"
+	. "generated"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// accountContext returns the context of a call made with an access token of user-1.
+func accountContext(t *testing.T) context.Context {
+	t.Helper()
+	return WithPrincipal(context.Background(), NewPrincipal(NewClaims("account@test.com", WithSubject("user-1"))))
+}
+
+func TestUserToProto(t *testing.T) {
+	desc := "About me"
+	user := &db.UserModel{InnerUser: db.InnerUser{
+		ID:       "user-1",
+		Email:    "account@test.com",
+		Name:     "Ada",
+		Surname:  "Lovelace",
+		Age:      36,
+		Desc:     &desc,
+		Password: "$2a$12$hash",
+		Roles:    []string{"admin"},
+	}}
+
+	reply := userToProto(user)
+	assert.Equal(t, "user-1", reply.Id)
+	assert.Equal(t, "Lovelace", reply.Surname)
+	assert.Equal(t, int32(36), reply.Age)
+	assert.Equal(t, "About me", reply.Desc)
+	assert.Equal(t, []string{"admin"}, reply.Roles)
+}
+
+func TestUpdateProfileValidation(t *testing.T) {
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	ctx := context.Background()
+
+	_, err := s.UpdateProfile(ctx, &UpdateProfileRequest{User: &User{Name: "Ada"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected missing update_mask to be rejected")
+
+	_, err = s.UpdateProfile(ctx, &UpdateProfileRequest{User: &User{Email: "other@test.com"}, UpdateMask: []string{"email"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected email to be immutable")
+
+	_, err = s.UpdateProfile(ctx, &UpdateProfileRequest{User: &User{}, UpdateMask: []string{"name"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected empty name to be rejected")
+}
+
+func TestUpdateProfile(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	current := db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "account@test.com", Name: "Ada"}}
+	mock.User.Expect(
+		client.User.FindFirst(Scope(context.Background(), client).UserWhere(db.User.ID.Equals("user-1"))...),
+	).Returns(current)
+	updated := current
+	updated.Surname = "Lovelace"
+	mock.User.Expect(
+		client.User.FindUnique(db.User.ID.Equals("user-1")).Update(
+			db.User.Surname.Set("Lovelace"),
+		),
+	).Returns(updated)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	reply, err := s.UpdateProfile(accountContext(t), &UpdateProfileRequest{
+		User:       &User{Name: "ignored", Surname: "Lovelace"},
+		UpdateMask: []string{"surname"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", reply.Name, "expected fields outside the mask to be left alone")
+	assert.Equal(t, "Lovelace", reply.Surname)
+}
+
+func TestChangePasswordWrongCurrentPassword(t *testing.T) {
+	useLoginAttemptStore(t, NewMemoryLoginAttemptStore())
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	hashed, err := hashPassword("current-password")
+	require.NoError(t, err)
+	mock.User.Expect(
+		client.User.FindFirst(Scope(context.Background(), client).UserWhere(db.User.ID.Equals("user-1"))...),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "account@test.com", Password: hashed}})
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err = s.ChangePassword(accountContext(t), &ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "new-password"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = s.ChangePassword(context.Background(), &ChangePasswordRequest{CurrentPassword: "current-password"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected missing new password to be rejected")
+}
+
+func TestReissueAccessTokenOutlivesCutoff(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	ctx := context.Background()
+	useRevocationStore(t, NewMemoryRevocationStore())
+
+	user := &db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "account@test.com", Roles: []string{"support"}}}
+	old := NewClaims(user.Email, WithSubject(user.ID), WithActor("admin-1", "admin@test.com"))
+	cutoff := time.Now()
+	require.NoError(t, Revocations().RevokeUserTokens(ctx, user.ID, cutoff))
+	assert.ErrorIs(t, CheckRevocation(ctx, old), ErrTokenRevoked, "expected tokens without a session to be revoked")
+
+	token, err := reissueAccessToken(user, "session-1", cutoff)
+	require.NoError(t, err)
+	claims, err := VerifyJWT(token)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.Equal(t, []string{"support"}, claims.Roles)
+	assert.NoError(t, CheckRevocation(ctx, claims), "expected the reissued token to outlive the cutoff")
+}
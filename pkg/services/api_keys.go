@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"db"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	. "generated"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyPrefix starts every API key, so that leaked keys are easy to recognize.
+const apiKeyPrefix = "thk_"
+
+// apiKeyDisplayLength is the number of leading characters stored in clear to tell keys apart.
+const apiKeyDisplayLength = 12
+
+// apiKeyTouchInterval limits how often lastUsedAt is written for a busy key.
+const apiKeyTouchInterval = time.Minute
+
+// ErrInvalidAPIKey is returned for unknown, revoked or expired API keys.
+var ErrInvalidAPIKey = errors.New("invalid or expired API key")
+
+// APIKeyVerifier resolves an API key to the identity of its owner.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*Claims, error)
+}
+
+var (
+	apiKeyVerifierMu sync.RWMutex
+	apiKeyVerifier   APIKeyVerifier
+)
+
+// SetAPIKeyVerifier enables API key authentication. Without a verifier, x-api-key
+// headers are rejected.
+func SetAPIKeyVerifier(v APIKeyVerifier) {
+	apiKeyVerifierMu.Lock()
+	defer apiKeyVerifierMu.Unlock()
+	apiKeyVerifier = v
+}
+
+// VerifyAPIKey returns the claims of the owner of an API key, the same identity a JWT of
+// that user carries, with the roles narrowed to the scopes of the key.
+func VerifyAPIKey(ctx context.Context, key string) (*Claims, error) {
+	apiKeyVerifierMu.RLock()
+	v := apiKeyVerifier
+	apiKeyVerifierMu.RUnlock()
+	if v == nil {
+		return nil, fmt.Errorf("%w: API keys are not enabled", ErrInvalidAPIKey)
+	}
+	return v.VerifyAPIKey(ctx, key)
+}
+
+// PrismaAPIKeyVerifier verifies API keys against the ApiKey table.
+type PrismaAPIKeyVerifier struct {
+	client *db.PrismaClient
+}
+
+// NewPrismaAPIKeyVerifier creates a verifier backed by the given Prisma client.
+func NewPrismaAPIKeyVerifier(client *db.PrismaClient) *PrismaAPIKeyVerifier {
+	return &PrismaAPIKeyVerifier{client: client}
+}
+
+func (p *PrismaAPIKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (*Claims, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	row, err := p.client.APIKey.FindUnique(
+		db.APIKey.KeyHash.Equals(hashToken(key)),
+	).With(
+		db.APIKey.User.Fetch(),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, revoked := row.RevokedAt(); revoked || userDisabled(row.User()) {
+		return nil, ErrInvalidAPIKey
+	}
+	if expiresAt, ok := row.ExpiresAt(); ok && now.After(expiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+	if lastUsed, ok := row.LastUsedAt(); !ok || now.Sub(lastUsed) > apiKeyTouchInterval {
+		// Best effort: a failed write must not fail the request.
+		_, _ = p.client.APIKey.FindUnique(
+			db.APIKey.ID.Equals(row.ID),
+		).Update(
+			db.APIKey.LastUsedAt.Set(now),
+		).Exec(ctx)
+	}
+
+	user := row.User()
+	return apiKeyClaims(user, row), nil
+}
+
+// apiKeyClaims builds the identity of a key. Its roles are the scopes of the key that the
+// owner still holds, so that removing a role from a user also removes it from their keys.
+func apiKeyClaims(user *db.UserModel, key *db.APIKeyModel) *Claims {
+	roles := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if slices.Contains(user.Roles, scope) {
+			roles = append(roles, scope)
+		}
+	}
+	claims := NewClaims(user.Email,
+		WithSubject(user.ID),
+		WithRoles(roles...),
+		WithEmailVerified(user.EmailVerified),
+		WithTenant(user.TenantID),
+	)
+	// API key identities have no token ID and cannot be revoked through the revocation
+	// store; revoke the key instead.
+	claims.ID = ""
+	claims.APIKeyID = key.ID
+	return claims
+}
+
+// newAPIKey returns a random key and the prefix stored in clear.
+func newAPIKey() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %v", err)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return key, key[:apiKeyDisplayLength], nil
+}
+
+// apiKeyToProto converts a stored key; the hash never leaves the service.
+func apiKeyToProto(row *db.APIKeyModel) *APIKey {
+	key := &APIKey{
+		Id:        row.ID,
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		Scopes:    row.Scopes,
+		CreatedAt: row.CreatedAt.Format(time.RFC3339),
+	}
+	if expiresAt, ok := row.ExpiresAt(); ok {
+		key.ExpiresAt = expiresAt.Format(time.RFC3339)
+	}
+	if lastUsed, ok := row.LastUsedAt(); ok {
+		key.LastUsedAt = lastUsed.Format(time.RFC3339)
+	}
+	return key
+}
+
+// CreateAPIKey creates an API key for the current user. The key is returned once; only
+// its hash is stored.
+func (s *AuthServiceServer) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest) (*CreateAPIKeyReply, error) {
+	if in.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+	if in.ExpiresIn < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "expires_in must not be negative")
+	}
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	// An admin acting as the user must not leave a key of the user behind.
+	if MustFromContext(ctx).ImpersonatorID != "" {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot create API keys while impersonating")
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range in.Scopes {
+		if !slices.Contains(user.Roles, scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "cannot grant role %q", scope)
+		}
+	}
+
+	key, prefix, err := newAPIKey()
+	if err != nil {
+		s.Logger.Errorw("Failed to generate API key", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not create API key")
+	}
+	params := []db.APIKeySetParam{
+		db.APIKey.Scopes.Set(in.Scopes),
+	}
+	if in.ExpiresIn > 0 {
+		params = append(params, db.APIKey.ExpiresAt.Set(time.Now().Add(time.Duration(in.ExpiresIn)*time.Second)))
+	}
+	row, err := s.PrismaClient.APIKey.CreateOne(
+		db.APIKey.Name.Set(in.Name),
+		db.APIKey.Prefix.Set(prefix),
+		db.APIKey.KeyHash.Set(hashToken(key)),
+		db.APIKey.User.Link(db.User.ID.Equals(user.ID)),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		s.Logger.Errorw("Failed to store API key", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not create API key")
+	}
+
+	s.Logger.Infow("API key created", "user", user.ID, "key", row.ID)
+	return &CreateAPIKeyReply{Key: key, ApiKey: apiKeyToProto(row)}, nil
+}
+
+// ListAPIKeys returns the active API keys of the current user.
+func (s *AuthServiceServer) ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest) (*ListAPIKeysReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.PrismaClient.APIKey.FindMany(
+		db.APIKey.UserID.Equals(user.ID),
+		db.APIKey.RevokedAt.IsNull(),
+	).OrderBy(
+		db.APIKey.CreatedAt.Order(db.SortOrderDesc),
+	).Exec(ctx)
+	if err != nil {
+		s.Logger.Errorw("Failed to list API keys", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not list API keys")
+	}
+
+	reply := &ListAPIKeysReply{ApiKeys: make([]*APIKey, 0, len(rows))}
+	for i := range rows {
+		reply.ApiKeys = append(reply.ApiKeys, apiKeyToProto(&rows[i]))
+	}
+	return reply, nil
+}
+
+// RevokeAPIKey revokes an API key of the current user.
+func (s *AuthServiceServer) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest) (*RevokeAPIKeyReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := s.PrismaClient.APIKey.FindMany(
+		db.APIKey.ID.Equals(in.Id),
+		db.APIKey.UserID.Equals(user.ID),
+		db.APIKey.RevokedAt.IsNull(),
+	).Update(
+		db.APIKey.RevokedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		s.Logger.Errorw("Failed to revoke API key", "user", user.ID, "key", in.Id, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not revoke API key")
+	}
+	if revoked.Count == 0 {
+		return nil, status.Errorf(codes.NotFound, "API key not found")
+	}
+
+	s.Logger.Infow("API key revoked", "user", user.ID, "key", in.Id)
+	return &RevokeAPIKeyReply{Reply: "API key revoked"}, nil
+}
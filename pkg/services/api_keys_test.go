@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"db"
+	. "generated"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	key, prefix, err := newAPIKey()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, apiKeyPrefix), "expected key to start with %q", apiKeyPrefix)
+	assert.Len(t, prefix, apiKeyDisplayLength)
+	assert.True(t, strings.HasPrefix(key, prefix))
+
+	other, _, err := newAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestAPIKeyClaims(t *testing.T) {
+	user := &db.UserModel{InnerUser: db.InnerUser{
+		ID:    "user-1",
+		Email: "ci@example.com",
+		Roles: []string{"deploy"},
+	}}
+	key := &db.APIKeyModel{InnerAPIKey: db.InnerAPIKey{
+		ID:     "key-1",
+		Scopes: []string{"deploy", "admin"},
+	}}
+
+	claims := apiKeyClaims(user, key)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "ci@example.com", claims.Email)
+	assert.Equal(t, []string{"deploy"}, claims.Roles, "expected scopes the user no longer holds to be dropped")
+	assert.Equal(t, "key-1", claims.APIKeyID)
+	assert.Empty(t, claims.ID)
+}
+
+func TestPrismaAPIKeyVerifier(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	revokedAt := db.DateTime(time.Now().Add(-time.Hour))
+	expiresAt := db.DateTime(time.Now().Add(-time.Minute))
+	owner := &db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "ci@example.com"}}
+	mock.APIKey.Expect(
+		client.APIKey.FindUnique(
+			db.APIKey.KeyHash.Equals(hashToken("thk_unknown")),
+		).With(db.APIKey.User.Fetch()),
+	).Errors(db.ErrNotFound)
+	mock.APIKey.Expect(
+		client.APIKey.FindUnique(
+			db.APIKey.KeyHash.Equals(hashToken("thk_revoked")),
+		).With(db.APIKey.User.Fetch()),
+	).Returns(db.APIKeyModel{
+		InnerAPIKey:     db.InnerAPIKey{ID: "key-1", UserID: "user-1", RevokedAt: &revokedAt},
+		RelationsAPIKey: db.RelationsAPIKey{User: owner},
+	})
+	mock.APIKey.Expect(
+		client.APIKey.FindUnique(
+			db.APIKey.KeyHash.Equals(hashToken("thk_expired")),
+		).With(db.APIKey.User.Fetch()),
+	).Returns(db.APIKeyModel{
+		InnerAPIKey:     db.InnerAPIKey{ID: "key-2", UserID: "user-1", ExpiresAt: &expiresAt},
+		RelationsAPIKey: db.RelationsAPIKey{User: owner},
+	})
+
+	verifier := NewPrismaAPIKeyVerifier(client)
+	for _, key := range []string{"not-a-key", "thk_unknown", "thk_revoked", "thk_expired"} {
+		_, err := verifier.VerifyAPIKey(context.Background(), key)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, "expected %s to be rejected", key)
+	}
+}
+
+func TestVerifyAPIKeyDisabled(t *testing.T) {
+	SetAPIKeyVerifier(nil)
+	_, err := VerifyAPIKey(context.Background(), "thk_anything")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+
+	_, err := s.CreateAPIKey(context.Background(), &CreateAPIKeyRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected missing name to be rejected")
+
+	_, err = s.CreateAPIKey(context.Background(), &CreateAPIKeyRequest{Name: "ci", ExpiresIn: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected negative expiry to be rejected")
+}
+
+func TestAccountMethodsRequireInteractivePrincipal(t *testing.T) {
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	apiKey := NewPrincipal(&Claims{Email: "ci@example.com", APIKeyID: "key-1"})
+	certificate := &Principal{UserID: "billing-service", Method: AuthMethodCertificate}
+
+	methods := map[string]func(ctx context.Context) error{
+		"UpdateProfile": func(ctx context.Context) error {
+			_, err := s.UpdateProfile(ctx, &UpdateProfileRequest{User: &User{Name: "Ada"}, UpdateMask: []string{"name"}})
+			return err
+		},
+		"ChangePassword": func(ctx context.Context) error {
+			_, err := s.ChangePassword(ctx, &ChangePasswordRequest{CurrentPassword: "old", NewPassword: "correct horse battery"})
+			return err
+		},
+		"DeleteAccount": func(ctx context.Context) error {
+			_, err := s.DeleteAccount(ctx, &DeleteAccountRequest{})
+			return err
+		},
+		"CreateAPIKey": func(ctx context.Context) error {
+			_, err := s.CreateAPIKey(ctx, &CreateAPIKeyRequest{Name: "ci"})
+			return err
+		},
+		"ListAPIKeys": func(ctx context.Context) error {
+			_, err := s.ListAPIKeys(ctx, &ListAPIKeysRequest{})
+			return err
+		},
+		"RevokeAPIKey": func(ctx context.Context) error {
+			_, err := s.RevokeAPIKey(ctx, &RevokeAPIKeyRequest{})
+			return err
+		},
+		"RevokeAllSessions": func(ctx context.Context) error {
+			_, err := s.RevokeAllSessions(ctx, &RevokeAllSessionsRequest{})
+			return err
+		},
+		"ListSessions": func(ctx context.Context) error {
+			_, err := s.ListSessions(ctx, &ListSessionsRequest{})
+			return err
+		},
+		"RevokeSession": func(ctx context.Context) error {
+			_, err := s.RevokeSession(ctx, &RevokeSessionRequest{})
+			return err
+		},
+		"RevokeOtherSessions": func(ctx context.Context) error {
+			_, err := s.RevokeOtherSessions(ctx, &RevokeOtherSessionsRequest{})
+			return err
+		},
+		"EnrollTOTP": func(ctx context.Context) error {
+			_, err := s.EnrollTOTP(ctx, &EnrollTOTPRequest{})
+			return err
+		},
+		"ConfirmTOTP": func(ctx context.Context) error {
+			_, err := s.ConfirmTOTP(ctx, &ConfirmTOTPRequest{})
+			return err
+		},
+		"DisableTOTP": func(ctx context.Context) error {
+			_, err := s.DisableTOTP(ctx, &DisableTOTPRequest{})
+			return err
+		},
+	}
+	for name, call := range methods {
+		for _, p := range []*Principal{apiKey, certificate} {
+			err := call(WithPrincipal(context.Background(), p))
+			assert.Equal(t, codes.PermissionDenied, status.Code(err), "expected %s to refuse %s principals", name, p.Method)
+		}
+	}
+}
+
+func TestCreateAPIKeyRefusesImpersonation(t *testing.T) {
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	claims := NewClaims("user@test.com", WithSubject("user-1"), WithActor("admin-1", "admin@test.com"))
+
+	_, err := s.CreateAPIKey(WithPrincipal(context.Background(), NewPrincipal(claims)), &CreateAPIKeyRequest{Name: "backdoor"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "expected impersonation tokens to be refused")
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"audit"
+	"context"
+	"db"
+	. "generated"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader is the metadata key of the ID that correlates a request across services.
+const RequestIDHeader = "x-request-id"
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+// requestID returns the ID the client or a proxy assigned to the request.
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(RequestIDHeader); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// listPageSize returns the number of items of a page of a list method.
+func listPageSize(requested int32) (int, error) {
+	switch {
+	case requested < 0:
+		return 0, status.Errorf(codes.InvalidArgument, "page_size must not be negative")
+	case requested == 0:
+		return defaultPageSize, nil
+	case requested > maxPageSize:
+		return maxPageSize, nil
+	}
+	return int(requested), nil
+}
+
+// AuditRequest describes the request of ctx for the audit log: the caller, their tenant
+// and where the request came from. The auth interceptors store it with audit.WithRequest
+// once the caller is known.
+func AuditRequest(ctx context.Context) audit.Request {
+	r := audit.Request{
+		TenantID:  TenantID(ctx),
+		IP:        ClientIP(ctx),
+		UserAgent: userAgent(ctx),
+		RequestID: requestID(ctx),
+	}
+	if p, ok := FromContext(ctx); ok {
+		r.ActorID, r.ActorEmail = p.UserID, p.Email
+	}
+	return r
+}
+
+// auditLoginSuccess records a completed login of the user.
+func auditLoginSuccess(ctx context.Context, user *db.UserModel) {
+	audit.Record(ctx, audit.Event{
+		Action:     audit.ActionLogin,
+		Outcome:    audit.OutcomeSuccess,
+		ActorID:    user.ID,
+		ActorEmail: user.Email,
+	})
+}
+
+// auditLoginFailure records a refused login; userID is empty for unknown accounts.
+func auditLoginFailure(ctx context.Context, userID, email, reason string) {
+	audit.Record(ctx, audit.Event{
+		Action:     audit.ActionLogin,
+		Outcome:    audit.OutcomeFailure,
+		ActorID:    userID,
+		ActorEmail: email,
+		Reason:     reason,
+	})
+}
+
+// auditTokenRevoke records that the caller revoked tokens of target, which is empty
+// when they revoked their own.
+func auditTokenRevoke(ctx context.Context, target, reason string) {
+	audit.Record(ctx, audit.Event{
+		Action:   audit.ActionTokenRevoke,
+		Outcome:  audit.OutcomeSuccess,
+		TargetID: target,
+		Reason:   reason,
+	})
+}
+
+// auditEventToProto converts a stored event.
+func auditEventToProto(row *db.AuditEventModel) *AuditEvent {
+	return &AuditEvent{
+		Id:         row.ID,
+		Action:     row.Action,
+		Outcome:    row.Outcome,
+		ActorId:    row.ActorID,
+		ActorEmail: row.ActorEmail,
+		TargetId:   row.TargetID,
+		Ip:         row.IP,
+		UserAgent:  row.UserAgent,
+		RequestId:  row.RequestID,
+		Reason:     row.Reason,
+		CreatedAt:  row.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ListAuditEvents pages through the audit log of the caller's tenant, newest first.
+func (s *AuthServiceServer) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest) (*ListAuditEventsReply, error) {
+	pageSize, err := listPageSize(in.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	where := []db.AuditEventWhereParam{db.AuditEvent.TenantID.Equals(TenantID(ctx))}
+	if in.ActorId != "" {
+		where = append(where, db.AuditEvent.ActorID.Equals(in.ActorId))
+	}
+	if in.Action != "" {
+		where = append(where, db.AuditEvent.Action.Equals(in.Action))
+	}
+	if in.Outcome != "" {
+		where = append(where, db.AuditEvent.Outcome.Equals(in.Outcome))
+	}
+	for _, bound := range []struct {
+		name  string
+		value string
+		param func(time.Time) db.AuditEventWhereParam
+	}{
+		{"since", in.Since, func(t time.Time) db.AuditEventWhereParam { return db.AuditEvent.CreatedAt.Gte(t) }},
+		{"until", in.Until, func(t time.Time) db.AuditEventWhereParam { return db.AuditEvent.CreatedAt.Lt(t) }},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be an RFC 3339 timestamp", bound.name)
+		}
+		where = append(where, bound.param(t))
+	}
+
+	// One more event than asked tells whether there is a next page.
+	query := s.PrismaClient.AuditEvent.FindMany(where...).OrderBy(
+		db.AuditEvent.CreatedAt.Order(db.SortOrderDesc),
+		db.AuditEvent.ID.Order(db.SortOrderDesc),
+	).Take(pageSize + 1)
+	if in.PageToken != "" {
+		query = query.Cursor(db.AuditEvent.ID.Cursor(in.PageToken)).Skip(1)
+	}
+	rows, err := query.Exec(ctx)
+	if err != nil {
+		s.Logger.Errorw("Failed to list audit events", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not list audit events")
+	}
+
+	reply := &ListAuditEventsReply{Events: make([]*AuditEvent, 0, min(len(rows), pageSize))}
+	for i := range rows {
+		if i == pageSize {
+			reply.NextPageToken = rows[i-1].ID
+			break
+		}
+		reply.Events = append(reply.Events, auditEventToProto(&rows[i]))
+	}
+	return reply, nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"audit"
+	"context"
+	"db"
+	. "generated"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// useAuditStore records audit events into a memory store for the duration of the test.
+// The returned function flushes the recorder and returns the events.
+func useAuditStore(t *testing.T) func() []audit.Event {
+	t.Helper()
+	store := audit.NewMemoryStore()
+	r := audit.NewRecorder(store)
+	audit.SetRecorder(r)
+	t.Cleanup(func() { audit.SetRecorder(nil) })
+	return func() []audit.Event {
+		require.NoError(t, r.Close(context.Background()))
+		return store.Events()
+	}
+}
+
+func TestAuditRequest(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"grpcgateway-user-agent", "Mozilla/5.0",
+		RequestIDHeader, "req-1",
+	))
+	ctx = WithClientIP(WithTenantID(ctx, "acme"), "203.0.113.7")
+	ctx = WithPrincipal(ctx, NewPrincipal(NewClaims("audit@test.com", WithSubject("user-1"))))
+
+	assert.Equal(t, audit.Request{
+		ActorID:    "user-1",
+		ActorEmail: "audit@test.com",
+		TenantID:   "acme",
+		IP:         "203.0.113.7",
+		UserAgent:  "Mozilla/5.0",
+		RequestID:  "req-1",
+	}, AuditRequest(ctx))
+}
+
+func TestLoginFailureIsAudited(t *testing.T) {
+	useLoginAttemptStore(t, NewMemoryLoginAttemptStore())
+	events := useAuditStore(t)
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.User.Expect(
+		client.User.FindUnique(Scope(context.Background(), client).UserByEmail("nobody@test.com")),
+	).Errors(db.ErrNotFound)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	ctx := audit.WithRequest(context.Background(), audit.Request{IP: "203.0.113.7"})
+	_, err := s.Login(ctx, &LoginRequest{Email: "nobody@test.com", Password: "password"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	recorded := events()
+	require.Len(t, recorded, 1)
+	assert.Equal(t, audit.ActionLogin, recorded[0].Action)
+	assert.Equal(t, audit.OutcomeFailure, recorded[0].Outcome)
+	assert.Equal(t, "nobody@test.com", recorded[0].ActorEmail)
+	assert.Equal(t, "unknown user", recorded[0].Reason)
+	assert.Equal(t, "203.0.113.7", recorded[0].IP)
+}
+
+func TestListAuditEvents(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := WithTenantID(context.Background(), "acme")
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []db.AuditEventModel{
+		{InnerAuditEvent: db.InnerAuditEvent{ID: "event-3", Action: audit.ActionLogin, Outcome: "failure", CreatedAt: since.Add(3 * time.Hour)}},
+		{InnerAuditEvent: db.InnerAuditEvent{ID: "event-2", Action: audit.ActionLogin, Outcome: "failure", CreatedAt: since.Add(2 * time.Hour)}},
+		{InnerAuditEvent: db.InnerAuditEvent{ID: "event-1", Action: audit.ActionLogin, Outcome: "failure", CreatedAt: since.Add(time.Hour)}},
+	}
+	mock.AuditEvent.Expect(
+		client.AuditEvent.FindMany(
+			db.AuditEvent.TenantID.Equals("acme"),
+			db.AuditEvent.Outcome.Equals("failure"),
+			db.AuditEvent.CreatedAt.Gte(since),
+		).OrderBy(
+			db.AuditEvent.CreatedAt.Order(db.SortOrderDesc),
+			db.AuditEvent.ID.Order(db.SortOrderDesc),
+		).Take(3).Cursor(db.AuditEvent.ID.Cursor("event-4")).Skip(1),
+	).ReturnsMany(rows)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	reply, err := s.ListAuditEvents(ctx, &ListAuditEventsRequest{
+		Outcome:   "failure",
+		Since:     since.Format(time.RFC3339),
+		PageSize:  2,
+		PageToken: "event-4",
+	})
+	require.NoError(t, err)
+	require.Len(t, reply.Events, 2)
+	assert.Equal(t, "event-3", reply.Events[0].Id)
+	assert.Equal(t, "event-2", reply.NextPageToken, "expected the last event of the page to continue from")
+}
+
+func TestListAuditEventsValidation(t *testing.T) {
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+
+	_, err := s.ListAuditEvents(context.Background(), &ListAuditEventsRequest{Since: "yesterday"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.ListAuditEvents(context.Background(), &ListAuditEventsRequest{PageSize: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -17,8 +17,8 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
-// User represents the payload structure for both registration and login.
-type User struct {
+// userPayload represents the payload structure for both registration and login.
+type userPayload struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 	Name     string `json:"name,omitempty"`
@@ -81,6 +81,7 @@ func TestContainers(t *testing.T) {
 		Env: map[string]string{
 			"DATABASE_URL": dbConnStr,
 			"JWT_SECRET":   "supersecret",
+			"MAILER":       "stdout",
 		},
 		Networks:   []string{networkName},
 		WaitingFor: wait.ForListeningPort("8080/tcp"),
@@ -135,14 +136,14 @@ func TestContainers(t *testing.T) {
 	registerURL := appURL + "/v1/auth/register"
 	loginURL := appURL + "/v1/auth/login"
 	protectedURL := appURL + "/v1/auth/protected"
-	registerPayload := User{
+	registerPayload := userPayload{
 		Email:    "newuser@example.com",
 		Password: "password123",
 		Name:     "John",
 		Surname:  "Doe",
 		Age:      30,
 	}
-	loginPayload := User{
+	loginPayload := userPayload{
 		Email:    "newuser@example.com",
 		Password: "password123",
 	}
@@ -184,7 +185,7 @@ func TestContainers(t *testing.T) {
 
 	// Test login with an incorrect password.
 	t.Run("Login with Wrong Password", func(t *testing.T) {
-		wrongLogin := User{
+		wrongLogin := userPayload{
 			Email:    "newuser@example.com",
 			Password: "wrongpassword",
 		}
@@ -1,14 +1,15 @@
 package services
 
 import (
+	"audit"
 	"context"
 	"db"
+	"errors"
 	"fmt"
 	"generated"
 	. "generated"
 
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -37,67 +38,136 @@ func (s *AuthServiceServer) SampleProtected(ctx context.Context, in *ProtectedRe
 func (s *AuthServiceServer) Login(ctx context.Context, in *LoginRequest) (*LoginReply, error) {
 	s.Logger.Infof("Login attempt for email: %s", in.Email)
 
-	user, err := s.PrismaClient.User.FindUnique(
-		db.User.Email.Equals(in.Email),
-	).Exec(ctx)
+	guard := newLoginGuard(ctx, in.Email)
+	if err := guard.check(ctx); err != nil {
+		s.Logger.Warnw("Login refused: too many failed attempts", "email", in.Email)
+		auditLoginFailure(ctx, "", in.Email, "too many failed attempts")
+		return nil, err
+	}
+
+	user, err := Scope(ctx, s.PrismaClient).FindUserByEmail(ctx, in.Email)
 
 	// Handle user not found (or any error retrieving the user).
 	if err != nil || user == nil {
 		s.Logger.Warnw("Login failed: user not found", "email", in.Email, "error", err)
+		s.recordLoginFailure(ctx, guard, in.Email)
+		auditLoginFailure(ctx, "", in.Email, "unknown user")
 		return nil, status.Errorf(codes.Unauthenticated, "incorrect email or password")
 	}
 
 	// Compare the stored hashed password with the password provided.
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(in.Password)); err != nil {
+	needsRehash, err := verifyPassword(user.Password, in.Password)
+	if err != nil {
 		s.Logger.Warnw("Invalid password attempt", "email", in.Email)
+		s.recordLoginFailure(ctx, guard, in.Email)
+		auditLoginFailure(ctx, user.ID, in.Email, "invalid password")
 		return nil, status.Errorf(codes.Unauthenticated, "Invalid credentials: %v", err)
 	}
+	if err := guard.succeed(ctx, in.Email); err != nil {
+		s.Logger.Warnw("Failed to clear failed logins", "email", in.Email, "error", err)
+	}
+
+	if err := s.refuseDisabled(ctx, user); err != nil {
+		return nil, err
+	}
+	if !user.EmailVerified && EmailVerificationMode() == EmailVerificationEnforce {
+		s.Logger.Warnw("Login refused: email not verified", "email", in.Email)
+		auditLoginFailure(ctx, user.ID, in.Email, "email not verified")
+		return nil, status.Errorf(codes.FailedPrecondition, "email address is not verified")
+	}
+	if needsRehash {
+		s.rehashPassword(ctx, user, in.Password)
+	}
+
+	if user.TotpEnabled {
+		reply, err := twoFactorChallenge(user)
+		if err != nil {
+			s.Logger.Errorw("Error generating two-factor challenge", "email", in.Email, "error", err)
+			return nil, status.Errorf(codes.Internal, "could not generate token: %v", err)
+		}
+		s.Logger.Infof("Two-factor challenge issued for email %s", in.Email)
+		return reply, nil
+	}
 
-	token, err := GenerateJWT(in.Email)
+	reply, _, err := s.issueTokens(ctx, user, "")
 	if err != nil {
 		s.Logger.Errorw("Error generating token", "email", in.Email, "error", err)
 		return nil, status.Errorf(codes.Internal, "could not generate token: %v", err)
 	}
 
 	s.Logger.Infof("Generated token for email %s", in.Email)
-	return &LoginReply{
-		Token: token,
-	}, nil
+	auditLoginSuccess(ctx, user)
+	return reply, nil
+}
+
+// rehashPassword replaces a stored hash made with another hasher or outdated parameters,
+// now that the password is known. Failing to do so does not fail the login.
+func (s *AuthServiceServer) rehashPassword(ctx context.Context, user *db.UserModel, password string) {
+	hashed, err := hashPassword(password)
+	if err != nil {
+		s.Logger.Warnw("Failed to rehash password", "user", user.ID, "error", err)
+		return
+	}
+	if _, err := Scope(ctx, s.PrismaClient).UpdateUser(ctx, user, db.User.Password.Set(hashed)); err != nil {
+		s.Logger.Warnw("Failed to store rehashed password", "user", user.ID, "error", err)
+		return
+	}
+	s.Logger.Infow("Password rehashed", "user", user.ID)
 }
 
-// Register creates a new user after ensuring the email is unique and hashing the password.
+// Register creates a new user after ensuring the email is unique within the tenant and
+// hashing the password.
 func (s *AuthServiceServer) Register(ctx context.Context, in *RegisterRequest) (*RegisterReply, error) {
 	// Check if a user with the given email already exists.
 	s.Logger.Debugw("Register request received", "email", in.Email)
-	existingUser, err := s.PrismaClient.User.FindUnique(
-		db.User.Email.Equals(in.Email),
-	).Exec(ctx)
+	if err := currentPasswordPolicy().Check(in.Password); err != nil {
+		return nil, err
+	}
+	scope := Scope(ctx, s.PrismaClient)
+	existingUser, err := scope.FindUserByEmail(ctx, in.Email)
 	if err == nil && existingUser != nil {
 		s.Logger.Warnw("Registration failed: email already in use", "email", in.Email)
+		audit.Record(ctx, audit.Event{
+			Action:     audit.ActionRegister,
+			Outcome:    audit.OutcomeFailure,
+			ActorEmail: in.Email,
+			Reason:     "email already in use",
+		})
 		return nil, status.Errorf(codes.AlreadyExists, "failed to register user: email already in use")
 	}
 
-	const bcryptCost = 12 // Recommended: 12-14 for production
-	// Hash the password using bcrypt with the default cost.
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcryptCost)
+	hashedPassword, err := hashPassword(in.Password)
 	if err != nil {
 		s.Logger.Errorw("Failed to hash password", "email", in.Email, "error", err)
 		return nil, status.Errorf(codes.Internal, "failed to register user: %v", err)
 	}
 
-	obj, err := s.PrismaClient.User.CreateOne(
-		db.User.Name.Set(in.Name),
-		// Store the hashed password instead of plaintext.
-		db.User.Password.Set(string(hashedPassword)),
-		db.User.Email.Set(in.Email),
-		db.User.Age.Set(int(in.Age)),
-	).Exec(ctx)
+	// Store the hashed password instead of plaintext.
+	obj, err := scope.CreateUser(ctx, in.Name, in.Email, hashedPassword, int(in.Age),
+		db.User.Surname.Set(in.Surname),
+	)
+	if errors.Is(err, db.ErrNotFound) {
+		s.Logger.Warnw("Registration failed: unknown tenant", "tenant", scope.TenantID())
+		return nil, status.Errorf(codes.NotFound, "failed to register user: unknown tenant")
+	}
 	if err != nil {
 		s.Logger.Errorw("Failed to create user", "email", in.Email, "error", err)
 		return nil, status.Errorf(codes.Internal, "failed to register user: %v", err)
 	}
 
+	// The account is usable without it in flag mode, so a failure here is not fatal;
+	// the user can ask for a new mail through ResendVerification.
+	if err := s.sendVerification(ctx, obj); err != nil {
+		s.Logger.Warnw("Failed to send verification", "email", obj.Email, "error", err)
+	}
+
 	s.Logger.Infow("User registered successfully", "email", obj.Email)
+	audit.Record(ctx, audit.Event{
+		Action:     audit.ActionRegister,
+		Outcome:    audit.OutcomeSuccess,
+		ActorID:    obj.ID,
+		ActorEmail: obj.Email,
+	})
 	return &RegisterReply{
 		Reply: fmt.Sprintf("Congratulations, User email: %s got created!", obj.Email),
 	}, nil
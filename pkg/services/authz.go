@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+	. "generated"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// authRules caches the rule of every method looked up so far, keyed by full method name.
+var authRules sync.Map
+
+// MethodAuthRule returns the (thunder.auth) rule declared on a gRPC method, given its
+// full name ("/package.Service/Method"). Methods without a rule, or that are unknown to
+// the protobuf registry, get an empty rule, which requires an authenticated caller.
+func MethodAuthRule(fullMethod string) *AuthRule {
+	if rule, ok := authRules.Load(fullMethod); ok {
+		return rule.(*AuthRule)
+	}
+	rule := lookupAuthRule(fullMethod)
+	authRules.Store(fullMethod, rule)
+	return rule
+}
+
+// lookupAuthRule reads the rule from the method descriptor in the global registry.
+func lookupAuthRule(fullMethod string) *AuthRule {
+	name := strings.Replace(strings.TrimPrefix(fullMethod, "/"), "/", ".", 1)
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return &AuthRule{}
+	}
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok || method.Options() == nil {
+		return &AuthRule{}
+	}
+	rule, ok := proto.GetExtension(method.Options(), E_Auth).(*AuthRule)
+	if !ok || rule == nil {
+		return &AuthRule{}
+	}
+	return rule
+}
+
+// Authorize checks that the caller authenticated with a credential accepted by the rule
+// and holds one of the roles it requires.
+func Authorize(p *Principal, rule *AuthRule) error {
+	if credentials := rule.GetCredentials(); len(credentials) > 0 && !slices.Contains(credentials, string(p.Method)) {
+		return fmt.Errorf("%s credentials are not accepted", p.Method)
+	}
+	if len(rule.GetRoles()) == 0 {
+		return nil
+	}
+	for _, role := range rule.GetRoles() {
+		if p.HasRole(role) {
+			return nil
+		}
+	}
+	return fmt.Errorf("requires one of the roles %v", rule.GetRoles())
+}
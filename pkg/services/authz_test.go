@@ -0,0 +1,40 @@
+package services
+
+import (
+	. "generated"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethodAuthRule(t *testing.T) {
+	assert.True(t, MethodAuthRule("/authenticator.Auth/Login").GetPublic(), "expected Login to be public")
+	assert.True(t, MethodAuthRule("/authenticator.Auth/RefreshToken").GetPublic(), "expected RefreshToken to be public")
+	assert.False(t, MethodAuthRule("/authenticator.Auth/SampleProtected").GetPublic(), "expected SampleProtected to require a token")
+	assert.False(t, MethodAuthRule("/unknown.Service/Method").GetPublic(), "expected unknown methods to require a token")
+}
+
+func TestAuthorize(t *testing.T) {
+	admin := NewPrincipal(NewClaims("admin@test.com", WithRoles("admin")))
+	user := NewPrincipal(NewClaims("user@test.com"))
+
+	assert.NoError(t, Authorize(user, &AuthRule{}), "expected rules without roles to allow any user")
+	assert.NoError(t, Authorize(admin, &AuthRule{Roles: []string{"support", "admin"}}))
+	assert.Error(t, Authorize(user, &AuthRule{Roles: []string{"admin"}}), "expected missing role to be rejected")
+
+	certificate := &Principal{UserID: "billing-service", Roles: []string{"billing"}, Method: AuthMethodCertificate}
+	certificateOnly := &AuthRule{Roles: []string{"billing"}, Credentials: []string{"certificate"}}
+	assert.NoError(t, Authorize(certificate, certificateOnly))
+	assert.Error(t, Authorize(NewPrincipal(NewClaims("billing@test.com", WithRoles("billing"))), certificateOnly), "expected tokens to be rejected where only certificates are accepted")
+	assert.Error(t, Authorize(certificate, &AuthRule{Credentials: []string{"jwt", "api_key"}}), "expected certificates to be rejected where only tokens are accepted")
+}
+
+func TestRolesRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	token, err := GenerateJWT("roles@test.com", WithRoles("admin", "support"))
+	require.NoError(t, err)
+	claims, err := VerifyJWT(token)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "support"}, claims.Roles, "expected roles to be embedded in the token")
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// CertificateRule maps client certificates to a principal. Exactly one of SAN and CN is
+// set; both are glob patterns (path.Match), SAN being matched against the DNS, URI and
+// email SANs of the certificate.
+type CertificateRule struct {
+	SAN string `json:"san,omitempty"`
+	CN  string `json:"cn,omitempty"`
+	// UserID of the principal; the matched name when empty.
+	UserID string   `json:"user_id,omitempty"`
+	Email  string   `json:"email,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+	// TenantID of the principal; empty for callers outside of tenants.
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// matches returns the name of the certificate matched by the rule.
+func (r CertificateRule) matches(cert *x509.Certificate) (string, bool) {
+	if r.CN != "" {
+		if ok, _ := path.Match(r.CN, cert.Subject.CommonName); ok {
+			return cert.Subject.CommonName, true
+		}
+		return "", false
+	}
+	names := append([]string{}, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	for _, name := range names {
+		if ok, _ := path.Match(r.SAN, name); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// CertificateAuthenticator authenticates callers by their verified client certificate.
+type CertificateAuthenticator struct {
+	rules []CertificateRule
+	// excluded holds the SHA-256 fingerprints of certificates that never authenticate.
+	excluded map[[sha256.Size]byte]bool
+}
+
+// NewCertificateAuthenticator validates the rules; the first rule that matches a
+// certificate wins.
+func NewCertificateAuthenticator(rules []CertificateRule) (*CertificateAuthenticator, error) {
+	for i, rule := range rules {
+		pattern := rule.SAN
+		if (rule.SAN == "") == (rule.CN == "") {
+			return nil, fmt.Errorf("certificate rule %d: exactly one of san and cn is required", i)
+		}
+		if rule.CN != "" {
+			pattern = rule.CN
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("certificate rule %d: invalid pattern %q", i, pattern)
+		}
+	}
+	return &CertificateAuthenticator{rules: rules, excluded: make(map[[sha256.Size]byte]bool)}, nil
+}
+
+// Exclude keeps the certificate, given in DER, from authenticating anyone, whatever the
+// rules. The gateways present theirs on behalf of every REST and GraphQL caller, who
+// must authenticate with a token instead. It must be called before serving requests.
+func (a *CertificateAuthenticator) Exclude(der []byte) {
+	a.excluded[sha256.Sum256(der)] = true
+}
+
+// Authenticate returns the principal of a verified certificate, or false when no rule
+// matches it or it was excluded.
+func (a *CertificateAuthenticator) Authenticate(cert *x509.Certificate) (*Principal, bool) {
+	fingerprint := sha256.Sum256(cert.Raw)
+	if a.excluded[fingerprint] {
+		return nil, false
+	}
+	for _, rule := range a.rules {
+		name, ok := rule.matches(cert)
+		if !ok {
+			continue
+		}
+		userID := rule.UserID
+		if userID == "" {
+			userID = name
+		}
+		return &Principal{
+			UserID:    userID,
+			Email:     rule.Email,
+			Roles:     rule.Roles,
+			TenantID:  rule.TenantID,
+			Method:    AuthMethodCertificate,
+			TokenID:   hex.EncodeToString(fingerprint[:]),
+			ExpiresAt: cert.NotAfter,
+		}, true
+	}
+	return nil, false
+}
+
+var (
+	certificateAuthMu sync.RWMutex
+	certificateAuth   *CertificateAuthenticator
+)
+
+// SetCertificateAuthenticator enables client certificate authentication; nil disables it.
+func SetCertificateAuthenticator(a *CertificateAuthenticator) {
+	certificateAuthMu.Lock()
+	defer certificateAuthMu.Unlock()
+	certificateAuth = a
+}
+
+// CertificatePrincipal returns the principal of the client certificate the gRPC peer
+// presented. Only certificates verified during the handshake are considered.
+func CertificatePrincipal(ctx context.Context) (*Principal, bool) {
+	certificateAuthMu.RLock()
+	a := certificateAuth
+	certificateAuthMu.RUnlock()
+	if a == nil {
+		return nil, false
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return a.Authenticate(info.State.VerifiedChains[0][0])
+}
+
+// LoadCertificateAuthenticatorFromEnv reads the rules from the JSON file named by
+// CLIENT_CERT_RULES_FILE, a list of CertificateRule. It returns nil, leaving certificate
+// authentication off, when the variable is unset.
+func LoadCertificateAuthenticatorFromEnv() (*CertificateAuthenticator, error) {
+	file := os.Getenv("CLIENT_CERT_RULES_FILE")
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate rules: %v", err)
+	}
+	var rules []CertificateRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse certificate rules: %v", err)
+	}
+	return NewCertificateAuthenticator(rules)
+}
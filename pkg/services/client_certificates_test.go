@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// newClientCertificate returns a self-signed client certificate.
+func newClientCertificate(t *testing.T, cn string, uris ...string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, raw := range uris {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		template.URIs = append(template.URIs, u)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestNewCertificateAuthenticatorValidatesRules(t *testing.T) {
+	_, err := NewCertificateAuthenticator([]CertificateRule{{}})
+	assert.Error(t, err, "expected a rule without pattern to be rejected")
+	_, err = NewCertificateAuthenticator([]CertificateRule{{SAN: "a", CN: "b"}})
+	assert.Error(t, err, "expected a rule with both patterns to be rejected")
+	_, err = NewCertificateAuthenticator([]CertificateRule{{CN: "[invalid"}})
+	assert.Error(t, err, "expected an invalid pattern to be rejected")
+}
+
+func TestCertificateAuthenticator(t *testing.T) {
+	auth, err := NewCertificateAuthenticator([]CertificateRule{
+		{SAN: "spiffe://internal/billing/*", Roles: []string{"billing"}},
+		{CN: "ops-*", UserID: "ops", Roles: []string{"admin"}},
+	})
+	require.NoError(t, err)
+
+	billing := newClientCertificate(t, "billing", "spiffe://internal/billing/worker")
+	p, ok := auth.Authenticate(billing)
+	require.True(t, ok)
+	assert.Equal(t, "spiffe://internal/billing/worker", p.UserID, "expected the matched SAN to name the principal")
+	assert.Equal(t, AuthMethodCertificate, p.Method)
+	assert.True(t, p.HasRole("billing"))
+	assert.Len(t, p.TokenID, 64)
+
+	p, ok = auth.Authenticate(newClientCertificate(t, "ops-laptop"))
+	require.True(t, ok)
+	assert.Equal(t, "ops", p.UserID)
+	assert.True(t, p.HasRole("admin"))
+
+	_, ok = auth.Authenticate(newClientCertificate(t, "unknown", "spiffe://internal/payments/worker"))
+	assert.False(t, ok, "expected certificates without a matching rule to be rejected")
+}
+
+func TestCertificateAuthenticatorExclude(t *testing.T) {
+	auth, err := NewCertificateAuthenticator([]CertificateRule{{SAN: "*"}, {CN: "*"}})
+	require.NoError(t, err)
+	gateway := newClientCertificate(t, "localhost")
+	other := newClientCertificate(t, "localhost")
+
+	auth.Exclude(gateway.Raw)
+	_, ok := auth.Authenticate(gateway)
+	assert.False(t, ok, "expected the gateway certificate never to authenticate")
+	_, ok = auth.Authenticate(other)
+	assert.True(t, ok, "expected other certificates to still match the rules")
+}
+
+func TestCertificatePrincipal(t *testing.T) {
+	auth, err := NewCertificateAuthenticator([]CertificateRule{{CN: "worker", Roles: []string{"billing"}}})
+	require.NoError(t, err)
+	SetCertificateAuthenticator(auth)
+	t.Cleanup(func() { SetCertificateAuthenticator(nil) })
+
+	cert := newClientCertificate(t, "worker")
+	withState := func(state tls.ConnectionState) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+
+	p, ok := CertificatePrincipal(withState(tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}))
+	require.True(t, ok)
+	assert.Equal(t, "worker", p.UserID)
+
+	_, ok = CertificatePrincipal(withState(tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}))
+	assert.False(t, ok, "expected unverified certificates to be ignored")
+
+	_, ok = CertificatePrincipal(context.Background())
+	assert.False(t, ok)
+}
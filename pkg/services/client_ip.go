@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/peer"
+)
+
+type clientIPKey struct{}
+
+// WithClientIP returns a context carrying the address of the client that made the
+// request. Middleware that resolves proxy headers stores the result here.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP returns the address of the client that made the request: the one stored by
+// WithClientIP, or the address of the gRPC peer. It returns "" when neither is known.
+func ClientIP(ctx context.Context) string {
+	if ip, ok := ctx.Value(clientIPKey{}).(string); ok && ip != "" {
+		return ip
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"db"
+	"errors"
+	"fmt"
+	. "generated"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Values of EMAIL_VERIFICATION_MODE.
+const (
+	// EmailVerificationFlag lets unverified users log in; their tokens carry
+	// email_verified=false. This is the default.
+	EmailVerificationFlag = "flag"
+	// EmailVerificationEnforce makes Login refuse unverified users.
+	EmailVerificationEnforce = "enforce"
+)
+
+// defaultEmailVerificationTTL is the lifetime of verification tokens when
+// EMAIL_VERIFICATION_TOKEN_TTL is unset.
+const defaultEmailVerificationTTL = 24 * time.Hour
+
+// resendVerificationReply is returned whether or not the email belongs to a user.
+const resendVerificationReply = "If the email is registered and not yet verified, a verification link has been sent."
+
+// EmailVerificationMode returns how Login treats unverified users, configured through
+// the EMAIL_VERIFICATION_MODE environment variable.
+func EmailVerificationMode() string {
+	if os.Getenv("EMAIL_VERIFICATION_MODE") == EmailVerificationEnforce {
+		return EmailVerificationEnforce
+	}
+	return EmailVerificationFlag
+}
+
+// EmailVerificationTTL returns the verification token lifetime, configurable through
+// the EMAIL_VERIFICATION_TOKEN_TTL environment variable (e.g. "48h").
+func EmailVerificationTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_TOKEN_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultEmailVerificationTTL
+}
+
+// emailVerificationMessage builds the verification mail. The token is appended to
+// EMAIL_VERIFICATION_URL when it is set.
+func emailVerificationMessage(email, token string) Message {
+	link := token
+	if base := os.Getenv("EMAIL_VERIFICATION_URL"); base != "" {
+		link = fmt.Sprintf("%s?token=%s", base, token)
+	}
+	return Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Use the following link to verify your email address:\n\n%s\n\n"+
+			"It expires in %s.", link, EmailVerificationTTL()),
+	}
+}
+
+// sendVerification stores a new verification token for the user and mails it.
+func (s *AuthServiceServer) sendVerification(ctx context.Context, user *db.UserModel) error {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if _, err := s.PrismaClient.EmailVerificationToken.CreateOne(
+		db.EmailVerificationToken.TokenHash.Set(hash),
+		db.EmailVerificationToken.User.Link(db.User.ID.Equals(user.ID)),
+		db.EmailVerificationToken.ExpiresAt.Set(time.Now().Add(EmailVerificationTTL())),
+	).Exec(ctx); err != nil {
+		return fmt.Errorf("could not store verification token: %v", err)
+	}
+	s.sendMail(emailVerificationMessage(user.Email, raw))
+	return nil
+}
+
+// VerifyEmail marks the email address of a user as verified using a token from the
+// verification mail.
+func (s *AuthServiceServer) VerifyEmail(ctx context.Context, in *VerifyEmailRequest) (*VerifyEmailReply, error) {
+	if in.Token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token is required")
+	}
+
+	stored, err := s.PrismaClient.EmailVerificationToken.FindUnique(
+		db.EmailVerificationToken.TokenHash.Equals(hashToken(in.Token)),
+	).With(
+		db.EmailVerificationToken.User.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			s.Logger.Warnw("Email verification failed: unknown token")
+			return nil, status.Errorf(codes.InvalidArgument, "invalid or expired verification token")
+		}
+		s.Logger.Errorw("Failed to look up verification token", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not verify email")
+	}
+	user := stored.User()
+	// Tokens are only accepted within the tenant they were issued in.
+	scope := Scope(ctx, s.PrismaClient)
+	if err := scope.Own(user); err != nil {
+		s.Logger.Warnw("Email verification failed: other tenant", "user", user.ID)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired verification token")
+	}
+	if _, used := stored.UsedAt(); used || time.Now().After(stored.ExpiresAt) {
+		s.Logger.Warnw("Email verification failed: token used or expired", "user", stored.UserID)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired verification token")
+	}
+
+	// Consume every outstanding token of the user; the address only needs verifying once.
+	if _, err := s.PrismaClient.EmailVerificationToken.FindMany(
+		db.EmailVerificationToken.UserID.Equals(stored.UserID),
+		db.EmailVerificationToken.UsedAt.IsNull(),
+	).Update(
+		db.EmailVerificationToken.UsedAt.Set(time.Now()),
+	).Exec(ctx); err != nil {
+		s.Logger.Errorw("Failed to consume verification tokens", "user", stored.UserID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not verify email")
+	}
+	if _, err := scope.UpdateUser(ctx, user, db.User.EmailVerified.Set(true)); err != nil {
+		s.Logger.Errorw("Failed to mark email as verified", "user", stored.UserID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not verify email")
+	}
+
+	s.Logger.Infow("Email verified", "user", stored.UserID)
+	return &VerifyEmailReply{Reply: "Email address verified"}, nil
+}
+
+// ResendVerification mails a new verification token to an unverified user. The reply is
+// the same for unknown or already verified addresses.
+func (s *AuthServiceServer) ResendVerification(ctx context.Context, in *ResendVerificationRequest) (*ResendVerificationReply, error) {
+	reply := &ResendVerificationReply{Reply: resendVerificationReply}
+
+	user, err := Scope(ctx, s.PrismaClient).FindUserByEmail(ctx, in.Email)
+	if err != nil {
+		if !errors.Is(err, db.ErrNotFound) {
+			s.Logger.Errorw("Failed to look up user for verification", "error", err)
+			return nil, status.Errorf(codes.Internal, "could not resend verification")
+		}
+		s.Logger.Infow("Verification requested for unknown email")
+		return reply, nil
+	}
+	if user.EmailVerified {
+		return reply, nil
+	}
+
+	if err := s.sendVerification(ctx, user); err != nil {
+		s.Logger.Errorw("Failed to send verification", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not resend verification")
+	}
+	return reply, nil
+}
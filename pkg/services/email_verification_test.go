@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"db"
+	. "generated"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEmailVerificationMode(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_MODE", "")
+	assert.Equal(t, EmailVerificationFlag, EmailVerificationMode())
+
+	t.Setenv("EMAIL_VERIFICATION_MODE", "enforce")
+	assert.Equal(t, EmailVerificationEnforce, EmailVerificationMode())
+}
+
+func TestLoginRefusesUnverifiedEmail(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_MODE", "enforce")
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	mock.User.Expect(
+		client.User.FindUnique(
+			Scope(context.Background(), client).UserByEmail("unverified@test.com"),
+		),
+	).Returns(db.UserModel{
+		InnerUser: db.InnerUser{ID: "user-1", Email: "unverified@test.com", Password: string(hashed)},
+	})
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err = s.Login(context.Background(), &LoginRequest{Email: "unverified@test.com", Password: "password"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "expected unverified users to be refused in enforce mode")
+}
+
+func TestVerifyEmailUnknownToken(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.EmailVerificationToken.Expect(
+		client.EmailVerificationToken.FindUnique(
+			db.EmailVerificationToken.TokenHash.Equals(hashToken("unknown")),
+		).With(
+			db.EmailVerificationToken.User.Fetch(),
+		),
+	).Errors(db.ErrNotFound)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err := s.VerifyEmail(context.Background(), &VerifyEmailRequest{Token: "unknown"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestResendVerificationAlreadyVerified(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.User.Expect(
+		client.User.FindUnique(
+			Scope(context.Background(), client).UserByEmail("verified@test.com"),
+		),
+	).Returns(db.UserModel{
+		InnerUser: db.InnerUser{ID: "user-1", Email: "verified@test.com", EmailVerified: true},
+	})
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	reply, err := s.ResendVerification(context.Background(), &ResendVerificationRequest{Email: "verified@test.com"})
+	require.NoError(t, err)
+	assert.Equal(t, resendVerificationReply, reply.Reply, "expected the same reply as for unverified addresses")
+}
@@ -11,346 +11,3754 @@ import (
 )
 
 var (
-	gql__type_RegisterRequest   *graphql.Object      // message RegisterRequest in authenticator.proto
-	gql__type_RegisterReply     *graphql.Object      // message RegisterReply in authenticator.proto
-	gql__type_ProtectedRequest  *graphql.Object      // message ProtectedRequest in authenticator.proto
-	gql__type_ProtectedReply    *graphql.Object      // message ProtectedReply in authenticator.proto
-	gql__type_LoginRequest      *graphql.Object      // message LoginRequest in authenticator.proto
-	gql__type_LoginReply        *graphql.Object      // message LoginReply in authenticator.proto
-	gql__input_RegisterRequest  *graphql.InputObject // message RegisterRequest in authenticator.proto
-	gql__input_RegisterReply    *graphql.InputObject // message RegisterReply in authenticator.proto
-	gql__input_ProtectedRequest *graphql.InputObject // message ProtectedRequest in authenticator.proto
-	gql__input_ProtectedReply   *graphql.InputObject // message ProtectedReply in authenticator.proto
-	gql__input_LoginRequest     *graphql.InputObject // message LoginRequest in authenticator.proto
-	gql__input_LoginReply       *graphql.InputObject // message LoginReply in authenticator.proto
+	gql__type_VerifyEmailRequest                 *graphql.Object      // message VerifyEmailRequest in authenticator.proto
+	gql__type_VerifyEmailReply                   *graphql.Object      // message VerifyEmailReply in authenticator.proto
+	gql__type_User                               *graphql.Object      // message User in authenticator.proto
+	gql__type_UpdateProfileRequest               *graphql.Object      // message UpdateProfileRequest in authenticator.proto
+	gql__type_UnlockUserRequest                  *graphql.Object      // message UnlockUserRequest in authenticator.proto
+	gql__type_UnlockUserReply                    *graphql.Object      // message UnlockUserReply in authenticator.proto
+	gql__type_SetUserRolesRequest                *graphql.Object      // message SetUserRolesRequest in authenticator.proto
+	gql__type_Session                            *graphql.Object      // message Session in authenticator.proto
+	gql__type_RevokeSessionRequest               *graphql.Object      // message RevokeSessionRequest in authenticator.proto
+	gql__type_RevokeSessionReply                 *graphql.Object      // message RevokeSessionReply in authenticator.proto
+	gql__type_RevokeOtherSessionsReply           *graphql.Object      // message RevokeOtherSessionsReply in authenticator.proto
+	gql__type_RevokeAllSessionsReply             *graphql.Object      // message RevokeAllSessionsReply in authenticator.proto
+	gql__type_RevokeAPIKeyRequest                *graphql.Object      // message RevokeAPIKeyRequest in authenticator.proto
+	gql__type_RevokeAPIKeyReply                  *graphql.Object      // message RevokeAPIKeyReply in authenticator.proto
+	gql__type_ResetPasswordRequest               *graphql.Object      // message ResetPasswordRequest in authenticator.proto
+	gql__type_ResetPasswordReply                 *graphql.Object      // message ResetPasswordReply in authenticator.proto
+	gql__type_ResendVerificationRequest          *graphql.Object      // message ResendVerificationRequest in authenticator.proto
+	gql__type_ResendVerificationReply            *graphql.Object      // message ResendVerificationReply in authenticator.proto
+	gql__type_RequestPasswordResetRequest        *graphql.Object      // message RequestPasswordResetRequest in authenticator.proto
+	gql__type_RequestPasswordResetReply          *graphql.Object      // message RequestPasswordResetReply in authenticator.proto
+	gql__type_RequestMagicLinkRequest            *graphql.Object      // message RequestMagicLinkRequest in authenticator.proto
+	gql__type_RequestMagicLinkReply              *graphql.Object      // message RequestMagicLinkReply in authenticator.proto
+	gql__type_RegisterRequest                    *graphql.Object      // message RegisterRequest in authenticator.proto
+	gql__type_RegisterReply                      *graphql.Object      // message RegisterReply in authenticator.proto
+	gql__type_RefreshTokenRequest                *graphql.Object      // message RefreshTokenRequest in authenticator.proto
+	gql__type_ProtectedRequest                   *graphql.Object      // message ProtectedRequest in authenticator.proto
+	gql__type_ProtectedReply                     *graphql.Object      // message ProtectedReply in authenticator.proto
+	gql__type_LogoutRequest                      *graphql.Object      // message LogoutRequest in authenticator.proto
+	gql__type_LogoutReply                        *graphql.Object      // message LogoutReply in authenticator.proto
+	gql__type_LoginVerifyRequest                 *graphql.Object      // message LoginVerifyRequest in authenticator.proto
+	gql__type_LoginRequest                       *graphql.Object      // message LoginRequest in authenticator.proto
+	gql__type_LoginReply                         *graphql.Object      // message LoginReply in authenticator.proto
+	gql__type_ListUsersRequest                   *graphql.Object      // message ListUsersRequest in authenticator.proto
+	gql__type_ListUsersReply                     *graphql.Object      // message ListUsersReply in authenticator.proto
+	gql__type_ListSessionsReply                  *graphql.Object      // message ListSessionsReply in authenticator.proto
+	gql__type_ListAuditEventsRequest             *graphql.Object      // message ListAuditEventsRequest in authenticator.proto
+	gql__type_ListAuditEventsReply               *graphql.Object      // message ListAuditEventsReply in authenticator.proto
+	gql__type_ListAPIKeysReply                   *graphql.Object      // message ListAPIKeysReply in authenticator.proto
+	gql__type_ImpersonateUserRequest             *graphql.Object      // message ImpersonateUserRequest in authenticator.proto
+	gql__type_ImpersonateUserReply               *graphql.Object      // message ImpersonateUserReply in authenticator.proto
+	gql__type_GetUserRequest                     *graphql.Object      // message GetUserRequest in authenticator.proto
+	gql__type_ForcePasswordResetRequest          *graphql.Object      // message ForcePasswordResetRequest in authenticator.proto
+	gql__type_ForcePasswordResetReply            *graphql.Object      // message ForcePasswordResetReply in authenticator.proto
+	gql__type_FinishWebAuthnRegistrationRequest  *graphql.Object      // message FinishWebAuthnRegistrationRequest in authenticator.proto
+	gql__type_FinishWebAuthnRegistrationReply    *graphql.Object      // message FinishWebAuthnRegistrationReply in authenticator.proto
+	gql__type_FinishWebAuthnLoginRequest         *graphql.Object      // message FinishWebAuthnLoginRequest in authenticator.proto
+	gql__type_EnrollTOTPReply                    *graphql.Object      // message EnrollTOTPReply in authenticator.proto
+	gql__type_EnableUserRequest                  *graphql.Object      // message EnableUserRequest in authenticator.proto
+	gql__type_DisableUserRequest                 *graphql.Object      // message DisableUserRequest in authenticator.proto
+	gql__type_DisableTOTPRequest                 *graphql.Object      // message DisableTOTPRequest in authenticator.proto
+	gql__type_DisableTOTPReply                   *graphql.Object      // message DisableTOTPReply in authenticator.proto
+	gql__type_DeleteAccountRequest               *graphql.Object      // message DeleteAccountRequest in authenticator.proto
+	gql__type_DeleteAccountReply                 *graphql.Object      // message DeleteAccountReply in authenticator.proto
+	gql__type_CreateAPIKeyRequest                *graphql.Object      // message CreateAPIKeyRequest in authenticator.proto
+	gql__type_CreateAPIKeyReply                  *graphql.Object      // message CreateAPIKeyReply in authenticator.proto
+	gql__type_ConsumeMagicLinkRequest            *graphql.Object      // message ConsumeMagicLinkRequest in authenticator.proto
+	gql__type_ConfirmTOTPRequest                 *graphql.Object      // message ConfirmTOTPRequest in authenticator.proto
+	gql__type_ConfirmTOTPReply                   *graphql.Object      // message ConfirmTOTPReply in authenticator.proto
+	gql__type_CompleteOAuthLoginRequest          *graphql.Object      // message CompleteOAuthLoginRequest in authenticator.proto
+	gql__type_ChangePasswordRequest              *graphql.Object      // message ChangePasswordRequest in authenticator.proto
+	gql__type_ChangePasswordReply                *graphql.Object      // message ChangePasswordReply in authenticator.proto
+	gql__type_BeginWebAuthnReply                 *graphql.Object      // message BeginWebAuthnReply in authenticator.proto
+	gql__type_BeginWebAuthnLoginRequest          *graphql.Object      // message BeginWebAuthnLoginRequest in authenticator.proto
+	gql__type_BeginOAuthLoginRequest             *graphql.Object      // message BeginOAuthLoginRequest in authenticator.proto
+	gql__type_BeginOAuthLoginReply               *graphql.Object      // message BeginOAuthLoginReply in authenticator.proto
+	gql__type_AuditEvent                         *graphql.Object      // message AuditEvent in authenticator.proto
+	gql__type_APIKey                             *graphql.Object      // message APIKey in authenticator.proto
+	gql__input_VerifyEmailRequest                *graphql.InputObject // message VerifyEmailRequest in authenticator.proto
+	gql__input_VerifyEmailReply                  *graphql.InputObject // message VerifyEmailReply in authenticator.proto
+	gql__input_User                              *graphql.InputObject // message User in authenticator.proto
+	gql__input_UpdateProfileRequest              *graphql.InputObject // message UpdateProfileRequest in authenticator.proto
+	gql__input_UnlockUserRequest                 *graphql.InputObject // message UnlockUserRequest in authenticator.proto
+	gql__input_UnlockUserReply                   *graphql.InputObject // message UnlockUserReply in authenticator.proto
+	gql__input_SetUserRolesRequest               *graphql.InputObject // message SetUserRolesRequest in authenticator.proto
+	gql__input_Session                           *graphql.InputObject // message Session in authenticator.proto
+	gql__input_RevokeSessionRequest              *graphql.InputObject // message RevokeSessionRequest in authenticator.proto
+	gql__input_RevokeSessionReply                *graphql.InputObject // message RevokeSessionReply in authenticator.proto
+	gql__input_RevokeOtherSessionsReply          *graphql.InputObject // message RevokeOtherSessionsReply in authenticator.proto
+	gql__input_RevokeAllSessionsReply            *graphql.InputObject // message RevokeAllSessionsReply in authenticator.proto
+	gql__input_RevokeAPIKeyRequest               *graphql.InputObject // message RevokeAPIKeyRequest in authenticator.proto
+	gql__input_RevokeAPIKeyReply                 *graphql.InputObject // message RevokeAPIKeyReply in authenticator.proto
+	gql__input_ResetPasswordRequest              *graphql.InputObject // message ResetPasswordRequest in authenticator.proto
+	gql__input_ResetPasswordReply                *graphql.InputObject // message ResetPasswordReply in authenticator.proto
+	gql__input_ResendVerificationRequest         *graphql.InputObject // message ResendVerificationRequest in authenticator.proto
+	gql__input_ResendVerificationReply           *graphql.InputObject // message ResendVerificationReply in authenticator.proto
+	gql__input_RequestPasswordResetRequest       *graphql.InputObject // message RequestPasswordResetRequest in authenticator.proto
+	gql__input_RequestPasswordResetReply         *graphql.InputObject // message RequestPasswordResetReply in authenticator.proto
+	gql__input_RequestMagicLinkRequest           *graphql.InputObject // message RequestMagicLinkRequest in authenticator.proto
+	gql__input_RequestMagicLinkReply             *graphql.InputObject // message RequestMagicLinkReply in authenticator.proto
+	gql__input_RegisterRequest                   *graphql.InputObject // message RegisterRequest in authenticator.proto
+	gql__input_RegisterReply                     *graphql.InputObject // message RegisterReply in authenticator.proto
+	gql__input_RefreshTokenRequest               *graphql.InputObject // message RefreshTokenRequest in authenticator.proto
+	gql__input_ProtectedRequest                  *graphql.InputObject // message ProtectedRequest in authenticator.proto
+	gql__input_ProtectedReply                    *graphql.InputObject // message ProtectedReply in authenticator.proto
+	gql__input_LogoutRequest                     *graphql.InputObject // message LogoutRequest in authenticator.proto
+	gql__input_LogoutReply                       *graphql.InputObject // message LogoutReply in authenticator.proto
+	gql__input_LoginVerifyRequest                *graphql.InputObject // message LoginVerifyRequest in authenticator.proto
+	gql__input_LoginRequest                      *graphql.InputObject // message LoginRequest in authenticator.proto
+	gql__input_LoginReply                        *graphql.InputObject // message LoginReply in authenticator.proto
+	gql__input_ListUsersRequest                  *graphql.InputObject // message ListUsersRequest in authenticator.proto
+	gql__input_ListUsersReply                    *graphql.InputObject // message ListUsersReply in authenticator.proto
+	gql__input_ListSessionsReply                 *graphql.InputObject // message ListSessionsReply in authenticator.proto
+	gql__input_ListAuditEventsRequest            *graphql.InputObject // message ListAuditEventsRequest in authenticator.proto
+	gql__input_ListAuditEventsReply              *graphql.InputObject // message ListAuditEventsReply in authenticator.proto
+	gql__input_ListAPIKeysReply                  *graphql.InputObject // message ListAPIKeysReply in authenticator.proto
+	gql__input_ImpersonateUserRequest            *graphql.InputObject // message ImpersonateUserRequest in authenticator.proto
+	gql__input_ImpersonateUserReply              *graphql.InputObject // message ImpersonateUserReply in authenticator.proto
+	gql__input_GetUserRequest                    *graphql.InputObject // message GetUserRequest in authenticator.proto
+	gql__input_ForcePasswordResetRequest         *graphql.InputObject // message ForcePasswordResetRequest in authenticator.proto
+	gql__input_ForcePasswordResetReply           *graphql.InputObject // message ForcePasswordResetReply in authenticator.proto
+	gql__input_FinishWebAuthnRegistrationRequest *graphql.InputObject // message FinishWebAuthnRegistrationRequest in authenticator.proto
+	gql__input_FinishWebAuthnRegistrationReply   *graphql.InputObject // message FinishWebAuthnRegistrationReply in authenticator.proto
+	gql__input_FinishWebAuthnLoginRequest        *graphql.InputObject // message FinishWebAuthnLoginRequest in authenticator.proto
+	gql__input_EnrollTOTPReply                   *graphql.InputObject // message EnrollTOTPReply in authenticator.proto
+	gql__input_EnableUserRequest                 *graphql.InputObject // message EnableUserRequest in authenticator.proto
+	gql__input_DisableUserRequest                *graphql.InputObject // message DisableUserRequest in authenticator.proto
+	gql__input_DisableTOTPRequest                *graphql.InputObject // message DisableTOTPRequest in authenticator.proto
+	gql__input_DisableTOTPReply                  *graphql.InputObject // message DisableTOTPReply in authenticator.proto
+	gql__input_DeleteAccountRequest              *graphql.InputObject // message DeleteAccountRequest in authenticator.proto
+	gql__input_DeleteAccountReply                *graphql.InputObject // message DeleteAccountReply in authenticator.proto
+	gql__input_CreateAPIKeyRequest               *graphql.InputObject // message CreateAPIKeyRequest in authenticator.proto
+	gql__input_CreateAPIKeyReply                 *graphql.InputObject // message CreateAPIKeyReply in authenticator.proto
+	gql__input_ConsumeMagicLinkRequest           *graphql.InputObject // message ConsumeMagicLinkRequest in authenticator.proto
+	gql__input_ConfirmTOTPRequest                *graphql.InputObject // message ConfirmTOTPRequest in authenticator.proto
+	gql__input_ConfirmTOTPReply                  *graphql.InputObject // message ConfirmTOTPReply in authenticator.proto
+	gql__input_CompleteOAuthLoginRequest         *graphql.InputObject // message CompleteOAuthLoginRequest in authenticator.proto
+	gql__input_ChangePasswordRequest             *graphql.InputObject // message ChangePasswordRequest in authenticator.proto
+	gql__input_ChangePasswordReply               *graphql.InputObject // message ChangePasswordReply in authenticator.proto
+	gql__input_BeginWebAuthnReply                *graphql.InputObject // message BeginWebAuthnReply in authenticator.proto
+	gql__input_BeginWebAuthnLoginRequest         *graphql.InputObject // message BeginWebAuthnLoginRequest in authenticator.proto
+	gql__input_BeginOAuthLoginRequest            *graphql.InputObject // message BeginOAuthLoginRequest in authenticator.proto
+	gql__input_BeginOAuthLoginReply              *graphql.InputObject // message BeginOAuthLoginReply in authenticator.proto
+	gql__input_AuditEvent                        *graphql.InputObject // message AuditEvent in authenticator.proto
+	gql__input_APIKey                            *graphql.InputObject // message APIKey in authenticator.proto
 )
 
-func Gql__type_RegisterRequest() *graphql.Object {
-	if gql__type_RegisterRequest == nil {
-		gql__type_RegisterRequest = graphql.NewObject(graphql.ObjectConfig{
-			Name: "Generated_Type_RegisterRequest",
+func Gql__type_VerifyEmailRequest() *graphql.Object {
+	if gql__type_VerifyEmailRequest == nil {
+		gql__type_VerifyEmailRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_VerifyEmailRequest",
+			Fields: graphql.Fields{
+				"token": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Token from the verification mail.`,
+				},
+			},
+		})
+	}
+	return gql__type_VerifyEmailRequest
+}
+
+func Gql__type_VerifyEmailReply() *graphql.Object {
+	if gql__type_VerifyEmailReply == nil {
+		gql__type_VerifyEmailReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_VerifyEmailReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_VerifyEmailReply
+}
+
+func Gql__type_User() *graphql.Object {
+	if gql__type_User == nil {
+		gql__type_User = graphql.NewObject(graphql.ObjectConfig{
+			Name:        "Generated_Type_User",
+			Description: `User is the account of the caller. The password hash never leaves the service.`,
 			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.String,
+				},
 				"email": &graphql.Field{
-					Type: graphql.NewNonNull(graphql.String),
+					Type: graphql.String,
 				},
-				"password": &graphql.Field{
-					Type: graphql.NewNonNull(graphql.String),
+				"email_verified": &graphql.Field{
+					Type: graphql.Boolean,
 				},
 				"name": &graphql.Field{
-					Type: graphql.NewNonNull(graphql.String),
+					Type: graphql.String,
 				},
 				"surname": &graphql.Field{
-					Type: graphql.NewNonNull(graphql.String),
+					Type: graphql.String,
 				},
 				"age": &graphql.Field{
-					Type: graphql.NewNonNull(graphql.Int),
+					Type: graphql.Int,
+				},
+				"desc": &graphql.Field{
+					Type: graphql.String,
+				},
+				"roles": &graphql.Field{
+					Type: graphql.NewList(graphql.String),
+				},
+				"totp_enabled": &graphql.Field{
+					Type: graphql.Boolean,
+				},
+				"created_at": &graphql.Field{
+					Type:        graphql.String,
+					Description: `RFC 3339 timestamps.`,
+				},
+				"updated_at": &graphql.Field{
+					Type: graphql.String,
+				},
+				"disabled": &graphql.Field{
+					Type:        graphql.Boolean,
+					Description: `Disabled users cannot log in; see UserAdmin.DisableUser.`,
 				},
 			},
 		})
 	}
-	return gql__type_RegisterRequest
+	return gql__type_User
 }
 
-func Gql__type_RegisterReply() *graphql.Object {
-	if gql__type_RegisterReply == nil {
-		gql__type_RegisterReply = graphql.NewObject(graphql.ObjectConfig{
-			Name: "Generated_Type_RegisterReply",
+func Gql__type_UpdateProfileRequest() *graphql.Object {
+	if gql__type_UpdateProfileRequest == nil {
+		gql__type_UpdateProfileRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_UpdateProfileRequest",
 			Fields: graphql.Fields{
-				"reply": &graphql.Field{
-					Type: graphql.String,
+				"user": &graphql.Field{
+					Type: graphql.NewNonNull(Gql__type_User()),
+				},
+				"update_mask": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Description: `Field mask: paths of the fields of user to update, out of name, surname, age
+ and desc. A plain list, since the GraphQL gateway has no FieldMask support.`,
 				},
 			},
 		})
 	}
-	return gql__type_RegisterReply
+	return gql__type_UpdateProfileRequest
 }
 
-func Gql__type_ProtectedRequest() *graphql.Object {
-	if gql__type_ProtectedRequest == nil {
-		gql__type_ProtectedRequest = graphql.NewObject(graphql.ObjectConfig{
-			Name: "Generated_Type_ProtectedRequest",
+func Gql__type_UnlockUserRequest() *graphql.Object {
+	if gql__type_UnlockUserRequest == nil {
+		gql__type_UnlockUserRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_UnlockUserRequest",
 			Fields: graphql.Fields{
-				"text": &graphql.Field{
-					Type: graphql.NewNonNull(graphql.String),
+				"email": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Email of the account whose failed login attempts are cleared.`,
 				},
 			},
 		})
 	}
-	return gql__type_ProtectedRequest
+	return gql__type_UnlockUserRequest
 }
 
-func Gql__type_ProtectedReply() *graphql.Object {
-	if gql__type_ProtectedReply == nil {
-		gql__type_ProtectedReply = graphql.NewObject(graphql.ObjectConfig{
-			Name: "Generated_Type_ProtectedReply",
+func Gql__type_UnlockUserReply() *graphql.Object {
+	if gql__type_UnlockUserReply == nil {
+		gql__type_UnlockUserReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_UnlockUserReply",
 			Fields: graphql.Fields{
-				"result": &graphql.Field{
+				"reply": &graphql.Field{
 					Type: graphql.String,
 				},
 			},
 		})
 	}
-	return gql__type_ProtectedReply
+	return gql__type_UnlockUserReply
 }
 
-func Gql__type_LoginRequest() *graphql.Object {
-	if gql__type_LoginRequest == nil {
-		gql__type_LoginRequest = graphql.NewObject(graphql.ObjectConfig{
-			Name: "Generated_Type_LoginRequest",
+func Gql__type_SetUserRolesRequest() *graphql.Object {
+	if gql__type_SetUserRolesRequest == nil {
+		gql__type_SetUserRolesRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_SetUserRolesRequest",
 			Fields: graphql.Fields{
-				"email": &graphql.Field{
+				"id": &graphql.Field{
 					Type: graphql.NewNonNull(graphql.String),
 				},
-				"password": &graphql.Field{
-					Type: graphql.NewNonNull(graphql.String),
+				"roles": &graphql.Field{
+					Type:        graphql.NewList(graphql.String),
+					Description: `Replaces the roles of the user.`,
 				},
 			},
 		})
 	}
-	return gql__type_LoginRequest
+	return gql__type_SetUserRolesRequest
 }
 
-func Gql__type_LoginReply() *graphql.Object {
-	if gql__type_LoginReply == nil {
-		gql__type_LoginReply = graphql.NewObject(graphql.ObjectConfig{
-			Name: "Generated_Type_LoginReply",
+func Gql__type_Session() *graphql.Object {
+	if gql__type_Session == nil {
+		gql__type_Session = graphql.NewObject(graphql.ObjectConfig{
+			Name:        "Generated_Type_Session",
+			Description: `Session is a login on a device. Refreshing the tokens of a login keeps its session.`,
 			Fields: graphql.Fields{
-				"token": &graphql.Field{
+				"id": &graphql.Field{
+					Type: graphql.String,
+				},
+				"user_agent": &graphql.Field{
+					Type: graphql.String,
+				},
+				"ip": &graphql.Field{
+					Type: graphql.String,
+				},
+				"created_at": &graphql.Field{
+					Type:        graphql.String,
+					Description: `RFC 3339 timestamps.`,
+				},
+				"last_seen_at": &graphql.Field{
 					Type: graphql.String,
 				},
+				"current": &graphql.Field{
+					Type:        graphql.Boolean,
+					Description: `Set for the session of the token used for the call.`,
+				},
 			},
 		})
 	}
-	return gql__type_LoginReply
+	return gql__type_Session
 }
 
-func Gql__input_RegisterRequest() *graphql.InputObject {
-	if gql__input_RegisterRequest == nil {
-		gql__input_RegisterRequest = graphql.NewInputObject(graphql.InputObjectConfig{
-			Name: "Generated_Input_RegisterRequest",
-			Fields: graphql.InputObjectConfigFieldMap{
-				"email": &graphql.InputObjectFieldConfig{
-					Type: graphql.NewNonNull(graphql.String),
-				},
-				"password": &graphql.InputObjectFieldConfig{
-					Type: graphql.NewNonNull(graphql.String),
-				},
-				"name": &graphql.InputObjectFieldConfig{
+func Gql__type_RevokeSessionRequest() *graphql.Object {
+	if gql__type_RevokeSessionRequest == nil {
+		gql__type_RevokeSessionRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RevokeSessionRequest",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
 					Type: graphql.NewNonNull(graphql.String),
 				},
-				"surname": &graphql.InputObjectFieldConfig{
-					Type: graphql.NewNonNull(graphql.String),
+			},
+		})
+	}
+	return gql__type_RevokeSessionRequest
+}
+
+func Gql__type_RevokeSessionReply() *graphql.Object {
+	if gql__type_RevokeSessionReply == nil {
+		gql__type_RevokeSessionReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RevokeSessionReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
 				},
-				"age": &graphql.InputObjectFieldConfig{
-					Type: graphql.NewNonNull(graphql.Int),
+			},
+		})
+	}
+	return gql__type_RevokeSessionReply
+}
+
+func Gql__type_RevokeOtherSessionsReply() *graphql.Object {
+	if gql__type_RevokeOtherSessionsReply == nil {
+		gql__type_RevokeOtherSessionsReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RevokeOtherSessionsReply",
+			Fields: graphql.Fields{
+				"revoked": &graphql.Field{
+					Type:        graphql.Int,
+					Description: `Number of sessions revoked.`,
 				},
 			},
 		})
 	}
-	return gql__input_RegisterRequest
+	return gql__type_RevokeOtherSessionsReply
 }
 
-func Gql__input_RegisterReply() *graphql.InputObject {
-	if gql__input_RegisterReply == nil {
-		gql__input_RegisterReply = graphql.NewInputObject(graphql.InputObjectConfig{
-			Name: "Generated_Input_RegisterReply",
-			Fields: graphql.InputObjectConfigFieldMap{
-				"reply": &graphql.InputObjectFieldConfig{
+func Gql__type_RevokeAllSessionsReply() *graphql.Object {
+	if gql__type_RevokeAllSessionsReply == nil {
+		gql__type_RevokeAllSessionsReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RevokeAllSessionsReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
 					Type: graphql.String,
 				},
 			},
 		})
 	}
-	return gql__input_RegisterReply
+	return gql__type_RevokeAllSessionsReply
 }
 
-func Gql__input_ProtectedRequest() *graphql.InputObject {
-	if gql__input_ProtectedRequest == nil {
-		gql__input_ProtectedRequest = graphql.NewInputObject(graphql.InputObjectConfig{
-			Name: "Generated_Input_ProtectedRequest",
-			Fields: graphql.InputObjectConfigFieldMap{
-				"text": &graphql.InputObjectFieldConfig{
+func Gql__type_RevokeAPIKeyRequest() *graphql.Object {
+	if gql__type_RevokeAPIKeyRequest == nil {
+		gql__type_RevokeAPIKeyRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RevokeAPIKeyRequest",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
 					Type: graphql.NewNonNull(graphql.String),
 				},
 			},
 		})
 	}
-	return gql__input_ProtectedRequest
+	return gql__type_RevokeAPIKeyRequest
 }
 
-func Gql__input_ProtectedReply() *graphql.InputObject {
-	if gql__input_ProtectedReply == nil {
-		gql__input_ProtectedReply = graphql.NewInputObject(graphql.InputObjectConfig{
-			Name: "Generated_Input_ProtectedReply",
-			Fields: graphql.InputObjectConfigFieldMap{
-				"result": &graphql.InputObjectFieldConfig{
+func Gql__type_RevokeAPIKeyReply() *graphql.Object {
+	if gql__type_RevokeAPIKeyReply == nil {
+		gql__type_RevokeAPIKeyReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RevokeAPIKeyReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
 					Type: graphql.String,
 				},
 			},
 		})
 	}
-	return gql__input_ProtectedReply
+	return gql__type_RevokeAPIKeyReply
 }
 
-func Gql__input_LoginRequest() *graphql.InputObject {
-	if gql__input_LoginRequest == nil {
-		gql__input_LoginRequest = graphql.NewInputObject(graphql.InputObjectConfig{
-			Name: "Generated_Input_LoginRequest",
-			Fields: graphql.InputObjectConfigFieldMap{
-				"email": &graphql.InputObjectFieldConfig{
-					Type: graphql.NewNonNull(graphql.String),
+func Gql__type_ResetPasswordRequest() *graphql.Object {
+	if gql__type_ResetPasswordRequest == nil {
+		gql__type_ResetPasswordRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ResetPasswordRequest",
+			Fields: graphql.Fields{
+				"token": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Token from the password reset mail.`,
 				},
-				"password": &graphql.InputObjectFieldConfig{
+				"new_password": &graphql.Field{
 					Type: graphql.NewNonNull(graphql.String),
 				},
 			},
 		})
 	}
-	return gql__input_LoginRequest
+	return gql__type_ResetPasswordRequest
 }
 
-func Gql__input_LoginReply() *graphql.InputObject {
-	if gql__input_LoginReply == nil {
-		gql__input_LoginReply = graphql.NewInputObject(graphql.InputObjectConfig{
-			Name: "Generated_Input_LoginReply",
-			Fields: graphql.InputObjectConfigFieldMap{
-				"token": &graphql.InputObjectFieldConfig{
+func Gql__type_ResetPasswordReply() *graphql.Object {
+	if gql__type_ResetPasswordReply == nil {
+		gql__type_ResetPasswordReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ResetPasswordReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
 					Type: graphql.String,
 				},
 			},
 		})
 	}
-	return gql__input_LoginReply
+	return gql__type_ResetPasswordReply
 }
 
-// graphql__resolver_Auth is a struct for making query, mutation and resolve fields.
-// This struct must be implemented runtime.SchemaBuilder interface.
-type graphql__resolver_Auth struct {
-
-	// Automatic connection host
-	host string
-
-	// grpc dial options
-	dialOptions []grpc.DialOption
+func Gql__type_ResendVerificationRequest() *graphql.Object {
+	if gql__type_ResendVerificationRequest == nil {
+		gql__type_ResendVerificationRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ResendVerificationRequest",
+			Fields: graphql.Fields{
+				"email": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_ResendVerificationRequest
+}
 
-	// grpc client connection.
-	// this connection may be provided by user
-	conn *grpc.ClientConn
+func Gql__type_ResendVerificationReply() *graphql.Object {
+	if gql__type_ResendVerificationReply == nil {
+		gql__type_ResendVerificationReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ResendVerificationReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_ResendVerificationReply
 }
 
-// new_graphql_resolver_Auth creates pointer of service struct
-func new_graphql_resolver_Auth(conn *grpc.ClientConn) *graphql__resolver_Auth {
-	return &graphql__resolver_Auth{
-		conn: conn,
-		host: "localhost:50051",
-		dialOptions: []grpc.DialOption{
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-		},
+func Gql__type_RequestPasswordResetRequest() *graphql.Object {
+	if gql__type_RequestPasswordResetRequest == nil {
+		gql__type_RequestPasswordResetRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RequestPasswordResetRequest",
+			Fields: graphql.Fields{
+				"email": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
 	}
+	return gql__type_RequestPasswordResetRequest
 }
 
-// CreateConnection() returns grpc connection which user specified or newly connected and closing function
-func (x *graphql__resolver_Auth) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
-	// If x.conn is not nil, user injected their own connection
-	if x.conn != nil {
-		return x.conn, func() {}, nil
+func Gql__type_RequestPasswordResetReply() *graphql.Object {
+	if gql__type_RequestPasswordResetReply == nil {
+		gql__type_RequestPasswordResetReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RequestPasswordResetReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
 	}
+	return gql__type_RequestPasswordResetReply
+}
 
-	// Otherwise, this handler opens connection with specified host
-	conn, err := grpc.DialContext(ctx, x.host, x.dialOptions...)
-	if err != nil {
-		return nil, nil, err
+func Gql__type_RequestMagicLinkRequest() *graphql.Object {
+	if gql__type_RequestMagicLinkRequest == nil {
+		gql__type_RequestMagicLinkRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RequestMagicLinkRequest",
+			Fields: graphql.Fields{
+				"email": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
 	}
-	return conn, func() { conn.Close() }, nil
+	return gql__type_RequestMagicLinkRequest
 }
 
-// GetQueries returns acceptable graphql.Fields for Query.
-func (x *graphql__resolver_Auth) GetQueries(conn *grpc.ClientConn) graphql.Fields {
-	return graphql.Fields{
-		"login": &graphql.Field{
-			Type: Gql__type_LoginReply(),
-			Args: graphql.FieldConfigArgument{
-				"email": &graphql.ArgumentConfig{
-					Type:         graphql.NewNonNull(graphql.String),
-					DefaultValue: "",
+func Gql__type_RequestMagicLinkReply() *graphql.Object {
+	if gql__type_RequestMagicLinkReply == nil {
+		gql__type_RequestMagicLinkReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RequestMagicLinkReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
 				},
-				"password": &graphql.ArgumentConfig{
-					Type:         graphql.NewNonNull(graphql.String),
-					DefaultValue: "",
+				"nonce": &graphql.Field{
+					Type: graphql.String,
+					Description: `Kept by the requesting device and sent with the token to ConsumeMagicLink, so that
+ the link only logs in the device that asked for it.`,
 				},
 			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				var req LoginRequest
-				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
-					return nil, errors.Wrap(err, "Failed to marshal request for login")
-				}
-				client := NewAuthClient(conn)
-				resp, err := client.Login(p.Context, &req)
-				if err != nil {
-					return nil, errors.Wrap(err, "Failed to call RPC Login")
-				}
-				return resp, nil
-			},
+		})
+	}
+	return gql__type_RequestMagicLinkReply
+}
+
+func Gql__type_RegisterRequest() *graphql.Object {
+	if gql__type_RegisterRequest == nil {
+		gql__type_RegisterRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RegisterRequest",
+			Fields: graphql.Fields{
+				"email": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"password": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"surname": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"age": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			},
+		})
+	}
+	return gql__type_RegisterRequest
+}
+
+func Gql__type_RegisterReply() *graphql.Object {
+	if gql__type_RegisterReply == nil {
+		gql__type_RegisterReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RegisterReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_RegisterReply
+}
+
+func Gql__type_RefreshTokenRequest() *graphql.Object {
+	if gql__type_RefreshTokenRequest == nil {
+		gql__type_RefreshTokenRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_RefreshTokenRequest",
+			Fields: graphql.Fields{
+				"refresh_token": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_RefreshTokenRequest
+}
+
+func Gql__type_ProtectedRequest() *graphql.Object {
+	if gql__type_ProtectedRequest == nil {
+		gql__type_ProtectedRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ProtectedRequest",
+			Fields: graphql.Fields{
+				"text": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_ProtectedRequest
+}
+
+func Gql__type_ProtectedReply() *graphql.Object {
+	if gql__type_ProtectedReply == nil {
+		gql__type_ProtectedReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ProtectedReply",
+			Fields: graphql.Fields{
+				"result": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_ProtectedReply
+}
+
+func Gql__type_LogoutRequest() *graphql.Object {
+	if gql__type_LogoutRequest == nil {
+		gql__type_LogoutRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_LogoutRequest",
+			Fields: graphql.Fields{
+				"refresh_token": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Optional refresh token whose family is revoked together with the access token.`,
+				},
+			},
+		})
+	}
+	return gql__type_LogoutRequest
+}
+
+func Gql__type_LogoutReply() *graphql.Object {
+	if gql__type_LogoutReply == nil {
+		gql__type_LogoutReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_LogoutReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_LogoutReply
+}
+
+func Gql__type_LoginVerifyRequest() *graphql.Object {
+	if gql__type_LoginVerifyRequest == nil {
+		gql__type_LoginVerifyRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_LoginVerifyRequest",
+			Fields: graphql.Fields{
+				"challenge_token": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Challenge token returned by Login.`,
+				},
+				"code": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Current TOTP code or an unused recovery code.`,
+				},
+			},
+		})
+	}
+	return gql__type_LoginVerifyRequest
+}
+
+func Gql__type_LoginRequest() *graphql.Object {
+	if gql__type_LoginRequest == nil {
+		gql__type_LoginRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_LoginRequest",
+			Fields: graphql.Fields{
+				"email": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"password": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_LoginRequest
+}
+
+func Gql__type_LoginReply() *graphql.Object {
+	if gql__type_LoginReply == nil {
+		gql__type_LoginReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_LoginReply",
+			Fields: graphql.Fields{
+				"token": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Short-lived access token (JWT).`,
+				},
+				"refresh_token": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Opaque, single-use token exchanged for a new token pair via RefreshToken.`,
+				},
+				"expires_in": &graphql.Field{
+					Type:        graphql.Int,
+					Description: `Lifetime of the access token in seconds.`,
+				},
+				"two_factor_required": &graphql.Field{
+					Type: graphql.Boolean,
+					Description: `Set when the user has two-factor authentication enabled. No tokens are
+ issued; the challenge token must be exchanged through LoginVerify.`,
+				},
+				"challenge_token": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_LoginReply
+}
+
+func Gql__type_ListUsersRequest() *graphql.Object {
+	if gql__type_ListUsersRequest == nil {
+		gql__type_ListUsersRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name:        "Generated_Type_ListUsersRequest",
+			Description: `ListUsersRequest filters the users of the caller's tenant. Empty filters match every user.`,
+			Fields: graphql.Fields{
+				"query": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Case-insensitive part of the email or name.`,
+				},
+				"role": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Only users holding the role.`,
+				},
+				"status": &graphql.Field{
+					Type:        graphql.String,
+					Description: `"active" or "disabled".`,
+				},
+				"order_by": &graphql.Field{
+					Type:        graphql.String,
+					Description: `"created_at" (default), "email" or "name".`,
+				},
+				"descending": &graphql.Field{
+					Type: graphql.Boolean,
+				},
+				"page_size": &graphql.Field{
+					Type:        graphql.Int,
+					Description: `Users per page, at most 100 (default 50).`,
+				},
+				"page_token": &graphql.Field{
+					Type:        graphql.String,
+					Description: `next_page_token of the previous page; the other fields must not change between pages.`,
+				},
+			},
+		})
+	}
+	return gql__type_ListUsersRequest
+}
+
+func Gql__type_ListUsersReply() *graphql.Object {
+	if gql__type_ListUsersReply == nil {
+		gql__type_ListUsersReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ListUsersReply",
+			Fields: graphql.Fields{
+				"users": &graphql.Field{
+					Type: graphql.NewList(Gql__type_User()),
+				},
+				"next_page_token": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Empty on the last page.`,
+				},
+			},
+		})
+	}
+	return gql__type_ListUsersReply
+}
+
+func Gql__type_ListSessionsReply() *graphql.Object {
+	if gql__type_ListSessionsReply == nil {
+		gql__type_ListSessionsReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ListSessionsReply",
+			Fields: graphql.Fields{
+				"sessions": &graphql.Field{
+					Type: graphql.NewList(Gql__type_Session()),
+				},
+			},
+		})
+	}
+	return gql__type_ListSessionsReply
+}
+
+func Gql__type_ListAuditEventsRequest() *graphql.Object {
+	if gql__type_ListAuditEventsRequest == nil {
+		gql__type_ListAuditEventsRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ListAuditEventsRequest",
+			Description: `ListAuditEventsRequest filters the events of the caller's tenant, newest first. Empty
+ filters match every event.`,
+			Fields: graphql.Fields{
+				"actor_id": &graphql.Field{
+					Type: graphql.String,
+				},
+				"action": &graphql.Field{
+					Type: graphql.String,
+				},
+				"outcome": &graphql.Field{
+					Type: graphql.String,
+				},
+				"since": &graphql.Field{
+					Type:        graphql.String,
+					Description: `RFC 3339 bounds of the event time; since is inclusive, until exclusive.`,
+				},
+				"until": &graphql.Field{
+					Type: graphql.String,
+				},
+				"page_size": &graphql.Field{
+					Type:        graphql.Int,
+					Description: `Events per page, at most 100 (default 50).`,
+				},
+				"page_token": &graphql.Field{
+					Type:        graphql.String,
+					Description: `next_page_token of the previous page.`,
+				},
+			},
+		})
+	}
+	return gql__type_ListAuditEventsRequest
+}
+
+func Gql__type_ListAuditEventsReply() *graphql.Object {
+	if gql__type_ListAuditEventsReply == nil {
+		gql__type_ListAuditEventsReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ListAuditEventsReply",
+			Fields: graphql.Fields{
+				"events": &graphql.Field{
+					Type: graphql.NewList(Gql__type_AuditEvent()),
+				},
+				"next_page_token": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Empty on the last page.`,
+				},
+			},
+		})
+	}
+	return gql__type_ListAuditEventsReply
+}
+
+func Gql__type_ListAPIKeysReply() *graphql.Object {
+	if gql__type_ListAPIKeysReply == nil {
+		gql__type_ListAPIKeysReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ListAPIKeysReply",
+			Fields: graphql.Fields{
+				"api_keys": &graphql.Field{
+					Type: graphql.NewList(Gql__type_APIKey()),
+				},
+			},
+		})
+	}
+	return gql__type_ListAPIKeysReply
+}
+
+func Gql__type_ImpersonateUserRequest() *graphql.Object {
+	if gql__type_ImpersonateUserRequest == nil {
+		gql__type_ImpersonateUserRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ImpersonateUserRequest",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"reason": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Recorded in the audit log.`,
+				},
+			},
+		})
+	}
+	return gql__type_ImpersonateUserRequest
+}
+
+func Gql__type_ImpersonateUserReply() *graphql.Object {
+	if gql__type_ImpersonateUserReply == nil {
+		gql__type_ImpersonateUserReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ImpersonateUserReply",
+			Fields: graphql.Fields{
+				"token": &graphql.Field{
+					Type: graphql.String,
+					Description: `Access token of the user that names the admin in its act claim. It cannot be
+ refreshed.`,
+				},
+				"expires_in": &graphql.Field{
+					Type:        graphql.Int,
+					Description: `Lifetime of the token in seconds.`,
+				},
+			},
+		})
+	}
+	return gql__type_ImpersonateUserReply
+}
+
+func Gql__type_GetUserRequest() *graphql.Object {
+	if gql__type_GetUserRequest == nil {
+		gql__type_GetUserRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_GetUserRequest",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_GetUserRequest
+}
+
+func Gql__type_ForcePasswordResetRequest() *graphql.Object {
+	if gql__type_ForcePasswordResetRequest == nil {
+		gql__type_ForcePasswordResetRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ForcePasswordResetRequest",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_ForcePasswordResetRequest
+}
+
+func Gql__type_ForcePasswordResetReply() *graphql.Object {
+	if gql__type_ForcePasswordResetReply == nil {
+		gql__type_ForcePasswordResetReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ForcePasswordResetReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_ForcePasswordResetReply
+}
+
+func Gql__type_FinishWebAuthnRegistrationRequest() *graphql.Object {
+	if gql__type_FinishWebAuthnRegistrationRequest == nil {
+		gql__type_FinishWebAuthnRegistrationRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_FinishWebAuthnRegistrationRequest",
+			Fields: graphql.Fields{
+				"session_token": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"credential": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `JSON of the PublicKeyCredential returned by navigator.credentials.create.`,
+				},
+				"name": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Label shown to the user, such as "YubiKey".`,
+				},
+			},
+		})
+	}
+	return gql__type_FinishWebAuthnRegistrationRequest
+}
+
+func Gql__type_FinishWebAuthnRegistrationReply() *graphql.Object {
+	if gql__type_FinishWebAuthnRegistrationReply == nil {
+		gql__type_FinishWebAuthnRegistrationReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_FinishWebAuthnRegistrationReply",
+			Fields: graphql.Fields{
+				"credential_id": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Base64url encoded ID of the credential.`,
+				},
+			},
+		})
+	}
+	return gql__type_FinishWebAuthnRegistrationReply
+}
+
+func Gql__type_FinishWebAuthnLoginRequest() *graphql.Object {
+	if gql__type_FinishWebAuthnLoginRequest == nil {
+		gql__type_FinishWebAuthnLoginRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_FinishWebAuthnLoginRequest",
+			Fields: graphql.Fields{
+				"session_token": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"credential": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `JSON of the PublicKeyCredential returned by navigator.credentials.get.`,
+				},
+			},
+		})
+	}
+	return gql__type_FinishWebAuthnLoginRequest
+}
+
+func Gql__type_EnrollTOTPReply() *graphql.Object {
+	if gql__type_EnrollTOTPReply == nil {
+		gql__type_EnrollTOTPReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_EnrollTOTPReply",
+			Fields: graphql.Fields{
+				"secret": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Base32 encoded secret, for manual entry.`,
+				},
+				"otpauth_uri": &graphql.Field{
+					Type:        graphql.String,
+					Description: `otpauth:// URI, usually rendered as a QR code.`,
+				},
+			},
+		})
+	}
+	return gql__type_EnrollTOTPReply
+}
+
+func Gql__type_EnableUserRequest() *graphql.Object {
+	if gql__type_EnableUserRequest == nil {
+		gql__type_EnableUserRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_EnableUserRequest",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_EnableUserRequest
+}
+
+func Gql__type_DisableUserRequest() *graphql.Object {
+	if gql__type_DisableUserRequest == nil {
+		gql__type_DisableUserRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_DisableUserRequest",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"reason": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Recorded in the audit log.`,
+				},
+			},
+		})
+	}
+	return gql__type_DisableUserRequest
+}
+
+func Gql__type_DisableTOTPRequest() *graphql.Object {
+	if gql__type_DisableTOTPRequest == nil {
+		gql__type_DisableTOTPRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_DisableTOTPRequest",
+			Fields: graphql.Fields{
+				"code": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Current TOTP code or an unused recovery code.`,
+				},
+			},
+		})
+	}
+	return gql__type_DisableTOTPRequest
+}
+
+func Gql__type_DisableTOTPReply() *graphql.Object {
+	if gql__type_DisableTOTPReply == nil {
+		gql__type_DisableTOTPReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_DisableTOTPReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_DisableTOTPReply
+}
+
+func Gql__type_DeleteAccountRequest() *graphql.Object {
+	if gql__type_DeleteAccountRequest == nil {
+		gql__type_DeleteAccountRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_DeleteAccountRequest",
+			Fields: graphql.Fields{
+				"password": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Current password, to confirm the deletion.`,
+				},
+			},
+		})
+	}
+	return gql__type_DeleteAccountRequest
+}
+
+func Gql__type_DeleteAccountReply() *graphql.Object {
+	if gql__type_DeleteAccountReply == nil {
+		gql__type_DeleteAccountReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_DeleteAccountReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_DeleteAccountReply
+}
+
+func Gql__type_CreateAPIKeyRequest() *graphql.Object {
+	if gql__type_CreateAPIKeyRequest == nil {
+		gql__type_CreateAPIKeyRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_CreateAPIKeyRequest",
+			Fields: graphql.Fields{
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"scopes": &graphql.Field{
+					Type: graphql.NewList(graphql.String),
+				},
+				"expires_in": &graphql.Field{
+					Type:        graphql.Int,
+					Description: `Lifetime of the key in seconds; 0 means the key does not expire.`,
+				},
+			},
+		})
+	}
+	return gql__type_CreateAPIKeyRequest
+}
+
+func Gql__type_CreateAPIKeyReply() *graphql.Object {
+	if gql__type_CreateAPIKeyReply == nil {
+		gql__type_CreateAPIKeyReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_CreateAPIKeyReply",
+			Fields: graphql.Fields{
+				"key": &graphql.Field{
+					Type:        graphql.String,
+					Description: `The key, sent as the x-api-key header. It is only shown once.`,
+				},
+				"api_key": &graphql.Field{
+					Type: Gql__type_APIKey(),
+				},
+			},
+		})
+	}
+	return gql__type_CreateAPIKeyReply
+}
+
+func Gql__type_ConsumeMagicLinkRequest() *graphql.Object {
+	if gql__type_ConsumeMagicLinkRequest == nil {
+		gql__type_ConsumeMagicLinkRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ConsumeMagicLinkRequest",
+			Fields: graphql.Fields{
+				"token": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Token from the magic link mail.`,
+				},
+				"nonce": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `nonce of the RequestMagicLink reply.`,
+				},
+			},
+		})
+	}
+	return gql__type_ConsumeMagicLinkRequest
+}
+
+func Gql__type_ConfirmTOTPRequest() *graphql.Object {
+	if gql__type_ConfirmTOTPRequest == nil {
+		gql__type_ConfirmTOTPRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ConfirmTOTPRequest",
+			Fields: graphql.Fields{
+				"code": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_ConfirmTOTPRequest
+}
+
+func Gql__type_ConfirmTOTPReply() *graphql.Object {
+	if gql__type_ConfirmTOTPReply == nil {
+		gql__type_ConfirmTOTPReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ConfirmTOTPReply",
+			Fields: graphql.Fields{
+				"recovery_codes": &graphql.Field{
+					Type: graphql.NewList(graphql.String),
+					Description: `Single-use codes that replace a TOTP code when the device is lost.
+ They are only shown once.`,
+				},
+			},
+		})
+	}
+	return gql__type_ConfirmTOTPReply
+}
+
+func Gql__type_CompleteOAuthLoginRequest() *graphql.Object {
+	if gql__type_CompleteOAuthLoginRequest == nil {
+		gql__type_CompleteOAuthLoginRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_CompleteOAuthLoginRequest",
+			Fields: graphql.Fields{
+				"state": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"code": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Authorization code returned by the provider on the redirect URL.`,
+				},
+				"code_verifier": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_CompleteOAuthLoginRequest
+}
+
+func Gql__type_ChangePasswordRequest() *graphql.Object {
+	if gql__type_ChangePasswordRequest == nil {
+		gql__type_ChangePasswordRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ChangePasswordRequest",
+			Fields: graphql.Fields{
+				"current_password": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"new_password": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__type_ChangePasswordRequest
+}
+
+func Gql__type_ChangePasswordReply() *graphql.Object {
+	if gql__type_ChangePasswordReply == nil {
+		gql__type_ChangePasswordReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_ChangePasswordReply",
+			Fields: graphql.Fields{
+				"reply": &graphql.Field{
+					Type: graphql.String,
+				},
+				"token": &graphql.Field{
+					Type: graphql.String,
+					Description: `Access tokens issued before the change are revoked. Callers signed in with a
+ session get a new access token for it; its refresh token stays valid.`,
+				},
+				"expires_in": &graphql.Field{
+					Type:        graphql.Int,
+					Description: `Lifetime of the access token in seconds.`,
+				},
+			},
+		})
+	}
+	return gql__type_ChangePasswordReply
+}
+
+func Gql__type_BeginWebAuthnReply() *graphql.Object {
+	if gql__type_BeginWebAuthnReply == nil {
+		gql__type_BeginWebAuthnReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_BeginWebAuthnReply",
+			Fields: graphql.Fields{
+				"options": &graphql.Field{
+					Type:        graphql.String,
+					Description: `JSON options for navigator.credentials.create or .get.`,
+				},
+				"session_token": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Single-use token of the ceremony, sent back with its result.`,
+				},
+			},
+		})
+	}
+	return gql__type_BeginWebAuthnReply
+}
+
+func Gql__type_BeginWebAuthnLoginRequest() *graphql.Object {
+	if gql__type_BeginWebAuthnLoginRequest == nil {
+		gql__type_BeginWebAuthnLoginRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_BeginWebAuthnLoginRequest",
+			Fields: graphql.Fields{
+				"email": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Email of the user; empty to let the authenticator offer its passkeys.`,
+				},
+			},
+		})
+	}
+	return gql__type_BeginWebAuthnLoginRequest
+}
+
+func Gql__type_BeginOAuthLoginRequest() *graphql.Object {
+	if gql__type_BeginOAuthLoginRequest == nil {
+		gql__type_BeginOAuthLoginRequest = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_BeginOAuthLoginRequest",
+			Fields: graphql.Fields{
+				"provider": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: `Name of a configured OpenID Connect provider.`,
+				},
+			},
+		})
+	}
+	return gql__type_BeginOAuthLoginRequest
+}
+
+func Gql__type_BeginOAuthLoginReply() *graphql.Object {
+	if gql__type_BeginOAuthLoginReply == nil {
+		gql__type_BeginOAuthLoginReply = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_BeginOAuthLoginReply",
+			Fields: graphql.Fields{
+				"authorize_url": &graphql.Field{
+					Type:        graphql.String,
+					Description: `URL the user is sent to in order to sign in with the provider.`,
+				},
+				"state": &graphql.Field{
+					Type:        graphql.String,
+					Description: `Signed state, passed back to CompleteOAuthLogin together with the code.`,
+				},
+				"code_verifier": &graphql.Field{
+					Type:        graphql.String,
+					Description: `PKCE verifier; the client keeps it and passes it to CompleteOAuthLogin.`,
+				},
+			},
+		})
+	}
+	return gql__type_BeginOAuthLoginReply
+}
+
+func Gql__type_AuditEvent() *graphql.Object {
+	if gql__type_AuditEvent == nil {
+		gql__type_AuditEvent = graphql.NewObject(graphql.ObjectConfig{
+			Name:        "Generated_Type_AuditEvent",
+			Description: `AuditEvent is a recorded security event, such as a login or a token revocation.`,
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.String,
+				},
+				"action": &graphql.Field{
+					Type: graphql.String,
+				},
+				"outcome": &graphql.Field{
+					Type:        graphql.String,
+					Description: `"success" or "failure".`,
+				},
+				"actor_id": &graphql.Field{
+					Type: graphql.String,
+				},
+				"actor_email": &graphql.Field{
+					Type: graphql.String,
+				},
+				"target_id": &graphql.Field{
+					Type: graphql.String,
+				},
+				"ip": &graphql.Field{
+					Type: graphql.String,
+				},
+				"user_agent": &graphql.Field{
+					Type: graphql.String,
+				},
+				"request_id": &graphql.Field{
+					Type: graphql.String,
+				},
+				"reason": &graphql.Field{
+					Type: graphql.String,
+				},
+				"created_at": &graphql.Field{
+					Type:        graphql.String,
+					Description: `RFC 3339 timestamp.`,
+				},
+			},
+		})
+	}
+	return gql__type_AuditEvent
+}
+
+func Gql__type_APIKey() *graphql.Object {
+	if gql__type_APIKey == nil {
+		gql__type_APIKey = graphql.NewObject(graphql.ObjectConfig{
+			Name: "Generated_Type_APIKey",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.String,
+				},
+				"name": &graphql.Field{
+					Type: graphql.String,
+				},
+				"prefix": &graphql.Field{
+					Type:        graphql.String,
+					Description: `First characters of the key, to tell keys apart.`,
+				},
+				"scopes": &graphql.Field{
+					Type:        graphql.NewList(graphql.String),
+					Description: `Roles the key may act with; a subset of the roles of its owner.`,
+				},
+				"created_at": &graphql.Field{
+					Type:        graphql.String,
+					Description: `RFC 3339 timestamps; expires_at and last_used_at are empty when unset.`,
+				},
+				"expires_at": &graphql.Field{
+					Type: graphql.String,
+				},
+				"last_used_at": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__type_APIKey
+}
+
+func Gql__input_VerifyEmailRequest() *graphql.InputObject {
+	if gql__input_VerifyEmailRequest == nil {
+		gql__input_VerifyEmailRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_VerifyEmailRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"token": &graphql.InputObjectFieldConfig{
+					Description: `Token from the verification mail.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_VerifyEmailRequest
+}
+
+func Gql__input_VerifyEmailReply() *graphql.InputObject {
+	if gql__input_VerifyEmailReply == nil {
+		gql__input_VerifyEmailReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_VerifyEmailReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_VerifyEmailReply
+}
+
+func Gql__input_User() *graphql.InputObject {
+	if gql__input_User == nil {
+		gql__input_User = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_User",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"email": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"email_verified": &graphql.InputObjectFieldConfig{
+					Type: graphql.Boolean,
+				},
+				"name": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"surname": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"age": &graphql.InputObjectFieldConfig{
+					Type: graphql.Int,
+				},
+				"desc": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"roles": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewList(graphql.String),
+				},
+				"totp_enabled": &graphql.InputObjectFieldConfig{
+					Type: graphql.Boolean,
+				},
+				"created_at": &graphql.InputObjectFieldConfig{
+					Description: `RFC 3339 timestamps.`,
+					Type:        graphql.String,
+				},
+				"updated_at": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"disabled": &graphql.InputObjectFieldConfig{
+					Description: `Disabled users cannot log in; see UserAdmin.DisableUser.`,
+					Type:        graphql.Boolean,
+				},
+			},
+		})
+	}
+	return gql__input_User
+}
+
+func Gql__input_UpdateProfileRequest() *graphql.InputObject {
+	if gql__input_UpdateProfileRequest == nil {
+		gql__input_UpdateProfileRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_UpdateProfileRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"user": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(Gql__input_User()),
+				},
+				"update_mask": &graphql.InputObjectFieldConfig{
+					Description: `Field mask: paths of the fields of user to update, out of name, surname, age
+ and desc. A plain list, since the GraphQL gateway has no FieldMask support.`,
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				},
+			},
+		})
+	}
+	return gql__input_UpdateProfileRequest
+}
+
+func Gql__input_UnlockUserRequest() *graphql.InputObject {
+	if gql__input_UnlockUserRequest == nil {
+		gql__input_UnlockUserRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_UnlockUserRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"email": &graphql.InputObjectFieldConfig{
+					Description: `Email of the account whose failed login attempts are cleared.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_UnlockUserRequest
+}
+
+func Gql__input_UnlockUserReply() *graphql.InputObject {
+	if gql__input_UnlockUserReply == nil {
+		gql__input_UnlockUserReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_UnlockUserReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_UnlockUserReply
+}
+
+func Gql__input_SetUserRolesRequest() *graphql.InputObject {
+	if gql__input_SetUserRolesRequest == nil {
+		gql__input_SetUserRolesRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_SetUserRolesRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"roles": &graphql.InputObjectFieldConfig{
+					Description: `Replaces the roles of the user.`,
+					Type:        graphql.NewList(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_SetUserRolesRequest
+}
+
+func Gql__input_Session() *graphql.InputObject {
+	if gql__input_Session == nil {
+		gql__input_Session = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_Session",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"user_agent": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"ip": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"created_at": &graphql.InputObjectFieldConfig{
+					Description: `RFC 3339 timestamps.`,
+					Type:        graphql.String,
+				},
+				"last_seen_at": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"current": &graphql.InputObjectFieldConfig{
+					Description: `Set for the session of the token used for the call.`,
+					Type:        graphql.Boolean,
+				},
+			},
+		})
+	}
+	return gql__input_Session
+}
+
+func Gql__input_RevokeSessionRequest() *graphql.InputObject {
+	if gql__input_RevokeSessionRequest == nil {
+		gql__input_RevokeSessionRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RevokeSessionRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_RevokeSessionRequest
+}
+
+func Gql__input_RevokeSessionReply() *graphql.InputObject {
+	if gql__input_RevokeSessionReply == nil {
+		gql__input_RevokeSessionReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RevokeSessionReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_RevokeSessionReply
+}
+
+func Gql__input_RevokeOtherSessionsReply() *graphql.InputObject {
+	if gql__input_RevokeOtherSessionsReply == nil {
+		gql__input_RevokeOtherSessionsReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RevokeOtherSessionsReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"revoked": &graphql.InputObjectFieldConfig{
+					Description: `Number of sessions revoked.`,
+					Type:        graphql.Int,
+				},
+			},
+		})
+	}
+	return gql__input_RevokeOtherSessionsReply
+}
+
+func Gql__input_RevokeAllSessionsReply() *graphql.InputObject {
+	if gql__input_RevokeAllSessionsReply == nil {
+		gql__input_RevokeAllSessionsReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RevokeAllSessionsReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_RevokeAllSessionsReply
+}
+
+func Gql__input_RevokeAPIKeyRequest() *graphql.InputObject {
+	if gql__input_RevokeAPIKeyRequest == nil {
+		gql__input_RevokeAPIKeyRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RevokeAPIKeyRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_RevokeAPIKeyRequest
+}
+
+func Gql__input_RevokeAPIKeyReply() *graphql.InputObject {
+	if gql__input_RevokeAPIKeyReply == nil {
+		gql__input_RevokeAPIKeyReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RevokeAPIKeyReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_RevokeAPIKeyReply
+}
+
+func Gql__input_ResetPasswordRequest() *graphql.InputObject {
+	if gql__input_ResetPasswordRequest == nil {
+		gql__input_ResetPasswordRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ResetPasswordRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"token": &graphql.InputObjectFieldConfig{
+					Description: `Token from the password reset mail.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+				"new_password": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ResetPasswordRequest
+}
+
+func Gql__input_ResetPasswordReply() *graphql.InputObject {
+	if gql__input_ResetPasswordReply == nil {
+		gql__input_ResetPasswordReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ResetPasswordReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_ResetPasswordReply
+}
+
+func Gql__input_ResendVerificationRequest() *graphql.InputObject {
+	if gql__input_ResendVerificationRequest == nil {
+		gql__input_ResendVerificationRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ResendVerificationRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"email": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ResendVerificationRequest
+}
+
+func Gql__input_ResendVerificationReply() *graphql.InputObject {
+	if gql__input_ResendVerificationReply == nil {
+		gql__input_ResendVerificationReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ResendVerificationReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_ResendVerificationReply
+}
+
+func Gql__input_RequestPasswordResetRequest() *graphql.InputObject {
+	if gql__input_RequestPasswordResetRequest == nil {
+		gql__input_RequestPasswordResetRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RequestPasswordResetRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"email": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_RequestPasswordResetRequest
+}
+
+func Gql__input_RequestPasswordResetReply() *graphql.InputObject {
+	if gql__input_RequestPasswordResetReply == nil {
+		gql__input_RequestPasswordResetReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RequestPasswordResetReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_RequestPasswordResetReply
+}
+
+func Gql__input_RequestMagicLinkRequest() *graphql.InputObject {
+	if gql__input_RequestMagicLinkRequest == nil {
+		gql__input_RequestMagicLinkRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RequestMagicLinkRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"email": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_RequestMagicLinkRequest
+}
+
+func Gql__input_RequestMagicLinkReply() *graphql.InputObject {
+	if gql__input_RequestMagicLinkReply == nil {
+		gql__input_RequestMagicLinkReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RequestMagicLinkReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"nonce": &graphql.InputObjectFieldConfig{
+					Description: `Kept by the requesting device and sent with the token to ConsumeMagicLink, so that
+ the link only logs in the device that asked for it.`,
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_RequestMagicLinkReply
+}
+
+func Gql__input_RegisterRequest() *graphql.InputObject {
+	if gql__input_RegisterRequest == nil {
+		gql__input_RegisterRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RegisterRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"email": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"password": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"name": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"surname": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"age": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			},
+		})
+	}
+	return gql__input_RegisterRequest
+}
+
+func Gql__input_RegisterReply() *graphql.InputObject {
+	if gql__input_RegisterReply == nil {
+		gql__input_RegisterReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RegisterReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_RegisterReply
+}
+
+func Gql__input_RefreshTokenRequest() *graphql.InputObject {
+	if gql__input_RefreshTokenRequest == nil {
+		gql__input_RefreshTokenRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_RefreshTokenRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"refresh_token": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_RefreshTokenRequest
+}
+
+func Gql__input_ProtectedRequest() *graphql.InputObject {
+	if gql__input_ProtectedRequest == nil {
+		gql__input_ProtectedRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ProtectedRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"text": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ProtectedRequest
+}
+
+func Gql__input_ProtectedReply() *graphql.InputObject {
+	if gql__input_ProtectedReply == nil {
+		gql__input_ProtectedReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ProtectedReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"result": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_ProtectedReply
+}
+
+func Gql__input_LogoutRequest() *graphql.InputObject {
+	if gql__input_LogoutRequest == nil {
+		gql__input_LogoutRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_LogoutRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"refresh_token": &graphql.InputObjectFieldConfig{
+					Description: `Optional refresh token whose family is revoked together with the access token.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_LogoutRequest
+}
+
+func Gql__input_LogoutReply() *graphql.InputObject {
+	if gql__input_LogoutReply == nil {
+		gql__input_LogoutReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_LogoutReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_LogoutReply
+}
+
+func Gql__input_LoginVerifyRequest() *graphql.InputObject {
+	if gql__input_LoginVerifyRequest == nil {
+		gql__input_LoginVerifyRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_LoginVerifyRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"challenge_token": &graphql.InputObjectFieldConfig{
+					Description: `Challenge token returned by Login.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+				"code": &graphql.InputObjectFieldConfig{
+					Description: `Current TOTP code or an unused recovery code.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_LoginVerifyRequest
+}
+
+func Gql__input_LoginRequest() *graphql.InputObject {
+	if gql__input_LoginRequest == nil {
+		gql__input_LoginRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_LoginRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"email": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"password": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_LoginRequest
+}
+
+func Gql__input_LoginReply() *graphql.InputObject {
+	if gql__input_LoginReply == nil {
+		gql__input_LoginReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_LoginReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"token": &graphql.InputObjectFieldConfig{
+					Description: `Short-lived access token (JWT).`,
+					Type:        graphql.String,
+				},
+				"refresh_token": &graphql.InputObjectFieldConfig{
+					Description: `Opaque, single-use token exchanged for a new token pair via RefreshToken.`,
+					Type:        graphql.String,
+				},
+				"expires_in": &graphql.InputObjectFieldConfig{
+					Description: `Lifetime of the access token in seconds.`,
+					Type:        graphql.Int,
+				},
+				"two_factor_required": &graphql.InputObjectFieldConfig{
+					Description: `Set when the user has two-factor authentication enabled. No tokens are
+ issued; the challenge token must be exchanged through LoginVerify.`,
+					Type: graphql.Boolean,
+				},
+				"challenge_token": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_LoginReply
+}
+
+func Gql__input_ListUsersRequest() *graphql.InputObject {
+	if gql__input_ListUsersRequest == nil {
+		gql__input_ListUsersRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ListUsersRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"query": &graphql.InputObjectFieldConfig{
+					Description: `Case-insensitive part of the email or name.`,
+					Type:        graphql.String,
+				},
+				"role": &graphql.InputObjectFieldConfig{
+					Description: `Only users holding the role.`,
+					Type:        graphql.String,
+				},
+				"status": &graphql.InputObjectFieldConfig{
+					Description: `"active" or "disabled".`,
+					Type:        graphql.String,
+				},
+				"order_by": &graphql.InputObjectFieldConfig{
+					Description: `"created_at" (default), "email" or "name".`,
+					Type:        graphql.String,
+				},
+				"descending": &graphql.InputObjectFieldConfig{
+					Type: graphql.Boolean,
+				},
+				"page_size": &graphql.InputObjectFieldConfig{
+					Description: `Users per page, at most 100 (default 50).`,
+					Type:        graphql.Int,
+				},
+				"page_token": &graphql.InputObjectFieldConfig{
+					Description: `next_page_token of the previous page; the other fields must not change between pages.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_ListUsersRequest
+}
+
+func Gql__input_ListUsersReply() *graphql.InputObject {
+	if gql__input_ListUsersReply == nil {
+		gql__input_ListUsersReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ListUsersReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"users": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewList(Gql__input_User()),
+				},
+				"next_page_token": &graphql.InputObjectFieldConfig{
+					Description: `Empty on the last page.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_ListUsersReply
+}
+
+func Gql__input_ListSessionsReply() *graphql.InputObject {
+	if gql__input_ListSessionsReply == nil {
+		gql__input_ListSessionsReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ListSessionsReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"sessions": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewList(Gql__input_Session()),
+				},
+			},
+		})
+	}
+	return gql__input_ListSessionsReply
+}
+
+func Gql__input_ListAuditEventsRequest() *graphql.InputObject {
+	if gql__input_ListAuditEventsRequest == nil {
+		gql__input_ListAuditEventsRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ListAuditEventsRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"actor_id": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"action": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"outcome": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"since": &graphql.InputObjectFieldConfig{
+					Description: `RFC 3339 bounds of the event time; since is inclusive, until exclusive.`,
+					Type:        graphql.String,
+				},
+				"until": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"page_size": &graphql.InputObjectFieldConfig{
+					Description: `Events per page, at most 100 (default 50).`,
+					Type:        graphql.Int,
+				},
+				"page_token": &graphql.InputObjectFieldConfig{
+					Description: `next_page_token of the previous page.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_ListAuditEventsRequest
+}
+
+func Gql__input_ListAuditEventsReply() *graphql.InputObject {
+	if gql__input_ListAuditEventsReply == nil {
+		gql__input_ListAuditEventsReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ListAuditEventsReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"events": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewList(Gql__input_AuditEvent()),
+				},
+				"next_page_token": &graphql.InputObjectFieldConfig{
+					Description: `Empty on the last page.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_ListAuditEventsReply
+}
+
+func Gql__input_ListAPIKeysReply() *graphql.InputObject {
+	if gql__input_ListAPIKeysReply == nil {
+		gql__input_ListAPIKeysReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ListAPIKeysReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"api_keys": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewList(Gql__input_APIKey()),
+				},
+			},
+		})
+	}
+	return gql__input_ListAPIKeysReply
+}
+
+func Gql__input_ImpersonateUserRequest() *graphql.InputObject {
+	if gql__input_ImpersonateUserRequest == nil {
+		gql__input_ImpersonateUserRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ImpersonateUserRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"reason": &graphql.InputObjectFieldConfig{
+					Description: `Recorded in the audit log.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ImpersonateUserRequest
+}
+
+func Gql__input_ImpersonateUserReply() *graphql.InputObject {
+	if gql__input_ImpersonateUserReply == nil {
+		gql__input_ImpersonateUserReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ImpersonateUserReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"token": &graphql.InputObjectFieldConfig{
+					Description: `Access token of the user that names the admin in its act claim. It cannot be
+ refreshed.`,
+					Type: graphql.String,
+				},
+				"expires_in": &graphql.InputObjectFieldConfig{
+					Description: `Lifetime of the token in seconds.`,
+					Type:        graphql.Int,
+				},
+			},
+		})
+	}
+	return gql__input_ImpersonateUserReply
+}
+
+func Gql__input_GetUserRequest() *graphql.InputObject {
+	if gql__input_GetUserRequest == nil {
+		gql__input_GetUserRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_GetUserRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_GetUserRequest
+}
+
+func Gql__input_ForcePasswordResetRequest() *graphql.InputObject {
+	if gql__input_ForcePasswordResetRequest == nil {
+		gql__input_ForcePasswordResetRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ForcePasswordResetRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ForcePasswordResetRequest
+}
+
+func Gql__input_ForcePasswordResetReply() *graphql.InputObject {
+	if gql__input_ForcePasswordResetReply == nil {
+		gql__input_ForcePasswordResetReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ForcePasswordResetReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_ForcePasswordResetReply
+}
+
+func Gql__input_FinishWebAuthnRegistrationRequest() *graphql.InputObject {
+	if gql__input_FinishWebAuthnRegistrationRequest == nil {
+		gql__input_FinishWebAuthnRegistrationRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_FinishWebAuthnRegistrationRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"session_token": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"credential": &graphql.InputObjectFieldConfig{
+					Description: `JSON of the PublicKeyCredential returned by navigator.credentials.create.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+				"name": &graphql.InputObjectFieldConfig{
+					Description: `Label shown to the user, such as "YubiKey".`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_FinishWebAuthnRegistrationRequest
+}
+
+func Gql__input_FinishWebAuthnRegistrationReply() *graphql.InputObject {
+	if gql__input_FinishWebAuthnRegistrationReply == nil {
+		gql__input_FinishWebAuthnRegistrationReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_FinishWebAuthnRegistrationReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"credential_id": &graphql.InputObjectFieldConfig{
+					Description: `Base64url encoded ID of the credential.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_FinishWebAuthnRegistrationReply
+}
+
+func Gql__input_FinishWebAuthnLoginRequest() *graphql.InputObject {
+	if gql__input_FinishWebAuthnLoginRequest == nil {
+		gql__input_FinishWebAuthnLoginRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_FinishWebAuthnLoginRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"session_token": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"credential": &graphql.InputObjectFieldConfig{
+					Description: `JSON of the PublicKeyCredential returned by navigator.credentials.get.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_FinishWebAuthnLoginRequest
+}
+
+func Gql__input_EnrollTOTPReply() *graphql.InputObject {
+	if gql__input_EnrollTOTPReply == nil {
+		gql__input_EnrollTOTPReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_EnrollTOTPReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"secret": &graphql.InputObjectFieldConfig{
+					Description: `Base32 encoded secret, for manual entry.`,
+					Type:        graphql.String,
+				},
+				"otpauth_uri": &graphql.InputObjectFieldConfig{
+					Description: `otpauth:// URI, usually rendered as a QR code.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_EnrollTOTPReply
+}
+
+func Gql__input_EnableUserRequest() *graphql.InputObject {
+	if gql__input_EnableUserRequest == nil {
+		gql__input_EnableUserRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_EnableUserRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_EnableUserRequest
+}
+
+func Gql__input_DisableUserRequest() *graphql.InputObject {
+	if gql__input_DisableUserRequest == nil {
+		gql__input_DisableUserRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_DisableUserRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"reason": &graphql.InputObjectFieldConfig{
+					Description: `Recorded in the audit log.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_DisableUserRequest
+}
+
+func Gql__input_DisableTOTPRequest() *graphql.InputObject {
+	if gql__input_DisableTOTPRequest == nil {
+		gql__input_DisableTOTPRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_DisableTOTPRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"code": &graphql.InputObjectFieldConfig{
+					Description: `Current TOTP code or an unused recovery code.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_DisableTOTPRequest
+}
+
+func Gql__input_DisableTOTPReply() *graphql.InputObject {
+	if gql__input_DisableTOTPReply == nil {
+		gql__input_DisableTOTPReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_DisableTOTPReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_DisableTOTPReply
+}
+
+func Gql__input_DeleteAccountRequest() *graphql.InputObject {
+	if gql__input_DeleteAccountRequest == nil {
+		gql__input_DeleteAccountRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_DeleteAccountRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"password": &graphql.InputObjectFieldConfig{
+					Description: `Current password, to confirm the deletion.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_DeleteAccountRequest
+}
+
+func Gql__input_DeleteAccountReply() *graphql.InputObject {
+	if gql__input_DeleteAccountReply == nil {
+		gql__input_DeleteAccountReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_DeleteAccountReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_DeleteAccountReply
+}
+
+func Gql__input_CreateAPIKeyRequest() *graphql.InputObject {
+	if gql__input_CreateAPIKeyRequest == nil {
+		gql__input_CreateAPIKeyRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_CreateAPIKeyRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"name": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"scopes": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewList(graphql.String),
+				},
+				"expires_in": &graphql.InputObjectFieldConfig{
+					Description: `Lifetime of the key in seconds; 0 means the key does not expire.`,
+					Type:        graphql.Int,
+				},
+			},
+		})
+	}
+	return gql__input_CreateAPIKeyRequest
+}
+
+func Gql__input_CreateAPIKeyReply() *graphql.InputObject {
+	if gql__input_CreateAPIKeyReply == nil {
+		gql__input_CreateAPIKeyReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_CreateAPIKeyReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"key": &graphql.InputObjectFieldConfig{
+					Description: `The key, sent as the x-api-key header. It is only shown once.`,
+					Type:        graphql.String,
+				},
+				"api_key": &graphql.InputObjectFieldConfig{
+					Type: Gql__input_APIKey(),
+				},
+			},
+		})
+	}
+	return gql__input_CreateAPIKeyReply
+}
+
+func Gql__input_ConsumeMagicLinkRequest() *graphql.InputObject {
+	if gql__input_ConsumeMagicLinkRequest == nil {
+		gql__input_ConsumeMagicLinkRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ConsumeMagicLinkRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"token": &graphql.InputObjectFieldConfig{
+					Description: `Token from the magic link mail.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+				"nonce": &graphql.InputObjectFieldConfig{
+					Description: `nonce of the RequestMagicLink reply.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ConsumeMagicLinkRequest
+}
+
+func Gql__input_ConfirmTOTPRequest() *graphql.InputObject {
+	if gql__input_ConfirmTOTPRequest == nil {
+		gql__input_ConfirmTOTPRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ConfirmTOTPRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"code": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ConfirmTOTPRequest
+}
+
+func Gql__input_ConfirmTOTPReply() *graphql.InputObject {
+	if gql__input_ConfirmTOTPReply == nil {
+		gql__input_ConfirmTOTPReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ConfirmTOTPReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"recovery_codes": &graphql.InputObjectFieldConfig{
+					Description: `Single-use codes that replace a TOTP code when the device is lost.
+ They are only shown once.`,
+					Type: graphql.NewList(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ConfirmTOTPReply
+}
+
+func Gql__input_CompleteOAuthLoginRequest() *graphql.InputObject {
+	if gql__input_CompleteOAuthLoginRequest == nil {
+		gql__input_CompleteOAuthLoginRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_CompleteOAuthLoginRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"state": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"code": &graphql.InputObjectFieldConfig{
+					Description: `Authorization code returned by the provider on the redirect URL.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+				"code_verifier": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_CompleteOAuthLoginRequest
+}
+
+func Gql__input_ChangePasswordRequest() *graphql.InputObject {
+	if gql__input_ChangePasswordRequest == nil {
+		gql__input_ChangePasswordRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ChangePasswordRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"current_password": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"new_password": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_ChangePasswordRequest
+}
+
+func Gql__input_ChangePasswordReply() *graphql.InputObject {
+	if gql__input_ChangePasswordReply == nil {
+		gql__input_ChangePasswordReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_ChangePasswordReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"reply": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"token": &graphql.InputObjectFieldConfig{
+					Description: `Access tokens issued before the change are revoked. Callers signed in with a
+ session get a new access token for it; its refresh token stays valid.`,
+					Type: graphql.String,
+				},
+				"expires_in": &graphql.InputObjectFieldConfig{
+					Description: `Lifetime of the access token in seconds.`,
+					Type:        graphql.Int,
+				},
+			},
+		})
+	}
+	return gql__input_ChangePasswordReply
+}
+
+func Gql__input_BeginWebAuthnReply() *graphql.InputObject {
+	if gql__input_BeginWebAuthnReply == nil {
+		gql__input_BeginWebAuthnReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_BeginWebAuthnReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"options": &graphql.InputObjectFieldConfig{
+					Description: `JSON options for navigator.credentials.create or .get.`,
+					Type:        graphql.String,
+				},
+				"session_token": &graphql.InputObjectFieldConfig{
+					Description: `Single-use token of the ceremony, sent back with its result.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_BeginWebAuthnReply
+}
+
+func Gql__input_BeginWebAuthnLoginRequest() *graphql.InputObject {
+	if gql__input_BeginWebAuthnLoginRequest == nil {
+		gql__input_BeginWebAuthnLoginRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_BeginWebAuthnLoginRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"email": &graphql.InputObjectFieldConfig{
+					Description: `Email of the user; empty to let the authenticator offer its passkeys.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_BeginWebAuthnLoginRequest
+}
+
+func Gql__input_BeginOAuthLoginRequest() *graphql.InputObject {
+	if gql__input_BeginOAuthLoginRequest == nil {
+		gql__input_BeginOAuthLoginRequest = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_BeginOAuthLoginRequest",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"provider": &graphql.InputObjectFieldConfig{
+					Description: `Name of a configured OpenID Connect provider.`,
+					Type:        graphql.NewNonNull(graphql.String),
+				},
+			},
+		})
+	}
+	return gql__input_BeginOAuthLoginRequest
+}
+
+func Gql__input_BeginOAuthLoginReply() *graphql.InputObject {
+	if gql__input_BeginOAuthLoginReply == nil {
+		gql__input_BeginOAuthLoginReply = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_BeginOAuthLoginReply",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"authorize_url": &graphql.InputObjectFieldConfig{
+					Description: `URL the user is sent to in order to sign in with the provider.`,
+					Type:        graphql.String,
+				},
+				"state": &graphql.InputObjectFieldConfig{
+					Description: `Signed state, passed back to CompleteOAuthLogin together with the code.`,
+					Type:        graphql.String,
+				},
+				"code_verifier": &graphql.InputObjectFieldConfig{
+					Description: `PKCE verifier; the client keeps it and passes it to CompleteOAuthLogin.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_BeginOAuthLoginReply
+}
+
+func Gql__input_AuditEvent() *graphql.InputObject {
+	if gql__input_AuditEvent == nil {
+		gql__input_AuditEvent = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_AuditEvent",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"action": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"outcome": &graphql.InputObjectFieldConfig{
+					Description: `"success" or "failure".`,
+					Type:        graphql.String,
+				},
+				"actor_id": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"actor_email": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"target_id": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"ip": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"user_agent": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"request_id": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"reason": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"created_at": &graphql.InputObjectFieldConfig{
+					Description: `RFC 3339 timestamp.`,
+					Type:        graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_AuditEvent
+}
+
+func Gql__input_APIKey() *graphql.InputObject {
+	if gql__input_APIKey == nil {
+		gql__input_APIKey = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "Generated_Input_APIKey",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"id": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"name": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"prefix": &graphql.InputObjectFieldConfig{
+					Description: `First characters of the key, to tell keys apart.`,
+					Type:        graphql.String,
+				},
+				"scopes": &graphql.InputObjectFieldConfig{
+					Description: `Roles the key may act with; a subset of the roles of its owner.`,
+					Type:        graphql.NewList(graphql.String),
+				},
+				"created_at": &graphql.InputObjectFieldConfig{
+					Description: `RFC 3339 timestamps; expires_at and last_used_at are empty when unset.`,
+					Type:        graphql.String,
+				},
+				"expires_at": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+				"last_used_at": &graphql.InputObjectFieldConfig{
+					Type: graphql.String,
+				},
+			},
+		})
+	}
+	return gql__input_APIKey
+}
+
+// graphql__resolver_UserAdmin is a struct for making query, mutation and resolve fields.
+// This struct must be implemented runtime.SchemaBuilder interface.
+type graphql__resolver_UserAdmin struct {
+
+	// Automatic connection host
+	host string
+
+	// grpc dial options
+	dialOptions []grpc.DialOption
+
+	// grpc client connection.
+	// this connection may be provided by user
+	conn *grpc.ClientConn
+}
+
+// new_graphql_resolver_UserAdmin creates pointer of service struct
+func new_graphql_resolver_UserAdmin(conn *grpc.ClientConn) *graphql__resolver_UserAdmin {
+	return &graphql__resolver_UserAdmin{
+		conn: conn,
+		host: "localhost:50051",
+		dialOptions: []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		},
+	}
+}
+
+// CreateConnection() returns grpc connection which user specified or newly connected and closing function
+func (x *graphql__resolver_UserAdmin) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	// If x.conn is not nil, user injected their own connection
+	if x.conn != nil {
+		return x.conn, func() {}, nil
+	}
+
+	// Otherwise, this handler opens connection with specified host
+	conn, err := grpc.DialContext(ctx, x.host, x.dialOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, func() { conn.Close() }, nil
+}
+
+// GetQueries returns acceptable graphql.Fields for Query.
+func (x *graphql__resolver_UserAdmin) GetQueries(conn *grpc.ClientConn) graphql.Fields {
+	return graphql.Fields{
+		"users": &graphql.Field{
+			Type: Gql__type_ListUsersReply(),
+			Args: graphql.FieldConfigArgument{
+				"query": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `Case-insensitive part of the email or name.`,
+				},
+				"role": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `Only users holding the role.`,
+				},
+				"status": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `"active" or "disabled".`,
+				},
+				"order_by": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `"created_at" (default), "email" or "name".`,
+				},
+				"descending": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
+				},
+				"page_size": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: `Users per page, at most 100 (default 50).`,
+				},
+				"page_token": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `next_page_token of the previous page; the other fields must not change between pages.`,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ListUsersRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for users")
+				}
+				client := NewUserAdminClient(conn)
+				resp, err := client.ListUsers(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ListUsers")
+				}
+				return resp, nil
+			},
+		},
+		"user": &graphql.Field{
+			Type: Gql__type_User(),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req GetUserRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for user")
+				}
+				client := NewUserAdminClient(conn)
+				resp, err := client.GetUser(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC GetUser")
+				}
+				return resp, nil
+			},
+		},
+	}
+}
+
+// GetMutations returns acceptable graphql.Fields for Mutation.
+func (x *graphql__resolver_UserAdmin) GetMutations(conn *grpc.ClientConn) graphql.Fields {
+	return graphql.Fields{
+		"disableUser": &graphql.Field{
+			Type: Gql__type_User(),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"reason": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `Recorded in the audit log.`,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req DisableUserRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for disableUser")
+				}
+				client := NewUserAdminClient(conn)
+				resp, err := client.DisableUser(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC DisableUser")
+				}
+				return resp, nil
+			},
+		},
+
+		"enableUser": &graphql.Field{
+			Type: Gql__type_User(),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req EnableUserRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for enableUser")
+				}
+				client := NewUserAdminClient(conn)
+				resp, err := client.EnableUser(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC EnableUser")
+				}
+				return resp, nil
+			},
+		},
+
+		"forcePasswordReset": &graphql.Field{
+			Type: Gql__type_ForcePasswordResetReply(),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ForcePasswordResetRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for forcePasswordReset")
+				}
+				client := NewUserAdminClient(conn)
+				resp, err := client.ForcePasswordReset(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ForcePasswordReset")
+				}
+				return resp, nil
+			},
+		},
+
+		"setUserRoles": &graphql.Field{
+			Type: Gql__type_User(),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"roles": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.String),
+					Description: `Replaces the roles of the user.`,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req SetUserRolesRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for setUserRoles")
+				}
+				client := NewUserAdminClient(conn)
+				resp, err := client.SetUserRoles(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC SetUserRoles")
+				}
+				return resp, nil
+			},
+		},
+
+		"impersonateUser": &graphql.Field{
+			Type: Gql__type_ImpersonateUserReply(),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"reason": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Recorded in the audit log.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ImpersonateUserRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for impersonateUser")
+				}
+				client := NewUserAdminClient(conn)
+				resp, err := client.ImpersonateUser(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ImpersonateUser")
+				}
+				return resp, nil
+			},
+		},
+	}
+}
+
+// GetSubscriptions returns graphql.Fields for Subscription.
+func (x *graphql__resolver_UserAdmin) GetSubscriptions(conn *grpc.ClientConn) graphql.Fields {
+	return graphql.Fields{}
+}
+
+// Register package divided graphql handler "without" *grpc.ClientConn,
+// therefore gRPC connection will be opened and closed automatically.
+// Occasionally you may worry about open/close performance for each handling graphql request,
+// then you can call RegisterUserAdminGraphqlHandler with *grpc.ClientConn manually.
+func RegisterUserAdminGraphql(mux *runtime.ServeMux) error {
+	return RegisterUserAdminGraphqlHandler(mux, nil)
+}
+
+// Register package divided graphql handler "with" *grpc.ClientConn.
+// this function accepts your defined grpc connection, so that we reuse that and never close connection inside.
+// You need to close it maunally when application will terminate.
+// Otherwise, you can specify automatic opening connection with ServiceOption directive:
+//
+//	service UserAdmin {
+//	   option (graphql.service) = {
+//	       host: "host:port"
+//	       insecure: true or false
+//	   };
+//
+//	   ...with RPC definitions
+//	}
+func RegisterUserAdminGraphqlHandler(mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return mux.AddHandler(new_graphql_resolver_UserAdmin(conn))
+}
+
+// graphql__resolver_Auth is a struct for making query, mutation and resolve fields.
+// This struct must be implemented runtime.SchemaBuilder interface.
+type graphql__resolver_Auth struct {
+
+	// Automatic connection host
+	host string
+
+	// grpc dial options
+	dialOptions []grpc.DialOption
+
+	// grpc client connection.
+	// this connection may be provided by user
+	conn *grpc.ClientConn
+}
+
+// new_graphql_resolver_Auth creates pointer of service struct
+func new_graphql_resolver_Auth(conn *grpc.ClientConn) *graphql__resolver_Auth {
+	return &graphql__resolver_Auth{
+		conn: conn,
+		host: "localhost:50051",
+		dialOptions: []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		},
+	}
+}
+
+// CreateConnection() returns grpc connection which user specified or newly connected and closing function
+func (x *graphql__resolver_Auth) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	// If x.conn is not nil, user injected their own connection
+	if x.conn != nil {
+		return x.conn, func() {}, nil
+	}
+
+	// Otherwise, this handler opens connection with specified host
+	conn, err := grpc.DialContext(ctx, x.host, x.dialOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, func() { conn.Close() }, nil
+}
+
+// GetQueries returns acceptable graphql.Fields for Query.
+func (x *graphql__resolver_Auth) GetQueries(conn *grpc.ClientConn) graphql.Fields {
+	return graphql.Fields{
+		"login": &graphql.Field{
+			Type: Gql__type_LoginReply(),
+			Args: graphql.FieldConfigArgument{
+				"email": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"password": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req LoginRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for login")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.Login(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC Login")
+				}
+				return resp, nil
+			},
+		},
+		"sessions": &graphql.Field{
+			Type: Gql__type_ListSessionsReply(),
+			Args: graphql.FieldConfigArgument{},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ListSessionsRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for sessions")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.ListSessions(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ListSessions")
+				}
+				return resp, nil
+			},
+		},
+		"apiKeys": &graphql.Field{
+			Type: Gql__type_ListAPIKeysReply(),
+			Args: graphql.FieldConfigArgument{},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ListAPIKeysRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for apiKeys")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.ListAPIKeys(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ListAPIKeys")
+				}
+				return resp, nil
+			},
+		},
+		"me": &graphql.Field{
+			Type: Gql__type_User(),
+			Args: graphql.FieldConfigArgument{},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req GetMeRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for me")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.GetMe(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC GetMe")
+				}
+				return resp, nil
+			},
+		},
+		"auditEvents": &graphql.Field{
+			Type: Gql__type_ListAuditEventsReply(),
+			Args: graphql.FieldConfigArgument{
+				"actor_id": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"action": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"outcome": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"since": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `RFC 3339 bounds of the event time; since is inclusive, until exclusive.`,
+				},
+				"until": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"page_size": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: `Events per page, at most 100 (default 50).`,
+				},
+				"page_token": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `next_page_token of the previous page.`,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ListAuditEventsRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for auditEvents")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.ListAuditEvents(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ListAuditEvents")
+				}
+				return resp, nil
+			},
+		},
+		"protected": &graphql.Field{
+			Type: Gql__type_ProtectedReply(),
+			Args: graphql.FieldConfigArgument{
+				"text": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ProtectedRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for protected")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.SampleProtected(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC SampleProtected")
+				}
+				return resp, nil
+			},
+		},
+	}
+}
+
+// GetMutations returns acceptable graphql.Fields for Mutation.
+func (x *graphql__resolver_Auth) GetMutations(conn *grpc.ClientConn) graphql.Fields {
+	return graphql.Fields{
+		"register": &graphql.Field{
+			Type: Gql__type_RegisterReply(),
+			Args: graphql.FieldConfigArgument{
+				"email": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"password": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"name": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"surname": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"age": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req RegisterRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for register")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.Register(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC Register")
+				}
+				return resp, nil
+			},
+		},
+
+		"loginVerify": &graphql.Field{
+			Type: Gql__type_LoginReply(),
+			Args: graphql.FieldConfigArgument{
+				"challenge_token": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Challenge token returned by Login.`,
+					DefaultValue: "",
+				},
+				"code": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Current TOTP code or an unused recovery code.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req LoginVerifyRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for loginVerify")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.LoginVerify(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC LoginVerify")
+				}
+				return resp, nil
+			},
+		},
+
+		"beginWebAuthnLogin": &graphql.Field{
+			Type: Gql__type_BeginWebAuthnReply(),
+			Args: graphql.FieldConfigArgument{
+				"email": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `Email of the user; empty to let the authenticator offer its passkeys.`,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req BeginWebAuthnLoginRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for beginWebAuthnLogin")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.BeginWebAuthnLogin(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC BeginWebAuthnLogin")
+				}
+				return resp, nil
+			},
+		},
+
+		"finishWebAuthnLogin": &graphql.Field{
+			Type: Gql__type_LoginReply(),
+			Args: graphql.FieldConfigArgument{
+				"session_token": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"credential": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `JSON of the PublicKeyCredential returned by navigator.credentials.get.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req FinishWebAuthnLoginRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for finishWebAuthnLogin")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.FinishWebAuthnLogin(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC FinishWebAuthnLogin")
+				}
+				return resp, nil
+			},
+		},
+
+		"beginWebAuthnRegistration": &graphql.Field{
+			Type: Gql__type_BeginWebAuthnReply(),
+			Args: graphql.FieldConfigArgument{},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req BeginWebAuthnRegistrationRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for beginWebAuthnRegistration")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.BeginWebAuthnRegistration(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC BeginWebAuthnRegistration")
+				}
+				return resp, nil
+			},
+		},
+
+		"finishWebAuthnRegistration": &graphql.Field{
+			Type: Gql__type_FinishWebAuthnRegistrationReply(),
+			Args: graphql.FieldConfigArgument{
+				"session_token": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"credential": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `JSON of the PublicKeyCredential returned by navigator.credentials.create.`,
+					DefaultValue: "",
+				},
+				"name": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `Label shown to the user, such as "YubiKey".`,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req FinishWebAuthnRegistrationRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for finishWebAuthnRegistration")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.FinishWebAuthnRegistration(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC FinishWebAuthnRegistration")
+				}
+				return resp, nil
+			},
+		},
+
+		"beginOAuthLogin": &graphql.Field{
+			Type: Gql__type_BeginOAuthLoginReply(),
+			Args: graphql.FieldConfigArgument{
+				"provider": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Name of a configured OpenID Connect provider.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req BeginOAuthLoginRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for beginOAuthLogin")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.BeginOAuthLogin(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC BeginOAuthLogin")
+				}
+				return resp, nil
+			},
+		},
+
+		"completeOAuthLogin": &graphql.Field{
+			Type: Gql__type_LoginReply(),
+			Args: graphql.FieldConfigArgument{
+				"state": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"code": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Authorization code returned by the provider on the redirect URL.`,
+					DefaultValue: "",
+				},
+				"code_verifier": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req CompleteOAuthLoginRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for completeOAuthLogin")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.CompleteOAuthLogin(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC CompleteOAuthLogin")
+				}
+				return resp, nil
+			},
+		},
+
+		"enrollTotp": &graphql.Field{
+			Type: Gql__type_EnrollTOTPReply(),
+			Args: graphql.FieldConfigArgument{},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req EnrollTOTPRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for enrollTotp")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.EnrollTOTP(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC EnrollTOTP")
+				}
+				return resp, nil
+			},
+		},
+
+		"confirmTotp": &graphql.Field{
+			Type: Gql__type_ConfirmTOTPReply(),
+			Args: graphql.FieldConfigArgument{
+				"code": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ConfirmTOTPRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for confirmTotp")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.ConfirmTOTP(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ConfirmTOTP")
+				}
+				return resp, nil
+			},
+		},
+
+		"disableTotp": &graphql.Field{
+			Type: Gql__type_DisableTOTPReply(),
+			Args: graphql.FieldConfigArgument{
+				"code": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Current TOTP code or an unused recovery code.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req DisableTOTPRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for disableTotp")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.DisableTOTP(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC DisableTOTP")
+				}
+				return resp, nil
+			},
 		},
-		"protected": &graphql.Field{
-			Type: Gql__type_ProtectedReply(),
+
+		"refreshToken": &graphql.Field{
+			Type: Gql__type_LoginReply(),
 			Args: graphql.FieldConfigArgument{
-				"text": &graphql.ArgumentConfig{
+				"refresh_token": &graphql.ArgumentConfig{
 					Type:         graphql.NewNonNull(graphql.String),
 					DefaultValue: "",
 				},
 			},
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				var req ProtectedRequest
+				var req RefreshTokenRequest
 				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
-					return nil, errors.Wrap(err, "Failed to marshal request for protected")
+					return nil, errors.Wrap(err, "Failed to marshal request for refreshToken")
 				}
 				client := NewAuthClient(conn)
-				resp, err := client.SampleProtected(p.Context, &req)
+				resp, err := client.RefreshToken(p.Context, &req)
 				if err != nil {
-					return nil, errors.Wrap(err, "Failed to call RPC SampleProtected")
+					return nil, errors.Wrap(err, "Failed to call RPC RefreshToken")
 				}
 				return resp, nil
 			},
 		},
-	}
-}
 
-// GetMutations returns acceptable graphql.Fields for Mutation.
-func (x *graphql__resolver_Auth) GetMutations(conn *grpc.ClientConn) graphql.Fields {
-	return graphql.Fields{
-		"register": &graphql.Field{
-			Type: Gql__type_RegisterReply(),
+		"verifyEmail": &graphql.Field{
+			Type: Gql__type_VerifyEmailReply(),
+			Args: graphql.FieldConfigArgument{
+				"token": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Token from the verification mail.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req VerifyEmailRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for verifyEmail")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.VerifyEmail(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC VerifyEmail")
+				}
+				return resp, nil
+			},
+		},
+
+		"resendVerification": &graphql.Field{
+			Type: Gql__type_ResendVerificationReply(),
 			Args: graphql.FieldConfigArgument{
 				"email": &graphql.ArgumentConfig{
 					Type:         graphql.NewNonNull(graphql.String),
 					DefaultValue: "",
 				},
-				"password": &graphql.ArgumentConfig{
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ResendVerificationRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for resendVerification")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.ResendVerification(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ResendVerification")
+				}
+				return resp, nil
+			},
+		},
+
+		"requestPasswordReset": &graphql.Field{
+			Type: Gql__type_RequestPasswordResetReply(),
+			Args: graphql.FieldConfigArgument{
+				"email": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req RequestPasswordResetRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for requestPasswordReset")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.RequestPasswordReset(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC RequestPasswordReset")
+				}
+				return resp, nil
+			},
+		},
+
+		"resetPassword": &graphql.Field{
+			Type: Gql__type_ResetPasswordReply(),
+			Args: graphql.FieldConfigArgument{
+				"token": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Token from the password reset mail.`,
+					DefaultValue: "",
+				},
+				"new_password": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ResetPasswordRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for resetPassword")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.ResetPassword(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ResetPassword")
+				}
+				return resp, nil
+			},
+		},
+
+		"requestMagicLink": &graphql.Field{
+			Type: Gql__type_RequestMagicLinkReply(),
+			Args: graphql.FieldConfigArgument{
+				"email": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req RequestMagicLinkRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for requestMagicLink")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.RequestMagicLink(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC RequestMagicLink")
+				}
+				return resp, nil
+			},
+		},
+
+		"consumeMagicLink": &graphql.Field{
+			Type: Gql__type_LoginReply(),
+			Args: graphql.FieldConfigArgument{
+				"token": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Token from the magic link mail.`,
+					DefaultValue: "",
+				},
+				"nonce": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `nonce of the RequestMagicLink reply.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ConsumeMagicLinkRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for consumeMagicLink")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.ConsumeMagicLink(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ConsumeMagicLink")
+				}
+				return resp, nil
+			},
+		},
+
+		"logout": &graphql.Field{
+			Type: Gql__type_LogoutReply(),
+			Args: graphql.FieldConfigArgument{
+				"refresh_token": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: `Optional refresh token whose family is revoked together with the access token.`,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req LogoutRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for logout")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.Logout(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC Logout")
+				}
+				return resp, nil
+			},
+		},
+
+		"revokeAllSessions": &graphql.Field{
+			Type: Gql__type_RevokeAllSessionsReply(),
+			Args: graphql.FieldConfigArgument{},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req RevokeAllSessionsRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for revokeAllSessions")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.RevokeAllSessions(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC RevokeAllSessions")
+				}
+				return resp, nil
+			},
+		},
+
+		"revokeSession": &graphql.Field{
+			Type: Gql__type_RevokeSessionReply(),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
 					Type:         graphql.NewNonNull(graphql.String),
 					DefaultValue: "",
 				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req RevokeSessionRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for revokeSession")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.RevokeSession(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC RevokeSession")
+				}
+				return resp, nil
+			},
+		},
+
+		"revokeOtherSessions": &graphql.Field{
+			Type: Gql__type_RevokeOtherSessionsReply(),
+			Args: graphql.FieldConfigArgument{},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req RevokeOtherSessionsRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for revokeOtherSessions")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.RevokeOtherSessions(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC RevokeOtherSessions")
+				}
+				return resp, nil
+			},
+		},
+
+		"createApiKey": &graphql.Field{
+			Type: Gql__type_CreateAPIKeyReply(),
+			Args: graphql.FieldConfigArgument{
 				"name": &graphql.ArgumentConfig{
 					Type:         graphql.NewNonNull(graphql.String),
 					DefaultValue: "",
 				},
-				"surname": &graphql.ArgumentConfig{
+				"scopes": &graphql.ArgumentConfig{
+					Type: graphql.NewList(graphql.String),
+				},
+				"expires_in": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: `Lifetime of the key in seconds; 0 means the key does not expire.`,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req CreateAPIKeyRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for createApiKey")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.CreateAPIKey(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC CreateAPIKey")
+				}
+				return resp, nil
+			},
+		},
+
+		"revokeApiKey": &graphql.Field{
+			Type: Gql__type_RevokeAPIKeyReply(),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
 					Type:         graphql.NewNonNull(graphql.String),
 					DefaultValue: "",
 				},
-				"age": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.Int),
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req RevokeAPIKeyRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for revokeApiKey")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.RevokeAPIKey(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC RevokeAPIKey")
+				}
+				return resp, nil
+			},
+		},
+
+		"updateProfile": &graphql.Field{
+			Type: Gql__type_User(),
+			Args: graphql.FieldConfigArgument{
+				"user": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(Gql__input_User()),
+				},
+				"update_mask": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Description: `Field mask: paths of the fields of user to update, out of name, surname, age
+ and desc. A plain list, since the GraphQL gateway has no FieldMask support.`,
+					DefaultValue: "",
 				},
 			},
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				var req RegisterRequest
+				var req UpdateProfileRequest
 				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
-					return nil, errors.Wrap(err, "Failed to marshal request for register")
+					return nil, errors.Wrap(err, "Failed to marshal request for updateProfile")
 				}
 				client := NewAuthClient(conn)
-				resp, err := client.Register(p.Context, &req)
+				resp, err := client.UpdateProfile(p.Context, &req)
 				if err != nil {
-					return nil, errors.Wrap(err, "Failed to call RPC Register")
+					return nil, errors.Wrap(err, "Failed to call RPC UpdateProfile")
+				}
+				return resp, nil
+			},
+		},
+
+		"changePassword": &graphql.Field{
+			Type: Gql__type_ChangePasswordReply(),
+			Args: graphql.FieldConfigArgument{
+				"current_password": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+				"new_password": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req ChangePasswordRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for changePassword")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.ChangePassword(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC ChangePassword")
+				}
+				return resp, nil
+			},
+		},
+
+		"deleteAccount": &graphql.Field{
+			Type: Gql__type_DeleteAccountReply(),
+			Args: graphql.FieldConfigArgument{
+				"password": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Current password, to confirm the deletion.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req DeleteAccountRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for deleteAccount")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.DeleteAccount(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC DeleteAccount")
+				}
+				return resp, nil
+			},
+		},
+
+		"unlockUser": &graphql.Field{
+			Type: Gql__type_UnlockUserReply(),
+			Args: graphql.FieldConfigArgument{
+				"email": &graphql.ArgumentConfig{
+					Type:         graphql.NewNonNull(graphql.String),
+					Description:  `Email of the account whose failed login attempts are cleared.`,
+					DefaultValue: "",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var req UnlockUserRequest
+				if err := runtime.MarshalRequest(p.Args, &req, false); err != nil {
+					return nil, errors.Wrap(err, "Failed to marshal request for unlockUser")
+				}
+				client := NewAuthClient(conn)
+				resp, err := client.UnlockUser(p.Context, &req)
+				if err != nil {
+					return nil, errors.Wrap(err, "Failed to call RPC UnlockUser")
 				}
 				return resp, nil
 			},
@@ -240,27 +240,3326 @@ func (x *RegisterRequest) GetAge() int32 {
 }
 
 type LoginReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Short-lived access token (JWT).
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Opaque, single-use token exchanged for a new token pair via RefreshToken.
+	RefreshToken string `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// Lifetime of the access token in seconds.
+	ExpiresIn int64 `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	// Set when the user has two-factor authentication enabled. No tokens are
+	// issued; the challenge token must be exchanged through LoginVerify.
+	TwoFactorRequired bool   `protobuf:"varint,4,opt,name=two_factor_required,json=twoFactorRequired,proto3" json:"two_factor_required,omitempty"`
+	ChallengeToken    string `protobuf:"bytes,5,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LoginReply) Reset() {
+	*x = LoginReply{}
+	mi := &file_authenticator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginReply) ProtoMessage() {}
+
+func (x *LoginReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginReply.ProtoReflect.Descriptor instead.
+func (*LoginReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LoginReply) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *LoginReply) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *LoginReply) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *LoginReply) GetTwoFactorRequired() bool {
+	if x != nil {
+		return x.TwoFactorRequired
+	}
+	return false
+}
+
+func (x *LoginReply) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+type LoginVerifyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Challenge token returned by Login.
+	ChallengeToken string `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	// Current TOTP code or an unused recovery code.
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginVerifyRequest) Reset() {
+	*x = LoginVerifyRequest{}
+	mi := &file_authenticator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginVerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginVerifyRequest) ProtoMessage() {}
+
+func (x *LoginVerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginVerifyRequest.ProtoReflect.Descriptor instead.
+func (*LoginVerifyRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LoginVerifyRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *LoginVerifyRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type BeginOAuthLoginRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of a configured OpenID Connect provider.
+	Provider      string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginOAuthLoginRequest) Reset() {
+	*x = BeginOAuthLoginRequest{}
+	mi := &file_authenticator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginOAuthLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginOAuthLoginRequest) ProtoMessage() {}
+
+func (x *BeginOAuthLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginOAuthLoginRequest.ProtoReflect.Descriptor instead.
+func (*BeginOAuthLoginRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BeginOAuthLoginRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+type BeginOAuthLoginReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// URL the user is sent to in order to sign in with the provider.
+	AuthorizeUrl string `protobuf:"bytes,1,opt,name=authorize_url,json=authorizeUrl,proto3" json:"authorize_url,omitempty"`
+	// Signed state, passed back to CompleteOAuthLogin together with the code.
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// PKCE verifier; the client keeps it and passes it to CompleteOAuthLogin.
+	CodeVerifier  string `protobuf:"bytes,3,opt,name=code_verifier,json=codeVerifier,proto3" json:"code_verifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginOAuthLoginReply) Reset() {
+	*x = BeginOAuthLoginReply{}
+	mi := &file_authenticator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginOAuthLoginReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginOAuthLoginReply) ProtoMessage() {}
+
+func (x *BeginOAuthLoginReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginOAuthLoginReply.ProtoReflect.Descriptor instead.
+func (*BeginOAuthLoginReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BeginOAuthLoginReply) GetAuthorizeUrl() string {
+	if x != nil {
+		return x.AuthorizeUrl
+	}
+	return ""
+}
+
+func (x *BeginOAuthLoginReply) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *BeginOAuthLoginReply) GetCodeVerifier() string {
+	if x != nil {
+		return x.CodeVerifier
+	}
+	return ""
+}
+
+type CompleteOAuthLoginRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	State string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	// Authorization code returned by the provider on the redirect URL.
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	CodeVerifier  string `protobuf:"bytes,3,opt,name=code_verifier,json=codeVerifier,proto3" json:"code_verifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteOAuthLoginRequest) Reset() {
+	*x = CompleteOAuthLoginRequest{}
+	mi := &file_authenticator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteOAuthLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteOAuthLoginRequest) ProtoMessage() {}
+
+func (x *CompleteOAuthLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteOAuthLoginRequest.ProtoReflect.Descriptor instead.
+func (*CompleteOAuthLoginRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CompleteOAuthLoginRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CompleteOAuthLoginRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CompleteOAuthLoginRequest) GetCodeVerifier() string {
+	if x != nil {
+		return x.CodeVerifier
+	}
+	return ""
+}
+
+type EnrollTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPRequest) Reset() {
+	*x = EnrollTOTPRequest{}
+	mi := &file_authenticator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPRequest) ProtoMessage() {}
+
+func (x *EnrollTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPRequest.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{9}
+}
+
+type EnrollTOTPReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Base32 encoded secret, for manual entry.
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// otpauth:// URI, usually rendered as a QR code.
+	OtpauthUri    string `protobuf:"bytes,2,opt,name=otpauth_uri,json=otpauthUri,proto3" json:"otpauth_uri,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPReply) Reset() {
+	*x = EnrollTOTPReply{}
+	mi := &file_authenticator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPReply) ProtoMessage() {}
+
+func (x *EnrollTOTPReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPReply.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EnrollTOTPReply) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *EnrollTOTPReply) GetOtpauthUri() string {
+	if x != nil {
+		return x.OtpauthUri
+	}
+	return ""
+}
+
+type ConfirmTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPRequest) Reset() {
+	*x = ConfirmTOTPRequest{}
+	mi := &file_authenticator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPRequest) ProtoMessage() {}
+
+func (x *ConfirmTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTOTPRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ConfirmTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmTOTPReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Single-use codes that replace a TOTP code when the device is lost.
+	// They are only shown once.
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPReply) Reset() {
+	*x = ConfirmTOTPReply{}
+	mi := &file_authenticator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTOTPReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPReply) ProtoMessage() {}
+
+func (x *ConfirmTOTPReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTOTPReply.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ConfirmTOTPReply) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+type DisableTOTPRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Current TOTP code or an unused recovery code.
+	Code          string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableTOTPRequest) Reset() {
+	*x = DisableTOTPRequest{}
+	mi := &file_authenticator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTOTPRequest) ProtoMessage() {}
+
+func (x *DisableTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTOTPRequest.ProtoReflect.Descriptor instead.
+func (*DisableTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DisableTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DisableTOTPReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableTOTPReply) Reset() {
+	*x = DisableTOTPReply{}
+	mi := &file_authenticator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTOTPReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTOTPReply) ProtoMessage() {}
+
+func (x *DisableTOTPReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTOTPReply.ProtoReflect.Descriptor instead.
+func (*DisableTOTPReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DisableTOTPReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
+	mi := &file_authenticator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenRequest) ProtoMessage() {}
+
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RefreshTokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type VerifyEmailRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Token from the verification mail.
+	Token         string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailRequest) Reset() {
+	*x = VerifyEmailRequest{}
+	mi := &file_authenticator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailRequest) ProtoMessage() {}
+
+func (x *VerifyEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *VerifyEmailRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type VerifyEmailReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailReply) Reset() {
+	*x = VerifyEmailReply{}
+	mi := &file_authenticator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailReply) ProtoMessage() {}
+
+func (x *VerifyEmailReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailReply.ProtoReflect.Descriptor instead.
+func (*VerifyEmailReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *VerifyEmailReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+type ResendVerificationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendVerificationRequest) Reset() {
+	*x = ResendVerificationRequest{}
+	mi := &file_authenticator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendVerificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendVerificationRequest) ProtoMessage() {}
+
+func (x *ResendVerificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendVerificationRequest.ProtoReflect.Descriptor instead.
+func (*ResendVerificationRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ResendVerificationRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type ResendVerificationReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendVerificationReply) Reset() {
+	*x = ResendVerificationReply{}
+	mi := &file_authenticator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendVerificationReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendVerificationReply) ProtoMessage() {}
+
+func (x *ResendVerificationReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendVerificationReply.ProtoReflect.Descriptor instead.
+func (*ResendVerificationReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResendVerificationReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+type RequestPasswordResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetRequest) Reset() {
+	*x = RequestPasswordResetRequest{}
+	mi := &file_authenticator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetRequest) ProtoMessage() {}
+
+func (x *RequestPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RequestPasswordResetRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type RequestPasswordResetReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetReply) Reset() {
+	*x = RequestPasswordResetReply{}
+	mi := &file_authenticator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetReply) ProtoMessage() {}
+
+func (x *RequestPasswordResetReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetReply.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RequestPasswordResetReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+type BeginWebAuthnRegistrationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginWebAuthnRegistrationRequest) Reset() {
+	*x = BeginWebAuthnRegistrationRequest{}
+	mi := &file_authenticator_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginWebAuthnRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginWebAuthnRegistrationRequest) ProtoMessage() {}
+
+func (x *BeginWebAuthnRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginWebAuthnRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*BeginWebAuthnRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{22}
+}
+
+type BeginWebAuthnLoginRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Email of the user; empty to let the authenticator offer its passkeys.
+	Email         string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginWebAuthnLoginRequest) Reset() {
+	*x = BeginWebAuthnLoginRequest{}
+	mi := &file_authenticator_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginWebAuthnLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginWebAuthnLoginRequest) ProtoMessage() {}
+
+func (x *BeginWebAuthnLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginWebAuthnLoginRequest.ProtoReflect.Descriptor instead.
+func (*BeginWebAuthnLoginRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BeginWebAuthnLoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type BeginWebAuthnReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// JSON options for navigator.credentials.create or .get.
+	Options string `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// Single-use token of the ceremony, sent back with its result.
+	SessionToken  string `protobuf:"bytes,2,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginWebAuthnReply) Reset() {
+	*x = BeginWebAuthnReply{}
+	mi := &file_authenticator_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginWebAuthnReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginWebAuthnReply) ProtoMessage() {}
+
+func (x *BeginWebAuthnReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginWebAuthnReply.ProtoReflect.Descriptor instead.
+func (*BeginWebAuthnReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BeginWebAuthnReply) GetOptions() string {
+	if x != nil {
+		return x.Options
+	}
+	return ""
+}
+
+func (x *BeginWebAuthnReply) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+type FinishWebAuthnRegistrationRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SessionToken string                 `protobuf:"bytes,1,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	// JSON of the PublicKeyCredential returned by navigator.credentials.create.
+	Credential string `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"`
+	// Label shown to the user, such as "YubiKey".
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishWebAuthnRegistrationRequest) Reset() {
+	*x = FinishWebAuthnRegistrationRequest{}
+	mi := &file_authenticator_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishWebAuthnRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishWebAuthnRegistrationRequest) ProtoMessage() {}
+
+func (x *FinishWebAuthnRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishWebAuthnRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*FinishWebAuthnRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *FinishWebAuthnRegistrationRequest) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+func (x *FinishWebAuthnRegistrationRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+func (x *FinishWebAuthnRegistrationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type FinishWebAuthnRegistrationReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Base64url encoded ID of the credential.
+	CredentialId  string `protobuf:"bytes,1,opt,name=credential_id,json=credentialId,proto3" json:"credential_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishWebAuthnRegistrationReply) Reset() {
+	*x = FinishWebAuthnRegistrationReply{}
+	mi := &file_authenticator_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishWebAuthnRegistrationReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishWebAuthnRegistrationReply) ProtoMessage() {}
+
+func (x *FinishWebAuthnRegistrationReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishWebAuthnRegistrationReply.ProtoReflect.Descriptor instead.
+func (*FinishWebAuthnRegistrationReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *FinishWebAuthnRegistrationReply) GetCredentialId() string {
+	if x != nil {
+		return x.CredentialId
+	}
+	return ""
+}
+
+type FinishWebAuthnLoginRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SessionToken string                 `protobuf:"bytes,1,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	// JSON of the PublicKeyCredential returned by navigator.credentials.get.
+	Credential    string `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishWebAuthnLoginRequest) Reset() {
+	*x = FinishWebAuthnLoginRequest{}
+	mi := &file_authenticator_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishWebAuthnLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishWebAuthnLoginRequest) ProtoMessage() {}
+
+func (x *FinishWebAuthnLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishWebAuthnLoginRequest.ProtoReflect.Descriptor instead.
+func (*FinishWebAuthnLoginRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *FinishWebAuthnLoginRequest) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+func (x *FinishWebAuthnLoginRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+type RequestMagicLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestMagicLinkRequest) Reset() {
+	*x = RequestMagicLinkRequest{}
+	mi := &file_authenticator_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestMagicLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestMagicLinkRequest) ProtoMessage() {}
+
+func (x *RequestMagicLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestMagicLinkRequest.ProtoReflect.Descriptor instead.
+func (*RequestMagicLinkRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RequestMagicLinkRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type RequestMagicLinkReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Reply string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	// Kept by the requesting device and sent with the token to ConsumeMagicLink, so that
+	// the link only logs in the device that asked for it.
+	Nonce         string `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestMagicLinkReply) Reset() {
+	*x = RequestMagicLinkReply{}
+	mi := &file_authenticator_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestMagicLinkReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestMagicLinkReply) ProtoMessage() {}
+
+func (x *RequestMagicLinkReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestMagicLinkReply.ProtoReflect.Descriptor instead.
+func (*RequestMagicLinkReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RequestMagicLinkReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+func (x *RequestMagicLinkReply) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+type ConsumeMagicLinkRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Token from the magic link mail.
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// nonce of the RequestMagicLink reply.
+	Nonce         string `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsumeMagicLinkRequest) Reset() {
+	*x = ConsumeMagicLinkRequest{}
+	mi := &file_authenticator_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumeMagicLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumeMagicLinkRequest) ProtoMessage() {}
+
+func (x *ConsumeMagicLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumeMagicLinkRequest.ProtoReflect.Descriptor instead.
+func (*ConsumeMagicLinkRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ConsumeMagicLinkRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ConsumeMagicLinkRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+type ResetPasswordRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Token from the password reset mail.
+	Token         string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	NewPassword   string `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetPasswordRequest) Reset() {
+	*x = ResetPasswordRequest{}
+	mi := &file_authenticator_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetPasswordRequest) ProtoMessage() {}
+
+func (x *ResetPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetPasswordRequest.ProtoReflect.Descriptor instead.
+func (*ResetPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ResetPasswordRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ResetPasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ResetPasswordReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetPasswordReply) Reset() {
+	*x = ResetPasswordReply{}
+	mi := &file_authenticator_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetPasswordReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetPasswordReply) ProtoMessage() {}
+
+func (x *ResetPasswordReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetPasswordReply.ProtoReflect.Descriptor instead.
+func (*ResetPasswordReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ResetPasswordReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+type LogoutRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional refresh token whose family is revoked together with the access token.
+	RefreshToken  string `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	mi := &file_authenticator_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LogoutRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type LogoutReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutReply) Reset() {
+	*x = LogoutReply{}
+	mi := &file_authenticator_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutReply) ProtoMessage() {}
+
+func (x *LogoutReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutReply.ProtoReflect.Descriptor instead.
+func (*LogoutReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *LogoutReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+type RevokeAllSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAllSessionsRequest) Reset() {
+	*x = RevokeAllSessionsRequest{}
+	mi := &file_authenticator_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllSessionsRequest) ProtoMessage() {}
+
+func (x *RevokeAllSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAllSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{35}
+}
+
+type RevokeAllSessionsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAllSessionsReply) Reset() {
+	*x = RevokeAllSessionsReply{}
+	mi := &file_authenticator_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllSessionsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllSessionsReply) ProtoMessage() {}
+
+func (x *RevokeAllSessionsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllSessionsReply.ProtoReflect.Descriptor instead.
+func (*RevokeAllSessionsReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RevokeAllSessionsReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+// Session is a login on a device. Refreshing the tokens of a login keeps its session.
+type Session struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserAgent string                 `protobuf:"bytes,2,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Ip        string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	// RFC 3339 timestamps.
+	CreatedAt  string `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastSeenAt string `protobuf:"bytes,5,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	// Set for the session of the token used for the call.
+	Current       bool `protobuf:"varint,6,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_authenticator_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *Session) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+func (x *Session) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_authenticator_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{38}
+}
+
+type ListSessionsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsReply) Reset() {
+	*x = ListSessionsReply{}
+	mi := &file_authenticator_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsReply) ProtoMessage() {}
+
+func (x *ListSessionsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsReply.ProtoReflect.Descriptor instead.
+func (*ListSessionsReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListSessionsReply) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type RevokeSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_authenticator_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionRequest) ProtoMessage() {}
+
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RevokeSessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeSessionReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionReply) Reset() {
+	*x = RevokeSessionReply{}
+	mi := &file_authenticator_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionReply) ProtoMessage() {}
+
+func (x *RevokeSessionReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionReply.ProtoReflect.Descriptor instead.
+func (*RevokeSessionReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RevokeSessionReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+type RevokeOtherSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeOtherSessionsRequest) Reset() {
+	*x = RevokeOtherSessionsRequest{}
+	mi := &file_authenticator_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeOtherSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeOtherSessionsRequest) ProtoMessage() {}
+
+func (x *RevokeOtherSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeOtherSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeOtherSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{42}
+}
+
+type RevokeOtherSessionsReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of sessions revoked.
+	Revoked       int32 `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeOtherSessionsReply) Reset() {
+	*x = RevokeOtherSessionsReply{}
+	mi := &file_authenticator_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeOtherSessionsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeOtherSessionsReply) ProtoMessage() {}
+
+func (x *RevokeOtherSessionsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeOtherSessionsReply.ProtoReflect.Descriptor instead.
+func (*RevokeOtherSessionsReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RevokeOtherSessionsReply) GetRevoked() int32 {
+	if x != nil {
+		return x.Revoked
+	}
+	return 0
+}
+
+type APIKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// First characters of the key, to tell keys apart.
+	Prefix string `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Roles the key may act with; a subset of the roles of its owner.
+	Scopes []string `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// RFC 3339 timestamps; expires_at and last_used_at are empty when unset.
+	CreatedAt     string `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     string `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	LastUsedAt    string `protobuf:"bytes,7,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_authenticator_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *APIKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *APIKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *APIKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetLastUsedAt() string {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return ""
+}
+
+type CreateAPIKeyRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// Lifetime of the key in seconds; 0 means the key does not expire.
+	ExpiresIn     int64 `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_authenticator_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+type CreateAPIKeyReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The key, sent as the x-api-key header. It is only shown once.
+	Key           string  `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ApiKey        *APIKey `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyReply) Reset() {
+	*x = CreateAPIKeyReply{}
+	mi := &file_authenticator_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyReply) ProtoMessage() {}
+
+func (x *CreateAPIKeyReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyReply.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CreateAPIKeyReply) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyReply) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_authenticator_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{47}
+}
+
+type ListAPIKeysReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysReply) Reset() {
+	*x = ListAPIKeysReply{}
+	mi := &file_authenticator_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysReply) ProtoMessage() {}
+
+func (x *ListAPIKeysReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysReply.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListAPIKeysReply) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_authenticator_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *RevokeAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeAPIKeyReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyReply) Reset() {
+	*x = RevokeAPIKeyReply{}
+	mi := &file_authenticator_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyReply) ProtoMessage() {}
+
+func (x *RevokeAPIKeyReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyReply.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RevokeAPIKeyReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+// User is the account of the caller. The password hash never leaves the service.
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	EmailVerified bool                   `protobuf:"varint,3,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Surname       string                 `protobuf:"bytes,5,opt,name=surname,proto3" json:"surname,omitempty"`
+	Age           int32                  `protobuf:"varint,6,opt,name=age,proto3" json:"age,omitempty"`
+	Desc          string                 `protobuf:"bytes,7,opt,name=desc,proto3" json:"desc,omitempty"`
+	Roles         []string               `protobuf:"bytes,8,rep,name=roles,proto3" json:"roles,omitempty"`
+	TotpEnabled   bool                   `protobuf:"varint,9,opt,name=totp_enabled,json=totpEnabled,proto3" json:"totp_enabled,omitempty"`
+	// RFC 3339 timestamps.
+	CreatedAt string `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt string `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Disabled users cannot log in; see UserAdmin.DisableUser.
+	Disabled      bool `protobuf:"varint,12,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_authenticator_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetSurname() string {
+	if x != nil {
+		return x.Surname
+	}
+	return ""
+}
+
+func (x *User) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+func (x *User) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *User) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *User) GetTotpEnabled() bool {
+	if x != nil {
+		return x.TotpEnabled
+	}
+	return false
+}
+
+func (x *User) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *User) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *User) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type GetMeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMeRequest) Reset() {
+	*x = GetMeRequest{}
+	mi := &file_authenticator_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMeRequest) ProtoMessage() {}
+
+func (x *GetMeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMeRequest.ProtoReflect.Descriptor instead.
+func (*GetMeRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{52}
+}
+
+type UpdateProfileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// Field mask: paths of the fields of user to update, out of name, surname, age
+	// and desc. A plain list, since the GraphQL gateway has no FieldMask support.
+	UpdateMask    []string `protobuf:"bytes,2,rep,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProfileRequest) Reset() {
+	*x = UpdateProfileRequest{}
+	mi := &file_authenticator_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileRequest) ProtoMessage() {}
+
+func (x *UpdateProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProfileRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *UpdateProfileRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequest) GetUpdateMask() []string {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type ChangePasswordRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CurrentPassword string                 `protobuf:"bytes,1,opt,name=current_password,json=currentPassword,proto3" json:"current_password,omitempty"`
+	NewPassword     string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_authenticator_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ChangePasswordRequest) GetCurrentPassword() string {
+	if x != nil {
+		return x.CurrentPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ChangePasswordReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Reply string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	// Access tokens issued before the change are revoked. Callers signed in with a
+	// session get a new access token for it; its refresh token stays valid.
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	// Lifetime of the access token in seconds.
+	ExpiresIn     int64 `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordReply) Reset() {
+	*x = ChangePasswordReply{}
+	mi := &file_authenticator_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordReply) ProtoMessage() {}
+
+func (x *ChangePasswordReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordReply.ProtoReflect.Descriptor instead.
+func (*ChangePasswordReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ChangePasswordReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+func (x *ChangePasswordReply) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ChangePasswordReply) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+type DeleteAccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Current password, to confirm the deletion.
+	Password      string `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountRequest) Reset() {
+	*x = DeleteAccountRequest{}
+	mi := &file_authenticator_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountRequest) ProtoMessage() {}
+
+func (x *DeleteAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAccountRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *DeleteAccountRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type DeleteAccountReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountReply) Reset() {
+	*x = DeleteAccountReply{}
+	mi := &file_authenticator_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountReply) ProtoMessage() {}
+
+func (x *DeleteAccountReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountReply.ProtoReflect.Descriptor instead.
+func (*DeleteAccountReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *DeleteAccountReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+type UnlockUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Email of the account whose failed login attempts are cleared.
+	Email         string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockUserRequest) Reset() {
+	*x = UnlockUserRequest{}
+	mi := &file_authenticator_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserRequest) ProtoMessage() {}
+
+func (x *UnlockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockUserRequest.ProtoReflect.Descriptor instead.
+func (*UnlockUserRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *UnlockUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type UnlockUserReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockUserReply) Reset() {
+	*x = UnlockUserReply{}
+	mi := &file_authenticator_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockUserReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserReply) ProtoMessage() {}
+
+func (x *UnlockUserReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockUserReply.ProtoReflect.Descriptor instead.
+func (*UnlockUserReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *UnlockUserReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+// AuditEvent is a recorded security event, such as a login or a token revocation.
+type AuditEvent struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// "success" or "failure".
+	Outcome    string `protobuf:"bytes,3,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	ActorId    string `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorEmail string `protobuf:"bytes,5,opt,name=actor_email,json=actorEmail,proto3" json:"actor_email,omitempty"`
+	TargetId   string `protobuf:"bytes,6,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	Ip         string `protobuf:"bytes,7,opt,name=ip,proto3" json:"ip,omitempty"`
+	UserAgent  string `protobuf:"bytes,8,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	RequestId  string `protobuf:"bytes,9,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Reason     string `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	// RFC 3339 timestamp.
+	CreatedAt     string `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_authenticator_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *AuditEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorEmail() string {
+	if x != nil {
+		return x.ActorEmail
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// ListAuditEventsRequest filters the events of the caller's tenant, newest first. Empty
+// filters match every event.
+type ListAuditEventsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	ActorId string                 `protobuf:"bytes,1,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Action  string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Outcome string                 `protobuf:"bytes,3,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// RFC 3339 bounds of the event time; since is inclusive, until exclusive.
+	Since string `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until string `protobuf:"bytes,5,opt,name=until,proto3" json:"until,omitempty"`
+	// Events per page, at most 100 (default 50).
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page.
+	PageToken     string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_authenticator_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ListAuditEventsRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListAuditEventsReply struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsReply) Reset() {
+	*x = ListAuditEventsReply{}
+	mi := &file_authenticator_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsReply) ProtoMessage() {}
+
+func (x *ListAuditEventsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsReply.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ListAuditEventsReply) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsReply) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type RegisterReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterReply) Reset() {
+	*x = RegisterReply{}
+	mi := &file_authenticator_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterReply) ProtoMessage() {}
+
+func (x *RegisterReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterReply.ProtoReflect.Descriptor instead.
+func (*RegisterReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RegisterReply) GetReply() string {
+	if x != nil {
+		return x.Reply
+	}
+	return ""
+}
+
+// ListUsersRequest filters the users of the caller's tenant. Empty filters match every user.
+type ListUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Case-insensitive part of the email or name.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Only users holding the role.
+	Role string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	// "active" or "disabled".
+	Status string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// "created_at" (default), "email" or "name".
+	OrderBy    string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	Descending bool   `protobuf:"varint,5,opt,name=descending,proto3" json:"descending,omitempty"`
+	// Users per page, at most 100 (default 50).
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page; the other fields must not change between pages.
+	PageToken     string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_authenticator_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ListUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListUsersReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersReply) Reset() {
+	*x = ListUsersReply{}
+	mi := &file_authenticator_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersReply) ProtoMessage() {}
+
+func (x *ListUsersReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersReply.ProtoReflect.Descriptor instead.
+func (*ListUsersReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ListUsersReply) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersReply) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoginReply) Reset() {
-	*x = LoginReply{}
-	mi := &file_authenticator_proto_msgTypes[4]
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_authenticator_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoginReply) String() string {
+func (x *GetUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoginReply) ProtoMessage() {}
+func (*GetUserRequest) ProtoMessage() {}
 
-func (x *LoginReply) ProtoReflect() protoreflect.Message {
-	mi := &file_authenticator_proto_msgTypes[4]
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -271,40 +3570,181 @@ func (x *LoginReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoginReply.ProtoReflect.Descriptor instead.
-func (*LoginReply) Descriptor() ([]byte, []int) {
-	return file_authenticator_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *LoginReply) GetToken() string {
+func (x *GetUserRequest) GetId() string {
 	if x != nil {
-		return x.Token
+		return x.Id
 	}
 	return ""
 }
 
-type RegisterReply struct {
+type DisableUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Recorded in the audit log.
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableUserRequest) Reset() {
+	*x = DisableUserRequest{}
+	mi := &file_authenticator_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableUserRequest) ProtoMessage() {}
+
+func (x *DisableUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableUserRequest.ProtoReflect.Descriptor instead.
+func (*DisableUserRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *DisableUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DisableUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type EnableUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableUserRequest) Reset() {
+	*x = EnableUserRequest{}
+	mi := &file_authenticator_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableUserRequest) ProtoMessage() {}
+
+func (x *EnableUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableUserRequest.ProtoReflect.Descriptor instead.
+func (*EnableUserRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *EnableUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ForcePasswordResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForcePasswordResetRequest) Reset() {
+	*x = ForcePasswordResetRequest{}
+	mi := &file_authenticator_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForcePasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForcePasswordResetRequest) ProtoMessage() {}
+
+func (x *ForcePasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForcePasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*ForcePasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ForcePasswordResetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ForcePasswordResetReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Reply         string                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterReply) Reset() {
-	*x = RegisterReply{}
-	mi := &file_authenticator_proto_msgTypes[5]
+func (x *ForcePasswordResetReply) Reset() {
+	*x = ForcePasswordResetReply{}
+	mi := &file_authenticator_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterReply) String() string {
+func (x *ForcePasswordResetReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterReply) ProtoMessage() {}
+func (*ForcePasswordResetReply) ProtoMessage() {}
 
-func (x *RegisterReply) ProtoReflect() protoreflect.Message {
-	mi := &file_authenticator_proto_msgTypes[5]
+func (x *ForcePasswordResetReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,23 +3755,184 @@ func (x *RegisterReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterReply.ProtoReflect.Descriptor instead.
-func (*RegisterReply) Descriptor() ([]byte, []int) {
-	return file_authenticator_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ForcePasswordResetReply.ProtoReflect.Descriptor instead.
+func (*ForcePasswordResetReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *RegisterReply) GetReply() string {
+func (x *ForcePasswordResetReply) GetReply() string {
 	if x != nil {
 		return x.Reply
 	}
 	return ""
 }
 
+type SetUserRolesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Replaces the roles of the user.
+	Roles         []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRolesRequest) Reset() {
+	*x = SetUserRolesRequest{}
+	mi := &file_authenticator_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRolesRequest) ProtoMessage() {}
+
+func (x *SetUserRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRolesRequest.ProtoReflect.Descriptor instead.
+func (*SetUserRolesRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *SetUserRolesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetUserRolesRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type ImpersonateUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Recorded in the audit log.
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImpersonateUserRequest) Reset() {
+	*x = ImpersonateUserRequest{}
+	mi := &file_authenticator_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImpersonateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImpersonateUserRequest) ProtoMessage() {}
+
+func (x *ImpersonateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImpersonateUserRequest.ProtoReflect.Descriptor instead.
+func (*ImpersonateUserRequest) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *ImpersonateUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImpersonateUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ImpersonateUserReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Access token of the user that names the admin in its act claim. It cannot be
+	// refreshed.
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Lifetime of the token in seconds.
+	ExpiresIn     int64 `protobuf:"varint,2,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImpersonateUserReply) Reset() {
+	*x = ImpersonateUserReply{}
+	mi := &file_authenticator_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImpersonateUserReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImpersonateUserReply) ProtoMessage() {}
+
+func (x *ImpersonateUserReply) ProtoReflect() protoreflect.Message {
+	mi := &file_authenticator_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImpersonateUserReply.ProtoReflect.Descriptor instead.
+func (*ImpersonateUserReply) Descriptor() ([]byte, []int) {
+	return file_authenticator_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ImpersonateUserReply) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ImpersonateUserReply) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
 var File_authenticator_proto protoreflect.FileDescriptor
 
 const file_authenticator_proto_rawDesc = "" +
 	"\n" +
-	"\x13authenticator.proto\x12\rauthenticator\x1a\x1cgoogle/api/annotations.proto\x1a\rgraphql.proto\"-\n" +
+	"\x13authenticator.proto\x12\rauthenticator\x1a\x1cgoogle/api/annotations.proto\x1a\rgraphql.proto\x1a\rthunder.proto\"-\n" +
 	"\x10ProtectedRequest\x12\x19\n" +
 	"\x04text\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x04text\"(\n" +
 	"\x0eProtectedReply\x12\x16\n" +
@@ -344,18 +3945,298 @@ const file_authenticator_proto_rawDesc = "" +
 	"\bpassword\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\bpassword\x12\x19\n" +
 	"\x04name\x18\x03 \x01(\tB\x05\xbaC\x02\b\x01R\x04name\x12\x1f\n" +
 	"\asurname\x18\x04 \x01(\tB\x05\xbaC\x02\b\x01R\asurname\x12\x17\n" +
-	"\x03age\x18\x05 \x01(\x05B\x05\xbaC\x02\b\x01R\x03age\"\"\n" +
+	"\x03age\x18\x05 \x01(\x05B\x05\xbaC\x02\b\x01R\x03age\"\xbf\x01\n" +
 	"\n" +
 	"LoginReply\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"%\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x03 \x01(\x03R\texpiresIn\x12.\n" +
+	"\x13two_factor_required\x18\x04 \x01(\bR\x11twoFactorRequired\x12'\n" +
+	"\x0fchallenge_token\x18\x05 \x01(\tR\x0echallengeToken\"_\n" +
+	"\x12LoginVerifyRequest\x12.\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x0echallengeToken\x12\x19\n" +
+	"\x04code\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\x04code\";\n" +
+	"\x16BeginOAuthLoginRequest\x12!\n" +
+	"\bprovider\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\bprovider\"v\n" +
+	"\x14BeginOAuthLoginReply\x12#\n" +
+	"\rauthorize_url\x18\x01 \x01(\tR\fauthorizeUrl\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12#\n" +
+	"\rcode_verifier\x18\x03 \x01(\tR\fcodeVerifier\"\x7f\n" +
+	"\x19CompleteOAuthLoginRequest\x12\x1b\n" +
+	"\x05state\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x05state\x12\x19\n" +
+	"\x04code\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\x04code\x12*\n" +
+	"\rcode_verifier\x18\x03 \x01(\tB\x05\xbaC\x02\b\x01R\fcodeVerifier\"\x13\n" +
+	"\x11EnrollTOTPRequest\"J\n" +
+	"\x0fEnrollTOTPReply\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12\x1f\n" +
+	"\votpauth_uri\x18\x02 \x01(\tR\n" +
+	"otpauthUri\"/\n" +
+	"\x12ConfirmTOTPRequest\x12\x19\n" +
+	"\x04code\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x04code\"9\n" +
+	"\x10ConfirmTOTPReply\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"/\n" +
+	"\x12DisableTOTPRequest\x12\x19\n" +
+	"\x04code\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x04code\"(\n" +
+	"\x10DisableTOTPReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"A\n" +
+	"\x13RefreshTokenRequest\x12*\n" +
+	"\rrefresh_token\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\frefreshToken\"1\n" +
+	"\x12VerifyEmailRequest\x12\x1b\n" +
+	"\x05token\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x05token\"(\n" +
+	"\x10VerifyEmailReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"8\n" +
+	"\x19ResendVerificationRequest\x12\x1b\n" +
+	"\x05email\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x05email\"/\n" +
+	"\x17ResendVerificationReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\":\n" +
+	"\x1bRequestPasswordResetRequest\x12\x1b\n" +
+	"\x05email\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x05email\"1\n" +
+	"\x19RequestPasswordResetReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"\"\n" +
+	" BeginWebAuthnRegistrationRequest\"1\n" +
+	"\x19BeginWebAuthnLoginRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"S\n" +
+	"\x12BeginWebAuthnReply\x12\x18\n" +
+	"\aoptions\x18\x01 \x01(\tR\aoptions\x12#\n" +
+	"\rsession_token\x18\x02 \x01(\tR\fsessionToken\"\x8a\x01\n" +
+	"!FinishWebAuthnRegistrationRequest\x12*\n" +
+	"\rsession_token\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\fsessionToken\x12%\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\n" +
+	"credential\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"F\n" +
+	"\x1fFinishWebAuthnRegistrationReply\x12#\n" +
+	"\rcredential_id\x18\x01 \x01(\tR\fcredentialId\"o\n" +
+	"\x1aFinishWebAuthnLoginRequest\x12*\n" +
+	"\rsession_token\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\fsessionToken\x12%\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\n" +
+	"credential\"6\n" +
+	"\x17RequestMagicLinkRequest\x12\x1b\n" +
+	"\x05email\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x05email\"C\n" +
+	"\x15RequestMagicLinkReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\tR\x05nonce\"S\n" +
+	"\x17ConsumeMagicLinkRequest\x12\x1b\n" +
+	"\x05token\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x05token\x12\x1b\n" +
+	"\x05nonce\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\x05nonce\"]\n" +
+	"\x14ResetPasswordRequest\x12\x1b\n" +
+	"\x05token\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x05token\x12(\n" +
+	"\fnew_password\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\vnewPassword\"*\n" +
+	"\x12ResetPasswordReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"4\n" +
+	"\rLogoutRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"#\n" +
+	"\vLogoutReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"\x1a\n" +
+	"\x18RevokeAllSessionsRequest\".\n" +
+	"\x16RevokeAllSessionsReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"\xa3\x01\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x02 \x01(\tR\tuserAgent\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_seen_at\x18\x05 \x01(\tR\n" +
+	"lastSeenAt\x12\x18\n" +
+	"\acurrent\x18\x06 \x01(\bR\acurrent\"\x15\n" +
+	"\x13ListSessionsRequest\"G\n" +
+	"\x11ListSessionsReply\x122\n" +
+	"\bsessions\x18\x01 \x03(\v2\x16.authenticator.SessionR\bsessions\"-\n" +
+	"\x14RevokeSessionRequest\x12\x15\n" +
+	"\x02id\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x02id\"*\n" +
+	"\x12RevokeSessionReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"\x1c\n" +
+	"\x1aRevokeOtherSessionsRequest\"4\n" +
+	"\x18RevokeOtherSessionsReply\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\x05R\arevoked\"\xbc\x01\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12\x16\n" +
+	"\x06scopes\x18\x04 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\tR\texpiresAt\x12 \n" +
+	"\flast_used_at\x18\a \x01(\tR\n" +
+	"lastUsedAt\"g\n" +
+	"\x13CreateAPIKeyRequest\x12\x19\n" +
+	"\x04name\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x03 \x01(\x03R\texpiresIn\"U\n" +
+	"\x11CreateAPIKeyReply\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12.\n" +
+	"\aapi_key\x18\x02 \x01(\v2\x15.authenticator.APIKeyR\x06apiKey\"\x14\n" +
+	"\x12ListAPIKeysRequest\"D\n" +
+	"\x10ListAPIKeysReply\x120\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\x15.authenticator.APIKeyR\aapiKeys\",\n" +
+	"\x13RevokeAPIKeyRequest\x12\x15\n" +
+	"\x02id\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x02id\")\n" +
+	"\x11RevokeAPIKeyReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"\xba\x02\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12%\n" +
+	"\x0eemail_verified\x18\x03 \x01(\bR\remailVerified\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x18\n" +
+	"\asurname\x18\x05 \x01(\tR\asurname\x12\x10\n" +
+	"\x03age\x18\x06 \x01(\x05R\x03age\x12\x12\n" +
+	"\x04desc\x18\a \x01(\tR\x04desc\x12\x14\n" +
+	"\x05roles\x18\b \x03(\tR\x05roles\x12!\n" +
+	"\ftotp_enabled\x18\t \x01(\bR\vtotpEnabled\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\tR\tupdatedAt\x12\x1a\n" +
+	"\bdisabled\x18\f \x01(\bR\bdisabled\"\x0e\n" +
+	"\fGetMeRequest\"n\n" +
+	"\x14UpdateProfileRequest\x12.\n" +
+	"\x04user\x18\x01 \x01(\v2\x13.authenticator.UserB\x05\xbaC\x02\b\x01R\x04user\x12&\n" +
+	"\vupdate_mask\x18\x02 \x03(\tB\x05\xbaC\x02\b\x01R\n" +
+	"updateMask\"s\n" +
+	"\x15ChangePasswordRequest\x120\n" +
+	"\x10current_password\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x0fcurrentPassword\x12(\n" +
+	"\fnew_password\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\vnewPassword\"`\n" +
+	"\x13ChangePasswordReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x03 \x01(\x03R\texpiresIn\"9\n" +
+	"\x14DeleteAccountRequest\x12!\n" +
+	"\bpassword\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\bpassword\"*\n" +
+	"\x12DeleteAccountReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"0\n" +
+	"\x11UnlockUserRequest\x12\x1b\n" +
+	"\x05email\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x05email\"'\n" +
+	"\x0fUnlockUserReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"\xac\x02\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x18\n" +
+	"\aoutcome\x18\x03 \x01(\tR\aoutcome\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x12\x1f\n" +
+	"\vactor_email\x18\x05 \x01(\tR\n" +
+	"actorEmail\x12\x1b\n" +
+	"\ttarget_id\x18\x06 \x01(\tR\btargetId\x12\x0e\n" +
+	"\x02ip\x18\a \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\b \x01(\tR\tuserAgent\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\t \x01(\tR\trequestId\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\v \x01(\tR\tcreatedAt\"\xcd\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x19\n" +
+	"\bactor_id\x18\x01 \x01(\tR\aactorId\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x18\n" +
+	"\aoutcome\x18\x03 \x01(\tR\aoutcome\x12\x14\n" +
+	"\x05since\x18\x04 \x01(\tR\x05since\x12\x14\n" +
+	"\x05until\x18\x05 \x01(\tR\x05until\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"q\n" +
+	"\x14ListAuditEventsReply\x121\n" +
+	"\x06events\x18\x01 \x03(\v2\x19.authenticator.AuditEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"%\n" +
 	"\rRegisterReply\x12\x14\n" +
-	"\x05reply\x18\x01 \x01(\tR\x05reply2\x84\x04\n" +
-	"\x04Auth\x12d\n" +
-	"\x05Login\x12\x1b.authenticator.LoginRequest\x1a\x19.authenticator.LoginReply\"#\xbaC\a\x12\x05login\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/auth/login\x12u\n" +
-	"\bRegister\x12\x1e.authenticator.RegisterRequest\x1a\x1c.authenticator.RegisterReply\"+\xbaC\f\b\x01\x12\bregister\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/auth/register\x12{\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"\xcb\x01\n" +
+	"\x10ListUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\x12\x1e\n" +
+	"\n" +
+	"descending\x18\x05 \x01(\bR\n" +
+	"descending\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"c\n" +
+	"\x0eListUsersReply\x12)\n" +
+	"\x05users\x18\x01 \x03(\v2\x13.authenticator.UserR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"'\n" +
+	"\x0eGetUserRequest\x12\x15\n" +
+	"\x02id\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x02id\"C\n" +
+	"\x12DisableUserRequest\x12\x15\n" +
+	"\x02id\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"*\n" +
+	"\x11EnableUserRequest\x12\x15\n" +
+	"\x02id\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x02id\"2\n" +
+	"\x19ForcePasswordResetRequest\x12\x15\n" +
+	"\x02id\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x02id\"/\n" +
+	"\x17ForcePasswordResetReply\x12\x14\n" +
+	"\x05reply\x18\x01 \x01(\tR\x05reply\"B\n" +
+	"\x13SetUserRolesRequest\x12\x15\n" +
+	"\x02id\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x02id\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\"N\n" +
+	"\x16ImpersonateUserRequest\x12\x15\n" +
+	"\x02id\x18\x01 \x01(\tB\x05\xbaC\x02\b\x01R\x02id\x12\x1d\n" +
+	"\x06reason\x18\x02 \x01(\tB\x05\xbaC\x02\b\x01R\x06reason\"K\n" +
+	"\x14ImpersonateUserReply\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x02 \x01(\x03R\texpiresIn2\x8a(\n" +
+	"\x04Auth\x12j\n" +
+	"\x05Login\x12\x1b.authenticator.LoginRequest\x1a\x19.authenticator.LoginReply\")\xbaC\a\x12\x05login\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/auth/login\x12{\n" +
+	"\bRegister\x12\x1e.authenticator.RegisterRequest\x1a\x1c.authenticator.RegisterReply\"1\xbaC\f\b\x01\x12\bregister\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/auth/register\x12\x85\x01\n" +
+	"\vLoginVerify\x12!.authenticator.LoginVerifyRequest\x1a\x19.authenticator.LoginReply\"8\xbaC\x0f\b\x01\x12\vloginVerify\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/login/verify\x12\xaa\x01\n" +
+	"\x12BeginWebAuthnLogin\x12(.authenticator.BeginWebAuthnLoginRequest\x1a!.authenticator.BeginWebAuthnReply\"G\xbaC\x16\b\x01\x12\x12beginWebAuthnLogin\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/auth/webauthn/login/begin\x12\xa6\x01\n" +
+	"\x13FinishWebAuthnLogin\x12).authenticator.FinishWebAuthnLoginRequest\x1a\x19.authenticator.LoginReply\"I\xbaC\x17\b\x01\x12\x13finishWebAuthnLogin\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/auth/webauthn/login/finish\x12\xbc\x01\n" +
+	"\x19BeginWebAuthnRegistration\x12/.authenticator.BeginWebAuthnRegistrationRequest\x1a!.authenticator.BeginWebAuthnReply\"K\xbaC\x1d\b\x01\x12\x19beginWebAuthnRegistration\x82\xd3\xe4\x93\x02%:\x01*\" /v1/auth/webauthn/register/begin\x12\xcd\x01\n" +
+	"\x1aFinishWebAuthnRegistration\x120.authenticator.FinishWebAuthnRegistrationRequest\x1a..authenticator.FinishWebAuthnRegistrationReply\"M\xbaC\x1e\b\x01\x12\x1afinishWebAuthnRegistration\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/auth/webauthn/register/finish\x12\x9a\x01\n" +
+	"\x0fBeginOAuthLogin\x12%.authenticator.BeginOAuthLoginRequest\x1a#.authenticator.BeginOAuthLoginReply\";\xbaC\x13\b\x01\x12\x0fbeginOAuthLogin\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/auth/oauth/begin\x12\x9c\x01\n" +
+	"\x12CompleteOAuthLogin\x12(.authenticator.CompleteOAuthLoginRequest\x1a\x19.authenticator.LoginReply\"A\xbaC\x16\b\x01\x12\x12completeOAuthLogin\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/auth/oauth/complete\x12\x80\x01\n" +
+	"\n" +
+	"EnrollTOTP\x12 .authenticator.EnrollTOTPRequest\x1a\x1e.authenticator.EnrollTOTPReply\"0\xbaC\x0e\b\x01\x12\n" +
+	"enrollTotp\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/auth/totp/enroll\x12\x85\x01\n" +
+	"\vConfirmTOTP\x12!.authenticator.ConfirmTOTPRequest\x1a\x1f.authenticator.ConfirmTOTPReply\"2\xbaC\x0f\b\x01\x12\vconfirmTotp\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/totp/confirm\x12\x85\x01\n" +
+	"\vDisableTOTP\x12!.authenticator.DisableTOTPRequest\x1a\x1f.authenticator.DisableTOTPReply\"2\xbaC\x0f\b\x01\x12\vdisableTotp\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/totp/disable\x12\x83\x01\n" +
+	"\fRefreshToken\x12\".authenticator.RefreshTokenRequest\x1a\x19.authenticator.LoginReply\"4\xbaC\x10\b\x01\x12\frefreshToken\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/auth/refresh\x12\x8b\x01\n" +
+	"\vVerifyEmail\x12!.authenticator.VerifyEmailRequest\x1a\x1f.authenticator.VerifyEmailReply\"8\xbaC\x0f\b\x01\x12\vverifyEmail\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/email/verify\x12\xa7\x01\n" +
+	"\x12ResendVerification\x12(.authenticator.ResendVerificationRequest\x1a&.authenticator.ResendVerificationReply\"?\xbaC\x16\b\x01\x12\x12resendVerification\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/email/resend\x12\xb2\x01\n" +
+	"\x14RequestPasswordReset\x12*.authenticator.RequestPasswordResetRequest\x1a(.authenticator.RequestPasswordResetReply\"D\xbaC\x18\b\x01\x12\x14requestPasswordReset\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/auth/password/forgot\x12\x95\x01\n" +
+	"\rResetPassword\x12#.authenticator.ResetPasswordRequest\x1a!.authenticator.ResetPasswordReply\"<\xbaC\x11\b\x01\x12\rresetPassword\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/auth/password/reset\x12\x9d\x01\n" +
+	"\x10RequestMagicLink\x12&.authenticator.RequestMagicLinkRequest\x1a$.authenticator.RequestMagicLinkReply\";\xbaC\x14\b\x01\x12\x10requestMagicLink\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/auth/magic-link\x12\x9a\x01\n" +
+	"\x10ConsumeMagicLink\x12&.authenticator.ConsumeMagicLinkRequest\x1a\x19.authenticator.LoginReply\"C\xbaC\x14\b\x01\x12\x10consumeMagicLink\xa2\xbb\x18\x02\b\x01\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/auth/magic-link/consume\x12k\n" +
+	"\x06Logout\x12\x1c.authenticator.LogoutRequest\x1a\x1a.authenticator.LogoutReply\"'\xbaC\n" +
+	"\b\x01\x12\x06logout\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/auth/logout\x12\x9b\x01\n" +
+	"\x11RevokeAllSessions\x12'.authenticator.RevokeAllSessionsRequest\x1a%.authenticator.RevokeAllSessionsReply\"6\xbaC\x15\b\x01\x12\x11revokeAllSessions\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/auth/revoke-all\x12|\n" +
+	"\fListSessions\x12\".authenticator.ListSessionsRequest\x1a .authenticator.ListSessionsReply\"&\xbaC\n" +
+	"\x12\bsessions\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/auth/sessions\x12\x8b\x01\n" +
+	"\rRevokeSession\x12#.authenticator.RevokeSessionRequest\x1a!.authenticator.RevokeSessionReply\"2\xbaC\x11\b\x01\x12\rrevokeSession\x82\xd3\xe4\x93\x02\x18*\x16/v1/auth/sessions/{id}\x12\xaf\x01\n" +
+	"\x13RevokeOtherSessions\x12).authenticator.RevokeOtherSessionsRequest\x1a'.authenticator.RevokeOtherSessionsReply\"D\xbaC\x17\b\x01\x12\x13revokeOtherSessions\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/auth/sessions/revoke-others\x12\x85\x01\n" +
+	"\fCreateAPIKey\x12\".authenticator.CreateAPIKeyRequest\x1a .authenticator.CreateAPIKeyReply\"/\xbaC\x10\b\x01\x12\fcreateApiKey\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/auth/api-keys\x12x\n" +
+	"\vListAPIKeys\x12!.authenticator.ListAPIKeysRequest\x1a\x1f.authenticator.ListAPIKeysReply\"%\xbaC\t\x12\aapiKeys\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/auth/api-keys\x12\x87\x01\n" +
+	"\fRevokeAPIKey\x12\".authenticator.RevokeAPIKeyRequest\x1a .authenticator.RevokeAPIKeyReply\"1\xbaC\x10\b\x01\x12\frevokeApiKey\x82\xd3\xe4\x93\x02\x18*\x16/v1/auth/api-keys/{id}\x12U\n" +
+	"\x05GetMe\x12\x1b.authenticator.GetMeRequest\x1a\x13.authenticator.User\"\x1a\xbaC\x04\x12\x02me\x82\xd3\xe4\x93\x02\r\x12\v/v1/auth/me\x12u\n" +
+	"\rUpdateProfile\x12#.authenticator.UpdateProfileRequest\x1a\x13.authenticator.User\"*\xbaC\x11\b\x01\x12\rupdateProfile\x82\xd3\xe4\x93\x02\x10:\x01*2\v/v1/auth/me\x12\x94\x01\n" +
+	"\x0eChangePassword\x12$.authenticator.ChangePasswordRequest\x1a\".authenticator.ChangePasswordReply\"8\xbaC\x12\b\x01\x12\x0echangePassword\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/auth/password/change\x12\x8a\x01\n" +
+	"\rDeleteAccount\x12#.authenticator.DeleteAccountRequest\x1a!.authenticator.DeleteAccountReply\"1\xbaC\x11\b\x01\x12\rdeleteAccount\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/auth/me/delete\x12\x8c\x01\n" +
+	"\n" +
+	"UnlockUser\x12 .authenticator.UnlockUserRequest\x1a\x1e.authenticator.UnlockUserReply\"<\xbaC\x0e\b\x01\x12\n" +
+	"unlockUser\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/admin/unlock\x12\x9d\x01\n" +
+	"\x0fListAuditEvents\x12%.authenticator.ListAuditEventsRequest\x1a#.authenticator.ListAuditEventsReply\">\xbaC\r\x12\vauditEvents\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/auth/admin/audit-events\x12{\n" +
 	"\x0fSampleProtected\x12\x1f.authenticator.ProtectedRequest\x1a\x1d.authenticator.ProtectedReply\"(\xbaC\v\x12\tprotected\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/auth/protected\x12\x89\x01\n" +
 	"\x15StreamSampleProtected\x12\x1f.authenticator.ProtectedRequest\x1a\x1d.authenticator.ProtectedReply\".\xbaC\n" +
 	"\b\x03\x12\x06stream\x82\xd3\xe4\x93\x02\x1b\x12\x19/v1/auth/stream/protected0\x01\x1a\x16\xbaC\x13\n" +
+	"\x0flocalhost:50051\x10\x012\xa1\b\n" +
+	"\tUserAdmin\x12y\n" +
+	"\tListUsers\x12\x1f.authenticator.ListUsersRequest\x1a\x1d.authenticator.ListUsersReply\",\xbaC\a\x12\x05users\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/admin/users\x12o\n" +
+	"\aGetUser\x12\x1d.authenticator.GetUserRequest\x1a\x13.authenticator.User\"0\xbaC\x06\x12\x04user\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/admin/users/{id}\x12\x8b\x01\n" +
+	"\vDisableUser\x12!.authenticator.DisableUserRequest\x1a\x13.authenticator.User\"D\xbaC\x0f\b\x01\x12\vdisableUser\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/admin/users/{id}/disable\x12\x87\x01\n" +
+	"\n" +
+	"EnableUser\x12 .authenticator.EnableUserRequest\x1a\x13.authenticator.User\"B\xbaC\x0e\b\x01\x12\n" +
+	"enableUser\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/admin/users/{id}/enable\x12\xba\x01\n" +
+	"\x12ForcePasswordReset\x12(.authenticator.ForcePasswordResetRequest\x1a&.authenticator.ForcePasswordResetReply\"R\xbaC\x16\b\x01\x12\x12forcePasswordReset\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02(:\x01*\"#/v1/admin/users/{id}/password-reset\x12\x8c\x01\n" +
+	"\fSetUserRoles\x12\".authenticator.SetUserRolesRequest\x1a\x13.authenticator.User\"C\xbaC\x10\b\x01\x12\fsetUserRoles\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02\x1f:\x01*\x1a\x1a/v1/admin/users/{id}/roles\x12\xab\x01\n" +
+	"\x0fImpersonateUser\x12%.authenticator.ImpersonateUserRequest\x1a#.authenticator.ImpersonateUserReply\"L\xbaC\x13\b\x01\x12\x0fimpersonateUser\xa2\xbb\x18\a\x12\x05admin\x82\xd3\xe4\x93\x02%:\x01*\" /v1/admin/users/{id}/impersonate\x1a\x16\xbaC\x13\n" +
 	"\x0flocalhost:50051\x10\x01B\x1aZ\x18./pkg/services/generatedb\x06proto3"
 
 var (
@@ -370,29 +4251,179 @@ func file_authenticator_proto_rawDescGZIP() []byte {
 	return file_authenticator_proto_rawDescData
 }
 
-var file_authenticator_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_authenticator_proto_msgTypes = make([]protoimpl.MessageInfo, 74)
 var file_authenticator_proto_goTypes = []any{
-	(*ProtectedRequest)(nil), // 0: authenticator.ProtectedRequest
-	(*ProtectedReply)(nil),   // 1: authenticator.ProtectedReply
-	(*LoginRequest)(nil),     // 2: authenticator.LoginRequest
-	(*RegisterRequest)(nil),  // 3: authenticator.RegisterRequest
-	(*LoginReply)(nil),       // 4: authenticator.LoginReply
-	(*RegisterReply)(nil),    // 5: authenticator.RegisterReply
+	(*ProtectedRequest)(nil),                  // 0: authenticator.ProtectedRequest
+	(*ProtectedReply)(nil),                    // 1: authenticator.ProtectedReply
+	(*LoginRequest)(nil),                      // 2: authenticator.LoginRequest
+	(*RegisterRequest)(nil),                   // 3: authenticator.RegisterRequest
+	(*LoginReply)(nil),                        // 4: authenticator.LoginReply
+	(*LoginVerifyRequest)(nil),                // 5: authenticator.LoginVerifyRequest
+	(*BeginOAuthLoginRequest)(nil),            // 6: authenticator.BeginOAuthLoginRequest
+	(*BeginOAuthLoginReply)(nil),              // 7: authenticator.BeginOAuthLoginReply
+	(*CompleteOAuthLoginRequest)(nil),         // 8: authenticator.CompleteOAuthLoginRequest
+	(*EnrollTOTPRequest)(nil),                 // 9: authenticator.EnrollTOTPRequest
+	(*EnrollTOTPReply)(nil),                   // 10: authenticator.EnrollTOTPReply
+	(*ConfirmTOTPRequest)(nil),                // 11: authenticator.ConfirmTOTPRequest
+	(*ConfirmTOTPReply)(nil),                  // 12: authenticator.ConfirmTOTPReply
+	(*DisableTOTPRequest)(nil),                // 13: authenticator.DisableTOTPRequest
+	(*DisableTOTPReply)(nil),                  // 14: authenticator.DisableTOTPReply
+	(*RefreshTokenRequest)(nil),               // 15: authenticator.RefreshTokenRequest
+	(*VerifyEmailRequest)(nil),                // 16: authenticator.VerifyEmailRequest
+	(*VerifyEmailReply)(nil),                  // 17: authenticator.VerifyEmailReply
+	(*ResendVerificationRequest)(nil),         // 18: authenticator.ResendVerificationRequest
+	(*ResendVerificationReply)(nil),           // 19: authenticator.ResendVerificationReply
+	(*RequestPasswordResetRequest)(nil),       // 20: authenticator.RequestPasswordResetRequest
+	(*RequestPasswordResetReply)(nil),         // 21: authenticator.RequestPasswordResetReply
+	(*BeginWebAuthnRegistrationRequest)(nil),  // 22: authenticator.BeginWebAuthnRegistrationRequest
+	(*BeginWebAuthnLoginRequest)(nil),         // 23: authenticator.BeginWebAuthnLoginRequest
+	(*BeginWebAuthnReply)(nil),                // 24: authenticator.BeginWebAuthnReply
+	(*FinishWebAuthnRegistrationRequest)(nil), // 25: authenticator.FinishWebAuthnRegistrationRequest
+	(*FinishWebAuthnRegistrationReply)(nil),   // 26: authenticator.FinishWebAuthnRegistrationReply
+	(*FinishWebAuthnLoginRequest)(nil),        // 27: authenticator.FinishWebAuthnLoginRequest
+	(*RequestMagicLinkRequest)(nil),           // 28: authenticator.RequestMagicLinkRequest
+	(*RequestMagicLinkReply)(nil),             // 29: authenticator.RequestMagicLinkReply
+	(*ConsumeMagicLinkRequest)(nil),           // 30: authenticator.ConsumeMagicLinkRequest
+	(*ResetPasswordRequest)(nil),              // 31: authenticator.ResetPasswordRequest
+	(*ResetPasswordReply)(nil),                // 32: authenticator.ResetPasswordReply
+	(*LogoutRequest)(nil),                     // 33: authenticator.LogoutRequest
+	(*LogoutReply)(nil),                       // 34: authenticator.LogoutReply
+	(*RevokeAllSessionsRequest)(nil),          // 35: authenticator.RevokeAllSessionsRequest
+	(*RevokeAllSessionsReply)(nil),            // 36: authenticator.RevokeAllSessionsReply
+	(*Session)(nil),                           // 37: authenticator.Session
+	(*ListSessionsRequest)(nil),               // 38: authenticator.ListSessionsRequest
+	(*ListSessionsReply)(nil),                 // 39: authenticator.ListSessionsReply
+	(*RevokeSessionRequest)(nil),              // 40: authenticator.RevokeSessionRequest
+	(*RevokeSessionReply)(nil),                // 41: authenticator.RevokeSessionReply
+	(*RevokeOtherSessionsRequest)(nil),        // 42: authenticator.RevokeOtherSessionsRequest
+	(*RevokeOtherSessionsReply)(nil),          // 43: authenticator.RevokeOtherSessionsReply
+	(*APIKey)(nil),                            // 44: authenticator.APIKey
+	(*CreateAPIKeyRequest)(nil),               // 45: authenticator.CreateAPIKeyRequest
+	(*CreateAPIKeyReply)(nil),                 // 46: authenticator.CreateAPIKeyReply
+	(*ListAPIKeysRequest)(nil),                // 47: authenticator.ListAPIKeysRequest
+	(*ListAPIKeysReply)(nil),                  // 48: authenticator.ListAPIKeysReply
+	(*RevokeAPIKeyRequest)(nil),               // 49: authenticator.RevokeAPIKeyRequest
+	(*RevokeAPIKeyReply)(nil),                 // 50: authenticator.RevokeAPIKeyReply
+	(*User)(nil),                              // 51: authenticator.User
+	(*GetMeRequest)(nil),                      // 52: authenticator.GetMeRequest
+	(*UpdateProfileRequest)(nil),              // 53: authenticator.UpdateProfileRequest
+	(*ChangePasswordRequest)(nil),             // 54: authenticator.ChangePasswordRequest
+	(*ChangePasswordReply)(nil),               // 55: authenticator.ChangePasswordReply
+	(*DeleteAccountRequest)(nil),              // 56: authenticator.DeleteAccountRequest
+	(*DeleteAccountReply)(nil),                // 57: authenticator.DeleteAccountReply
+	(*UnlockUserRequest)(nil),                 // 58: authenticator.UnlockUserRequest
+	(*UnlockUserReply)(nil),                   // 59: authenticator.UnlockUserReply
+	(*AuditEvent)(nil),                        // 60: authenticator.AuditEvent
+	(*ListAuditEventsRequest)(nil),            // 61: authenticator.ListAuditEventsRequest
+	(*ListAuditEventsReply)(nil),              // 62: authenticator.ListAuditEventsReply
+	(*RegisterReply)(nil),                     // 63: authenticator.RegisterReply
+	(*ListUsersRequest)(nil),                  // 64: authenticator.ListUsersRequest
+	(*ListUsersReply)(nil),                    // 65: authenticator.ListUsersReply
+	(*GetUserRequest)(nil),                    // 66: authenticator.GetUserRequest
+	(*DisableUserRequest)(nil),                // 67: authenticator.DisableUserRequest
+	(*EnableUserRequest)(nil),                 // 68: authenticator.EnableUserRequest
+	(*ForcePasswordResetRequest)(nil),         // 69: authenticator.ForcePasswordResetRequest
+	(*ForcePasswordResetReply)(nil),           // 70: authenticator.ForcePasswordResetReply
+	(*SetUserRolesRequest)(nil),               // 71: authenticator.SetUserRolesRequest
+	(*ImpersonateUserRequest)(nil),            // 72: authenticator.ImpersonateUserRequest
+	(*ImpersonateUserReply)(nil),              // 73: authenticator.ImpersonateUserReply
 }
 var file_authenticator_proto_depIdxs = []int32{
-	2, // 0: authenticator.Auth.Login:input_type -> authenticator.LoginRequest
-	3, // 1: authenticator.Auth.Register:input_type -> authenticator.RegisterRequest
-	0, // 2: authenticator.Auth.SampleProtected:input_type -> authenticator.ProtectedRequest
-	0, // 3: authenticator.Auth.StreamSampleProtected:input_type -> authenticator.ProtectedRequest
-	4, // 4: authenticator.Auth.Login:output_type -> authenticator.LoginReply
-	5, // 5: authenticator.Auth.Register:output_type -> authenticator.RegisterReply
-	1, // 6: authenticator.Auth.SampleProtected:output_type -> authenticator.ProtectedReply
-	1, // 7: authenticator.Auth.StreamSampleProtected:output_type -> authenticator.ProtectedReply
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	37, // 0: authenticator.ListSessionsReply.sessions:type_name -> authenticator.Session
+	44, // 1: authenticator.CreateAPIKeyReply.api_key:type_name -> authenticator.APIKey
+	44, // 2: authenticator.ListAPIKeysReply.api_keys:type_name -> authenticator.APIKey
+	51, // 3: authenticator.UpdateProfileRequest.user:type_name -> authenticator.User
+	60, // 4: authenticator.ListAuditEventsReply.events:type_name -> authenticator.AuditEvent
+	51, // 5: authenticator.ListUsersReply.users:type_name -> authenticator.User
+	2,  // 6: authenticator.Auth.Login:input_type -> authenticator.LoginRequest
+	3,  // 7: authenticator.Auth.Register:input_type -> authenticator.RegisterRequest
+	5,  // 8: authenticator.Auth.LoginVerify:input_type -> authenticator.LoginVerifyRequest
+	23, // 9: authenticator.Auth.BeginWebAuthnLogin:input_type -> authenticator.BeginWebAuthnLoginRequest
+	27, // 10: authenticator.Auth.FinishWebAuthnLogin:input_type -> authenticator.FinishWebAuthnLoginRequest
+	22, // 11: authenticator.Auth.BeginWebAuthnRegistration:input_type -> authenticator.BeginWebAuthnRegistrationRequest
+	25, // 12: authenticator.Auth.FinishWebAuthnRegistration:input_type -> authenticator.FinishWebAuthnRegistrationRequest
+	6,  // 13: authenticator.Auth.BeginOAuthLogin:input_type -> authenticator.BeginOAuthLoginRequest
+	8,  // 14: authenticator.Auth.CompleteOAuthLogin:input_type -> authenticator.CompleteOAuthLoginRequest
+	9,  // 15: authenticator.Auth.EnrollTOTP:input_type -> authenticator.EnrollTOTPRequest
+	11, // 16: authenticator.Auth.ConfirmTOTP:input_type -> authenticator.ConfirmTOTPRequest
+	13, // 17: authenticator.Auth.DisableTOTP:input_type -> authenticator.DisableTOTPRequest
+	15, // 18: authenticator.Auth.RefreshToken:input_type -> authenticator.RefreshTokenRequest
+	16, // 19: authenticator.Auth.VerifyEmail:input_type -> authenticator.VerifyEmailRequest
+	18, // 20: authenticator.Auth.ResendVerification:input_type -> authenticator.ResendVerificationRequest
+	20, // 21: authenticator.Auth.RequestPasswordReset:input_type -> authenticator.RequestPasswordResetRequest
+	31, // 22: authenticator.Auth.ResetPassword:input_type -> authenticator.ResetPasswordRequest
+	28, // 23: authenticator.Auth.RequestMagicLink:input_type -> authenticator.RequestMagicLinkRequest
+	30, // 24: authenticator.Auth.ConsumeMagicLink:input_type -> authenticator.ConsumeMagicLinkRequest
+	33, // 25: authenticator.Auth.Logout:input_type -> authenticator.LogoutRequest
+	35, // 26: authenticator.Auth.RevokeAllSessions:input_type -> authenticator.RevokeAllSessionsRequest
+	38, // 27: authenticator.Auth.ListSessions:input_type -> authenticator.ListSessionsRequest
+	40, // 28: authenticator.Auth.RevokeSession:input_type -> authenticator.RevokeSessionRequest
+	42, // 29: authenticator.Auth.RevokeOtherSessions:input_type -> authenticator.RevokeOtherSessionsRequest
+	45, // 30: authenticator.Auth.CreateAPIKey:input_type -> authenticator.CreateAPIKeyRequest
+	47, // 31: authenticator.Auth.ListAPIKeys:input_type -> authenticator.ListAPIKeysRequest
+	49, // 32: authenticator.Auth.RevokeAPIKey:input_type -> authenticator.RevokeAPIKeyRequest
+	52, // 33: authenticator.Auth.GetMe:input_type -> authenticator.GetMeRequest
+	53, // 34: authenticator.Auth.UpdateProfile:input_type -> authenticator.UpdateProfileRequest
+	54, // 35: authenticator.Auth.ChangePassword:input_type -> authenticator.ChangePasswordRequest
+	56, // 36: authenticator.Auth.DeleteAccount:input_type -> authenticator.DeleteAccountRequest
+	58, // 37: authenticator.Auth.UnlockUser:input_type -> authenticator.UnlockUserRequest
+	61, // 38: authenticator.Auth.ListAuditEvents:input_type -> authenticator.ListAuditEventsRequest
+	0,  // 39: authenticator.Auth.SampleProtected:input_type -> authenticator.ProtectedRequest
+	0,  // 40: authenticator.Auth.StreamSampleProtected:input_type -> authenticator.ProtectedRequest
+	64, // 41: authenticator.UserAdmin.ListUsers:input_type -> authenticator.ListUsersRequest
+	66, // 42: authenticator.UserAdmin.GetUser:input_type -> authenticator.GetUserRequest
+	67, // 43: authenticator.UserAdmin.DisableUser:input_type -> authenticator.DisableUserRequest
+	68, // 44: authenticator.UserAdmin.EnableUser:input_type -> authenticator.EnableUserRequest
+	69, // 45: authenticator.UserAdmin.ForcePasswordReset:input_type -> authenticator.ForcePasswordResetRequest
+	71, // 46: authenticator.UserAdmin.SetUserRoles:input_type -> authenticator.SetUserRolesRequest
+	72, // 47: authenticator.UserAdmin.ImpersonateUser:input_type -> authenticator.ImpersonateUserRequest
+	4,  // 48: authenticator.Auth.Login:output_type -> authenticator.LoginReply
+	63, // 49: authenticator.Auth.Register:output_type -> authenticator.RegisterReply
+	4,  // 50: authenticator.Auth.LoginVerify:output_type -> authenticator.LoginReply
+	24, // 51: authenticator.Auth.BeginWebAuthnLogin:output_type -> authenticator.BeginWebAuthnReply
+	4,  // 52: authenticator.Auth.FinishWebAuthnLogin:output_type -> authenticator.LoginReply
+	24, // 53: authenticator.Auth.BeginWebAuthnRegistration:output_type -> authenticator.BeginWebAuthnReply
+	26, // 54: authenticator.Auth.FinishWebAuthnRegistration:output_type -> authenticator.FinishWebAuthnRegistrationReply
+	7,  // 55: authenticator.Auth.BeginOAuthLogin:output_type -> authenticator.BeginOAuthLoginReply
+	4,  // 56: authenticator.Auth.CompleteOAuthLogin:output_type -> authenticator.LoginReply
+	10, // 57: authenticator.Auth.EnrollTOTP:output_type -> authenticator.EnrollTOTPReply
+	12, // 58: authenticator.Auth.ConfirmTOTP:output_type -> authenticator.ConfirmTOTPReply
+	14, // 59: authenticator.Auth.DisableTOTP:output_type -> authenticator.DisableTOTPReply
+	4,  // 60: authenticator.Auth.RefreshToken:output_type -> authenticator.LoginReply
+	17, // 61: authenticator.Auth.VerifyEmail:output_type -> authenticator.VerifyEmailReply
+	19, // 62: authenticator.Auth.ResendVerification:output_type -> authenticator.ResendVerificationReply
+	21, // 63: authenticator.Auth.RequestPasswordReset:output_type -> authenticator.RequestPasswordResetReply
+	32, // 64: authenticator.Auth.ResetPassword:output_type -> authenticator.ResetPasswordReply
+	29, // 65: authenticator.Auth.RequestMagicLink:output_type -> authenticator.RequestMagicLinkReply
+	4,  // 66: authenticator.Auth.ConsumeMagicLink:output_type -> authenticator.LoginReply
+	34, // 67: authenticator.Auth.Logout:output_type -> authenticator.LogoutReply
+	36, // 68: authenticator.Auth.RevokeAllSessions:output_type -> authenticator.RevokeAllSessionsReply
+	39, // 69: authenticator.Auth.ListSessions:output_type -> authenticator.ListSessionsReply
+	41, // 70: authenticator.Auth.RevokeSession:output_type -> authenticator.RevokeSessionReply
+	43, // 71: authenticator.Auth.RevokeOtherSessions:output_type -> authenticator.RevokeOtherSessionsReply
+	46, // 72: authenticator.Auth.CreateAPIKey:output_type -> authenticator.CreateAPIKeyReply
+	48, // 73: authenticator.Auth.ListAPIKeys:output_type -> authenticator.ListAPIKeysReply
+	50, // 74: authenticator.Auth.RevokeAPIKey:output_type -> authenticator.RevokeAPIKeyReply
+	51, // 75: authenticator.Auth.GetMe:output_type -> authenticator.User
+	51, // 76: authenticator.Auth.UpdateProfile:output_type -> authenticator.User
+	55, // 77: authenticator.Auth.ChangePassword:output_type -> authenticator.ChangePasswordReply
+	57, // 78: authenticator.Auth.DeleteAccount:output_type -> authenticator.DeleteAccountReply
+	59, // 79: authenticator.Auth.UnlockUser:output_type -> authenticator.UnlockUserReply
+	62, // 80: authenticator.Auth.ListAuditEvents:output_type -> authenticator.ListAuditEventsReply
+	1,  // 81: authenticator.Auth.SampleProtected:output_type -> authenticator.ProtectedReply
+	1,  // 82: authenticator.Auth.StreamSampleProtected:output_type -> authenticator.ProtectedReply
+	65, // 83: authenticator.UserAdmin.ListUsers:output_type -> authenticator.ListUsersReply
+	51, // 84: authenticator.UserAdmin.GetUser:output_type -> authenticator.User
+	51, // 85: authenticator.UserAdmin.DisableUser:output_type -> authenticator.User
+	51, // 86: authenticator.UserAdmin.EnableUser:output_type -> authenticator.User
+	70, // 87: authenticator.UserAdmin.ForcePasswordReset:output_type -> authenticator.ForcePasswordResetReply
+	51, // 88: authenticator.UserAdmin.SetUserRoles:output_type -> authenticator.User
+	73, // 89: authenticator.UserAdmin.ImpersonateUser:output_type -> authenticator.ImpersonateUserReply
+	48, // [48:90] is the sub-list for method output_type
+	6,  // [6:48] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_authenticator_proto_init() }
@@ -400,15 +4431,16 @@ func file_authenticator_proto_init() {
 	if File_authenticator_proto != nil {
 		return
 	}
+	file_thunder_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_authenticator_proto_rawDesc), len(file_authenticator_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   74,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_authenticator_proto_goTypes,
 		DependencyIndexes: file_authenticator_proto_depIdxs,
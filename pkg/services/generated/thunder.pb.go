@@ -0,0 +1,178 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: thunder.proto
+
+package generated
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuthRule declares who may call an RPC. It is enforced by the auth interceptors.
+//
+//	rpc Login (LoginRequest) returns (LoginReply) {
+//	    option (thunder.auth) = { public: true };
+//	}
+//	rpc Settle (SettleRequest) returns (SettleReply) {
+//	    option (thunder.auth) = { roles: ["billing"], credentials: ["certificate"] };
+//	}
+//
+// RPCs without a rule require a valid token.
+type AuthRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Public RPCs can be called without a token.
+	Public bool `protobuf:"varint,1,opt,name=public,proto3" json:"public,omitempty"`
+	// Roles allowed to call the RPC; the caller needs at least one of them.
+	// An empty list allows every authenticated user.
+	Roles []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	// Credentials the caller may authenticate with: "jwt" for access tokens, "api_key"
+	// and "certificate". An empty list accepts all of them.
+	Credentials   []string `protobuf:"bytes,3,rep,name=credentials,proto3" json:"credentials,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthRule) Reset() {
+	*x = AuthRule{}
+	mi := &file_thunder_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthRule) ProtoMessage() {}
+
+func (x *AuthRule) ProtoReflect() protoreflect.Message {
+	mi := &file_thunder_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthRule.ProtoReflect.Descriptor instead.
+func (*AuthRule) Descriptor() ([]byte, []int) {
+	return file_thunder_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuthRule) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *AuthRule) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *AuthRule) GetCredentials() []string {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+var file_thunder_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*AuthRule)(nil),
+		Field:         50100,
+		Name:          "thunder.auth",
+		Tag:           "bytes,50100,opt,name=auth",
+		Filename:      "thunder.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional thunder.AuthRule auth = 50100;
+	E_Auth = &file_thunder_proto_extTypes[0]
+)
+
+var File_thunder_proto protoreflect.FileDescriptor
+
+const file_thunder_proto_rawDesc = "" +
+	"\n" +
+	"\rthunder.proto\x12\athunder\x1a google/protobuf/descriptor.proto\"Z\n" +
+	"\bAuthRule\x12\x16\n" +
+	"\x06public\x18\x01 \x01(\bR\x06public\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12 \n" +
+	"\vcredentials\x18\x03 \x03(\tR\vcredentials:G\n" +
+	"\x04auth\x12\x1e.google.protobuf.MethodOptions\x18\xb4\x87\x03 \x01(\v2\x11.thunder.AuthRuleR\x04authB\x1aZ\x18./pkg/services/generatedb\x06proto3"
+
+var (
+	file_thunder_proto_rawDescOnce sync.Once
+	file_thunder_proto_rawDescData []byte
+)
+
+func file_thunder_proto_rawDescGZIP() []byte {
+	file_thunder_proto_rawDescOnce.Do(func() {
+		file_thunder_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_thunder_proto_rawDesc), len(file_thunder_proto_rawDesc)))
+	})
+	return file_thunder_proto_rawDescData
+}
+
+var file_thunder_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_thunder_proto_goTypes = []any{
+	(*AuthRule)(nil),                   // 0: thunder.AuthRule
+	(*descriptorpb.MethodOptions)(nil), // 1: google.protobuf.MethodOptions
+}
+var file_thunder_proto_depIdxs = []int32{
+	1, // 0: thunder.auth:extendee -> google.protobuf.MethodOptions
+	0, // 1: thunder.auth:type_name -> thunder.AuthRule
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_thunder_proto_init() }
+func file_thunder_proto_init() {
+	if File_thunder_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_thunder_proto_rawDesc), len(file_thunder_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_thunder_proto_goTypes,
+		DependencyIndexes: file_thunder_proto_depIdxs,
+		MessageInfos:      file_thunder_proto_msgTypes,
+		ExtensionInfos:    file_thunder_proto_extTypes,
+	}.Build()
+	File_thunder_proto = out.File
+	file_thunder_proto_goTypes = nil
+	file_thunder_proto_depIdxs = nil
+}
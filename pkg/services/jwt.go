@@ -1,15 +1,22 @@
 package services
 
 import (
-	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"google.golang.org/grpc/metadata"
 )
 
+// Token times are issued with microsecond rather than whole-second precision, so that a
+// cutoff set by RevokeUserTokens tells tokens issued just before it from those issued
+// just after it. Cutoffs have millisecond precision (see revocationCutoff); the finer
+// precision of iat leaves room for the microsecond its parsing can lose to float rounding.
+func init() {
+	jwt.TimePrecision = time.Microsecond
+}
+
 // getJWTSecret fetches the JWT secret securely from environment variables.
 func getJWTSecret() ([]byte, error) {
 	secret := os.Getenv("JWT_SECRET")
@@ -19,39 +26,160 @@ func getJWTSecret() ([]byte, error) {
 	return []byte(secret), nil
 }
 
+// defaultAccessTokenTTL is the lifetime of access tokens when JWT_ACCESS_TOKEN_TTL is unset.
+const defaultAccessTokenTTL = 15 * time.Minute
+
+// AccessTokenTTL returns the access token lifetime, configurable through the
+// JWT_ACCESS_TOKEN_TTL environment variable (e.g. "15m", "1h").
+func AccessTokenTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("JWT_ACCESS_TOKEN_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultAccessTokenTTL
+}
+
 // Claims struct for JWT payload with added issuer claim.
 type Claims struct {
-	Email string `json:"email"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Roles         []string `json:"roles,omitempty"`
+	// Purpose marks tokens that are not access tokens, such as two-factor challenges.
+	Purpose string `json:"purpose,omitempty"`
+	// SessionID is the login the token was issued for; revoking the session revokes the token.
+	SessionID string `json:"sid,omitempty"`
+	// TenantID is the tenant of the user; the token is only accepted within it.
+	TenantID string `json:"tid,omitempty"`
+	// Actor is set on tokens an admin obtained through UserAdmin.ImpersonateUser.
+	Actor *Actor `json:"act,omitempty"`
+	// APIKeyID is set when the caller authenticated with an API key instead of a JWT.
+	APIKeyID string `json:"-"`
 	jwt.RegisteredClaims
 }
 
-// NewClaims creates a new Claims object with expiration time and an issuer.
-func NewClaims(email string) *Claims {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// Actor is the act claim of RFC 8693: the party acting on behalf of the subject.
+type Actor struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email,omitempty"`
+}
+
+// ClaimsOption customizes the claims of a token before it is signed.
+type ClaimsOption func(*Claims)
+
+// WithSubject sets the subject claim to the ID of the user the token is issued to.
+func WithSubject(userID string) ClaimsOption {
+	return func(c *Claims) {
+		c.Subject = userID
+	}
+}
+
+// WithRoles embeds the roles of the user, checked against (thunder.auth) rules.
+func WithRoles(roles ...string) ClaimsOption {
+	return func(c *Claims) {
+		c.Roles = roles
+	}
+}
+
+// WithEmailVerified records whether the user has verified their email address.
+func WithEmailVerified(verified bool) ClaimsOption {
+	return func(c *Claims) {
+		c.EmailVerified = verified
+	}
+}
+
+// WithSession ties the token to a login session.
+func WithSession(sessionID string) ClaimsOption {
+	return func(c *Claims) {
+		c.SessionID = sessionID
+	}
+}
+
+// WithTenant scopes the token to the tenant of the user.
+func WithTenant(tenantID string) ClaimsOption {
+	return func(c *Claims) {
+		c.TenantID = tenantID
+	}
+}
+
+// WithActor marks the token as issued to the user identified by userID and email, acting
+// on behalf of the subject.
+func WithActor(userID, email string) ClaimsOption {
+	return func(c *Claims) {
+		c.Actor = &Actor{Subject: userID, Email: email}
+	}
+}
+
+// WithPurpose restricts the token to a single purpose. Such tokens are rejected by
+// VerifyJWT and can only be checked with the verifier of that purpose.
+func WithPurpose(purpose string) ClaimsOption {
+	return func(c *Claims) {
+		c.Purpose = purpose
+	}
+}
+
+// WithTTL overrides the lifetime of the token.
+func WithTTL(ttl time.Duration) ClaimsOption {
+	return func(c *Claims) {
+		c.ExpiresAt = jwt.NewNumericDate(c.IssuedAt.Add(ttl))
+	}
+}
+
+// NewClaims creates a new Claims object with expiration time, an issuer and a unique
+// token ID (jti) that allows the token to be revoked.
+func NewClaims(email string, opts ...ClaimsOption) *Claims {
+	now := time.Now()
 	issuer := os.Getenv("JWT_ISSUER")
 	if issuer == "" {
 		issuer = "default-issuer" // Fallback issuer, but best to set it in env.
 	}
-	return &Claims{
+	// crypto/rand does not fail on supported platforms.
+	jti, _ := newRandomID()
+	claims := &Claims{
 		Email: email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL())),
 			Issuer:    issuer,
-			// Optionally, add Audience, Subject, etc.
 		},
 	}
+	for _, opt := range opts {
+		opt(claims)
+	}
+	return claims
 }
 
 // GenerateJWT generates a JWT token securely with additional claims.
-func GenerateJWT(email string) (string, error) {
+// Tokens are signed with the active asymmetric key when a key set is configured,
+// and with JWT_SECRET (HS256) otherwise.
+func GenerateJWT(email string, opts ...ClaimsOption) (string, error) {
+	return signClaims(NewClaims(email, opts...))
+}
+
+// signClaims signs the claims with the active signing key.
+func signClaims(claims jwt.Claims) (string, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return "", fmt.Errorf("failed to load signing keys: %v", err)
+	}
+	if ks != nil {
+		key, err := ks.SigningKey()
+		if err != nil {
+			return "", err
+		}
+		token := jwt.NewWithClaims(key.Method, claims)
+		token.Header["kid"] = key.ID
+		tokenString, err := token.SignedString(key.Private)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign token: %v", err)
+		}
+		return tokenString, nil
+	}
+
 	secret, err := getJWTSecret()
 	if err != nil {
 		return "", err
 	}
-
-	claims := NewClaims(email)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
 	tokenString, err := token.SignedString(secret)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %v", err)
@@ -59,36 +187,57 @@ func GenerateJWT(email string) (string, error) {
 	return tokenString, nil
 }
 
-// VerifyJWT verifies a JWT token, strictly checks the signing method, and extracts claims.
+// VerifyJWT verifies an access token, strictly checks the signing method, and extracts claims.
 func VerifyJWT(tokenStr string) (*Claims, error) {
-	secret, err := getJWTSecret()
-	if err != nil {
-		return nil, err
-	}
+	return verifyPurposeJWT(tokenStr, "")
+}
 
+// verifyPurposeJWT verifies a token issued for the given purpose; "" means an access token.
+func verifyPurposeJWT(tokenStr, purpose string) (*Claims, error) {
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		// Ensure the token method conforms to expected signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenStr, claims, verificationKey)
 	if err != nil || !token.Valid {
 		return nil, fmt.Errorf("invalid or expired token")
 	}
+	if claims.Purpose != purpose {
+		return nil, fmt.Errorf("invalid or expired token")
+	}
 	return claims, nil
 }
 
-// CurrentUser extracts the user email from context metadata safely.
-func CurrentUser(ctx context.Context) (string, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
+// acceptHS256 reports whether JWT_ACCEPT_HS256 keeps HMAC tokens valid next to signing
+// keys, while tokens issued before the switch to asymmetric keys expire.
+func acceptHS256() bool {
+	accept, _ := strconv.ParseBool(os.Getenv("JWT_ACCEPT_HS256"))
+	return accept
+}
+
+// verificationKey resolves the key for a token. HMAC tokens are accepted only when
+// JWT_SECRET is set and no signing keys are loaded, unless JWT_ACCEPT_HS256 is set;
+// asymmetric tokens must name a known key through their kid and use the algorithm
+// that belongs to that key.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if ks != nil && !acceptHS256() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return getJWTSecret()
+	}
+
+	if ks == nil {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.Key(kid)
 	if !ok {
-		return "", fmt.Errorf("missing metadata")
+		return nil, fmt.Errorf("unknown signing key %q", kid)
 	}
-	currentUser, exists := md["current_user"]
-	if !exists || len(currentUser) == 0 {
-		return "", fmt.Errorf("current_user metadata is missing")
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	}
-	return currentUser[0], nil
+	return key.Public, nil
 }
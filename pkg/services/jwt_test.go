@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/metadata"
@@ -36,13 +37,29 @@ func TestVerifyJWT(t *testing.T) {
 
 func TestCurrentUser(t *testing.T) {
 	email := "user@test.com"
-	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("current_user", email))
+	ctx := WithPrincipal(context.Background(), NewPrincipal(NewClaims(email)))
 
 	currentUser, err := CurrentUser(ctx)
 	assert.NoError(t, err, "expected no error from CurrentUser")
 	assert.Equal(t, email, currentUser, "expected current user to match email")
 
-	// Test without metadata
+	// Test without an authenticated caller
 	_, err = CurrentUser(context.Background())
-	assert.Error(t, err, "expected error from CurrentUser with missing metadata")
+	assert.Error(t, err, "expected error from CurrentUser without a principal")
+
+	// Metadata sent by the client is not an identity
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("current_user", email))
+	_, err = CurrentUser(ctx)
+	assert.Error(t, err, "expected current_user metadata to be ignored")
+}
+
+func TestAccessTokenTTL(t *testing.T) {
+	t.Setenv("JWT_ACCESS_TOKEN_TTL", "")
+	assert.Equal(t, defaultAccessTokenTTL, AccessTokenTTL())
+
+	t.Setenv("JWT_ACCESS_TOKEN_TTL", "5m")
+	assert.Equal(t, 5*time.Minute, AccessTokenTTL())
+
+	claims := NewClaims("ttl@test.com")
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), claims.ExpiresAt.Time, time.Second)
 }
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultKeyReloadInterval is how often key files are checked for changes when
+// JWT_KEYS_RELOAD_INTERVAL is unset.
+const defaultKeyReloadInterval = time.Minute
+
+// SigningKey is an asymmetric key identified by its kid.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	// Private is nil for keys that are only kept around to verify older tokens.
+	Private crypto.Signer
+	Public  crypto.PublicKey
+	modTime time.Time
+}
+
+// KeySet holds the asymmetric keys used to sign and verify tokens. Keys are loaded
+// from PEM files, either listed explicitly or found in a directory, and are reloaded
+// when the files change so keys can be rotated without a restart.
+type KeySet struct {
+	mu             sync.RWMutex
+	dir            string
+	files          []string
+	preferredID    string
+	reloadInterval time.Duration
+	lastCheck      time.Time
+	fingerprint    string
+	keys           map[string]*SigningKey
+	signingID      string
+}
+
+// NewKeySet loads the keys found in dir (any *.pem file) and in the given files. The
+// kid of each key is its file name without extension. The key named preferredID signs
+// new tokens; when empty, the most recently modified private key is used.
+func NewKeySet(dir string, files []string, preferredID string, reloadInterval time.Duration) (*KeySet, error) {
+	ks := &KeySet{
+		dir:            dir,
+		files:          files,
+		preferredID:    preferredID,
+		reloadInterval: reloadInterval,
+	}
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// LoadKeySetFromEnv builds a KeySet from JWT_KEYS_DIR, JWT_KEY_FILES (comma separated),
+// JWT_SIGNING_KEY_ID and JWT_KEYS_RELOAD_INTERVAL. It returns nil when no keys are
+// configured, in which case tokens are signed with JWT_SECRET (HS256).
+func LoadKeySetFromEnv() (*KeySet, error) {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	var files []string
+	for _, f := range strings.Split(os.Getenv("JWT_KEY_FILES"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	if dir == "" && len(files) == 0 {
+		return nil, nil
+	}
+	interval := defaultKeyReloadInterval
+	if d, err := time.ParseDuration(os.Getenv("JWT_KEYS_RELOAD_INTERVAL")); err == nil {
+		interval = d
+	}
+	return NewKeySet(dir, files, os.Getenv("JWT_SIGNING_KEY_ID"), interval)
+}
+
+var (
+	keySetOnce    sync.Once
+	keySetMu      sync.RWMutex
+	defaultKeySet *KeySet
+	keySetErr     error
+)
+
+// SetKeySet replaces the key set used by GenerateJWT and VerifyJWT. Passing nil
+// switches back to HMAC signing with JWT_SECRET.
+func SetKeySet(ks *KeySet) {
+	keySetOnce.Do(func() {})
+	keySetMu.Lock()
+	defer keySetMu.Unlock()
+	defaultKeySet, keySetErr = ks, nil
+}
+
+// currentKeySet returns the configured key set, loading it from the environment on first use.
+func currentKeySet() (*KeySet, error) {
+	keySetOnce.Do(func() {
+		ks, err := LoadKeySetFromEnv()
+		keySetMu.Lock()
+		defaultKeySet, keySetErr = ks, err
+		keySetMu.Unlock()
+	})
+	keySetMu.RLock()
+	defer keySetMu.RUnlock()
+	return defaultKeySet, keySetErr
+}
+
+// SigningKey returns the key used to sign new tokens.
+func (ks *KeySet) SigningKey() (*SigningKey, error) {
+	ks.maybeReload()
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok := ks.keys[ks.signingID]
+	if !ok || key.Private == nil {
+		return nil, fmt.Errorf("no private signing key available")
+	}
+	return key, nil
+}
+
+// Key returns the key with the given kid.
+func (ks *KeySet) Key(kid string) (*SigningKey, bool) {
+	ks.maybeReload()
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// Keys returns all loaded keys sorted by kid.
+func (ks *KeySet) Keys() []*SigningKey {
+	ks.maybeReload()
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	keys := make([]*SigningKey, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
+
+// maybeReload reloads the keys when the reload interval elapsed and the files changed.
+func (ks *KeySet) maybeReload() {
+	if ks.reloadInterval <= 0 {
+		return
+	}
+	ks.mu.RLock()
+	due := time.Since(ks.lastCheck) >= ks.reloadInterval
+	ks.mu.RUnlock()
+	if !due {
+		return
+	}
+	if err := ks.Reload(); err != nil {
+		// Keep serving with the previously loaded keys.
+		ks.mu.Lock()
+		ks.lastCheck = time.Now()
+		ks.mu.Unlock()
+	}
+}
+
+// Reload re-reads the key files if any of them changed since the last load.
+func (ks *KeySet) Reload() error {
+	paths, err := ks.paths()
+	if err != nil {
+		return err
+	}
+	fingerprint, err := fingerprintFiles(paths)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.RLock()
+	unchanged := ks.keys != nil && fingerprint == ks.fingerprint
+	ks.mu.RUnlock()
+	if unchanged {
+		ks.mu.Lock()
+		ks.lastCheck = time.Now()
+		ks.mu.Unlock()
+		return nil
+	}
+
+	keys := make(map[string]*SigningKey, len(paths))
+	for _, path := range paths {
+		key, err := loadSigningKey(path)
+		if err != nil {
+			return err
+		}
+		if _, dup := keys[key.ID]; dup {
+			return fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		keys[key.ID] = key
+	}
+	signingID, err := pickSigningKey(keys, ks.preferredID)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys = keys
+	ks.signingID = signingID
+	ks.fingerprint = fingerprint
+	ks.lastCheck = time.Now()
+	return nil
+}
+
+// paths lists the configured key files.
+func (ks *KeySet) paths() ([]string, error) {
+	paths := append([]string(nil), ks.files...)
+	if ks.dir != "" {
+		matches, err := filepath.Glob(filepath.Join(ks.dir, "*.pem"))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no key files found")
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// fingerprintFiles summarizes names, sizes and modification times of the files.
+func fingerprintFiles(paths []string) (string, error) {
+	var sb strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return sb.String(), nil
+}
+
+// pickSigningKey chooses the preferred key, or the newest private key.
+func pickSigningKey(keys map[string]*SigningKey, preferredID string) (string, error) {
+	if preferredID != "" {
+		key, ok := keys[preferredID]
+		if !ok || key.Private == nil {
+			return "", fmt.Errorf("signing key %q not found or has no private key", preferredID)
+		}
+		return preferredID, nil
+	}
+	var newest *SigningKey
+	for _, key := range keys {
+		if key.Private == nil {
+			continue
+		}
+		if newest == nil || key.modTime.After(newest.modTime) ||
+			(key.modTime.Equal(newest.modTime) && key.ID > newest.ID) {
+			newest = key
+		}
+	}
+	if newest == nil {
+		return "", fmt.Errorf("no private key found")
+	}
+	return newest.ID, nil
+}
+
+// loadSigningKey parses a PEM file holding a private or public key.
+func loadSigningKey(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+
+	key := &SigningKey{
+		ID:      strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		modTime: info.ModTime(),
+	}
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.Private = signer
+		key.Public = signer.Public()
+	} else {
+		key.Public = parsed
+	}
+	if key.Method, err = signingMethodFor(key.Public); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return key, nil
+}
+
+// signingMethodFor maps a public key to the JWS algorithm used with it.
+func signingMethodFor(pub crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+		return nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", pub)
+}
+
+// jsonWebKey is the JWK representation of a public key (RFC 7517).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS returns the JSON Web Key Set with the public keys used to verify tokens. In
+// HMAC mode the set is empty, since the secret must never be published.
+func JWKS() ([]byte, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return nil, err
+	}
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{Keys: []jsonWebKey{}}
+	if ks != nil {
+		for _, key := range ks.Keys() {
+			jwk, err := toJWK(key)
+			if err != nil {
+				return nil, err
+			}
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return json.Marshal(set)
+}
+
+// toJWK converts a key to its public JWK.
+func toJWK(key *SigningKey) (jsonWebKey, error) {
+	jwk := jsonWebKey{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+	enc := base64.RawURLEncoding
+	switch pub := key.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = enc.EncodeToString(pub.N.Bytes())
+		jwk.E = enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		point, err := pub.ECDH()
+		if err != nil {
+			return jwk, err
+		}
+		// Uncompressed point: 0x04 || X || Y
+		raw := point.Bytes()[1:]
+		size := len(raw) / 2
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = enc.EncodeToString(raw[:size])
+		jwk.Y = enc.EncodeToString(raw[size:])
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = enc.EncodeToString(pub)
+	default:
+		return jwk, fmt.Errorf("unsupported key type %T", pub)
+	}
+	return jwk, nil
+}
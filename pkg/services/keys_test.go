@@ -0,0 +1,175 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePrivateKey stores key as a PKCS#8 PEM file and sets its modification time.
+func writePrivateKey(t *testing.T, dir, kid string, key crypto.Signer, modTime time.Time) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(dir, kid+".pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	return path
+}
+
+func useKeySet(t *testing.T, ks *KeySet) {
+	t.Helper()
+	SetKeySet(ks)
+	t.Cleanup(func() { SetKeySet(nil) })
+}
+
+func TestAsymmetricSigning(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		key crypto.Signer
+		alg string
+	}{
+		"rsa":     {rsaKey, "RS256"},
+		"ecdsa":   {ecKey, "ES256"},
+		"ed25519": {edKey, "EdDSA"},
+	}
+	for kid, tc := range cases {
+		t.Run(kid, func(t *testing.T) {
+			dir := t.TempDir()
+			writePrivateKey(t, dir, kid, tc.key, time.Now())
+			ks, err := NewKeySet(dir, nil, "", 0)
+			require.NoError(t, err)
+			useKeySet(t, ks)
+
+			token, err := GenerateJWT("keys@test.com")
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, kid, parsed.Header["kid"], "expected kid header to name the signing key")
+			assert.Equal(t, tc.alg, parsed.Method.Alg())
+
+			claims, err := VerifyJWT(token)
+			require.NoError(t, err)
+			assert.Equal(t, "keys@test.com", claims.Email)
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "2024-01", oldKey, time.Now().Add(-time.Hour))
+
+	ks, err := NewKeySet(dir, nil, "", 0)
+	require.NoError(t, err)
+	useKeySet(t, ks)
+
+	oldToken, err := GenerateJWT("rotate@test.com")
+	require.NoError(t, err)
+
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "2024-02", newKey, time.Now())
+	require.NoError(t, ks.Reload())
+
+	signing, err := ks.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02", signing.ID, "expected the newest key to sign after rotation")
+
+	_, err = VerifyJWT(oldToken)
+	assert.NoError(t, err, "expected tokens signed with the previous key to stay valid")
+
+	body, err := JWKS()
+	require.NoError(t, err)
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(body, &set))
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "2024-01", set.Keys[0]["kid"])
+	assert.Equal(t, "EC", set.Keys[0]["kty"])
+	assert.Equal(t, "P-256", set.Keys[0]["crv"])
+	assert.NotEmpty(t, set.Keys[0]["x"])
+	assert.Empty(t, set.Keys[0]["d"], "expected private material to stay out of the JWKS")
+}
+
+func TestVerifyRejectsUnknownKid(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "known", key, time.Now())
+	ks, err := NewKeySet(dir, nil, "", 0)
+	require.NoError(t, err)
+	useKeySet(t, ks)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, NewClaims("kid@test.com"))
+	token.Header["kid"] = "unknown"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	_, err = VerifyJWT(signed)
+	assert.Error(t, err, "expected token with unknown kid to be rejected")
+}
+
+func TestHMACStillAccepted(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	t.Setenv("JWT_ACCEPT_HS256", "true")
+	SetKeySet(nil)
+
+	token, err := GenerateJWT("hmac@test.com")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "ec", key, time.Now())
+	ks, err := NewKeySet(dir, nil, "", 0)
+	require.NoError(t, err)
+	useKeySet(t, ks)
+
+	claims, err := VerifyJWT(token)
+	require.NoError(t, err, "expected HS256 tokens to verify while JWT_ACCEPT_HS256 is set")
+	assert.Equal(t, "hmac@test.com", claims.Email)
+}
+
+func TestHMACRejectedWithKeySet(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	t.Setenv("JWT_ACCEPT_HS256", "")
+	SetKeySet(nil)
+
+	token, err := GenerateJWT("hmac@test.com")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePrivateKey(t, dir, "ed", key, time.Now())
+	ks, err := NewKeySet(dir, nil, "", 0)
+	require.NoError(t, err)
+	useKeySet(t, ks)
+
+	_, err = VerifyJWT(token)
+	assert.Error(t, err, "expected HS256 tokens to be rejected once signing keys are loaded")
+}
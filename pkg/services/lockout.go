@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"db"
+	"errors"
+	"fmt"
+	. "generated"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Backoff between failed logins: the wait doubles with every failure, starting at
+// loginBackoffBase and capped at loginBackoffMax.
+const (
+	loginBackoffBase = time.Second
+	loginBackoffMax  = 30 * time.Second
+)
+
+// LoginAttempts is the failed login state of an account or a client IP.
+type LoginAttempts struct {
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+// LoginAttemptStore keeps failed login attempts, keyed by account or client IP.
+type LoginAttemptStore interface {
+	// Get returns the attempts of key; the zero value if there are none.
+	Get(ctx context.Context, key string) (LoginAttempts, error)
+	// RecordFailure counts a failed attempt and returns the updated state. The count
+	// starts over when the previous failure is older than window.
+	RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (LoginAttempts, error)
+	// Lock refuses logins for key until the given time.
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset clears the attempts of key.
+	Reset(ctx context.Context, key string) error
+}
+
+var (
+	loginAttemptsMu    sync.RWMutex
+	loginAttemptsStore LoginAttemptStore = NewMemoryLoginAttemptStore()
+)
+
+// SetLoginAttemptStore replaces the store used to track failed logins. It defaults to
+// an in-memory store.
+func SetLoginAttemptStore(store LoginAttemptStore) {
+	loginAttemptsMu.Lock()
+	defer loginAttemptsMu.Unlock()
+	loginAttemptsStore = store
+}
+
+// loginAttempts returns the active login attempt store.
+func loginAttempts() LoginAttemptStore {
+	loginAttemptsMu.RLock()
+	defer loginAttemptsMu.RUnlock()
+	return loginAttemptsStore
+}
+
+// LoadLoginAttemptStoreFromEnv builds the store selected by LOGIN_ATTEMPT_STORE:
+// "database", the default, or "memory".
+func LoadLoginAttemptStoreFromEnv(client *db.PrismaClient) (LoginAttemptStore, error) {
+	switch kind := os.Getenv("LOGIN_ATTEMPT_STORE"); kind {
+	case "", "database":
+		return NewPrismaLoginAttemptStore(client), nil
+	case "memory":
+		return NewMemoryLoginAttemptStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown LOGIN_ATTEMPT_STORE %q", kind)
+	}
+}
+
+// LockoutPolicy decides when failed logins lock an account or a client IP.
+type LockoutPolicy struct {
+	// MaxAccountFailures locks an account after that many failures.
+	MaxAccountFailures int
+	// MaxIPFailures locks a client IP after that many failures, across accounts.
+	MaxIPFailures int
+	// LockoutDuration is how long a lock lasts, and how long failures are remembered.
+	LockoutDuration time.Duration
+}
+
+// LockoutPolicyFromEnv reads the policy from LOGIN_MAX_FAILURES (default 5),
+// LOGIN_MAX_IP_FAILURES (default 20) and LOGIN_LOCKOUT_DURATION (default 15m).
+func LockoutPolicyFromEnv() LockoutPolicy {
+	policy := LockoutPolicy{
+		MaxAccountFailures: 5,
+		MaxIPFailures:      20,
+		LockoutDuration:    15 * time.Minute,
+	}
+	if n, err := strconv.Atoi(os.Getenv("LOGIN_MAX_FAILURES")); err == nil && n > 0 {
+		policy.MaxAccountFailures = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LOGIN_MAX_IP_FAILURES")); err == nil && n > 0 {
+		policy.MaxIPFailures = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("LOGIN_LOCKOUT_DURATION")); err == nil && d > 0 {
+		policy.LockoutDuration = d
+	}
+	return policy
+}
+
+// retryAfter returns how long the next attempt has to wait, or 0 if it may go ahead.
+func (p LockoutPolicy) retryAfter(attempts LoginAttempts, now time.Time) time.Duration {
+	if now.Before(attempts.LockedUntil) {
+		return attempts.LockedUntil.Sub(now)
+	}
+	if attempts.Failures == 0 || now.Sub(attempts.LastFailure) > p.LockoutDuration {
+		return 0
+	}
+	backoff := loginBackoffMax
+	if shift := attempts.Failures - 1; shift < 16 {
+		backoff = min(loginBackoffBase<<shift, loginBackoffMax)
+	}
+	if next := attempts.LastFailure.Add(backoff); now.Before(next) {
+		return next.Sub(now)
+	}
+	return 0
+}
+
+// accountAttemptKey and ipAttemptKey build the keys of the login attempt store. Accounts
+// are keyed by tenant and email, so that attempts on unknown addresses are throttled alike.
+func accountAttemptKey(tenantID, email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if tenantID == "" {
+		return "account:" + email
+	}
+	return "account:" + tenantID + "/" + email
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginGuard applies the lockout policy to one login: the account and the client IP.
+type loginGuard struct {
+	policy LockoutPolicy
+	store  LoginAttemptStore
+	keys   map[string]int // key -> failures before it locks
+}
+
+// newLoginGuard guards the account of email and the client IP. A loopback or unknown
+// client IP is not guarded: it is the address of the gateways when proxy headers are
+// not believed, and locking it would lock everyone out.
+func newLoginGuard(ctx context.Context, email string) *loginGuard {
+	policy := LockoutPolicyFromEnv()
+	g := &loginGuard{
+		policy: policy,
+		store:  loginAttempts(),
+		keys:   map[string]int{accountAttemptKey(TenantID(ctx), email): policy.MaxAccountFailures},
+	}
+	if ip, err := netip.ParseAddr(ClientIP(ctx)); err == nil && !ip.IsLoopback() && !ip.IsUnspecified() {
+		g.keys[ipAttemptKey(ip.String())] = policy.MaxIPFailures
+	}
+	return g
+}
+
+// check returns a ResourceExhausted status carrying RetryInfo while the account or the
+// client IP has to wait.
+func (g *loginGuard) check(ctx context.Context) error {
+	now := time.Now()
+	var wait time.Duration
+	for key := range g.keys {
+		attempts, err := g.store.Get(ctx, key)
+		if err != nil {
+			return status.Errorf(codes.Internal, "could not check login attempts")
+		}
+		wait = max(wait, g.policy.retryAfter(attempts, now))
+	}
+	if wait == 0 {
+		return nil
+	}
+	return retryLaterStatus("too many failed login attempts, retry later", wait)
+}
+
+// fail records a failed attempt and locks the keys that reached their limit.
+func (g *loginGuard) fail(ctx context.Context) error {
+	now := time.Now()
+	for key, limit := range g.keys {
+		attempts, err := g.store.RecordFailure(ctx, key, now, g.policy.LockoutDuration)
+		if err != nil {
+			return err
+		}
+		if attempts.Failures >= limit {
+			if err := g.store.Lock(ctx, key, now.Add(g.policy.LockoutDuration)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// succeed clears the failures of the account. The IP keeps its count, so that logging
+// into an own account does not reset the throttling of attempts on others.
+func (g *loginGuard) succeed(ctx context.Context, email string) error {
+	return g.store.Reset(ctx, accountAttemptKey(TenantID(ctx), email))
+}
+
+// recordLoginFailure is fail for handlers: errors are logged rather than returned, since the
+// caller is answering with the failed login anyway.
+func (s *AuthServiceServer) recordLoginFailure(ctx context.Context, g *loginGuard, email string) {
+	if err := g.fail(ctx); err != nil {
+		s.Logger.Errorw("Failed to record failed login", "email", email, "error", err)
+	}
+}
+
+// retryLaterStatus returns a ResourceExhausted status with a RetryInfo detail.
+func retryLaterStatus(msg string, wait time.Duration) error {
+	st := status.New(codes.ResourceExhausted, msg)
+	// Round up, so that clients retrying after the hint are not refused again.
+	delay := wait.Truncate(time.Second) + time.Second
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// UnlockUser clears the failed login attempts of an account, lifting its lock.
+func (s *AuthServiceServer) UnlockUser(ctx context.Context, in *UnlockUserRequest) (*UnlockUserReply, error) {
+	if in.Email == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "email is required")
+	}
+	if err := loginAttempts().Reset(ctx, accountAttemptKey(TenantID(ctx), in.Email)); err != nil {
+		s.Logger.Errorw("Failed to unlock user", "email", in.Email, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not unlock user")
+	}
+	s.Logger.Infow("User unlocked", "email", in.Email)
+	return &UnlockUserReply{Reply: fmt.Sprintf("User %s unlocked", in.Email)}, nil
+}
+
+// maxMemoryLoginAttempts bounds the memory used by MemoryLoginAttemptStore.
+const maxMemoryLoginAttempts = 100000
+
+// MemoryLoginAttemptStore keeps login attempts in process memory.
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]LoginAttempts
+}
+
+// NewMemoryLoginAttemptStore creates an empty in-memory store.
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]LoginAttempts)}
+}
+
+func (m *MemoryLoginAttemptStore) Get(ctx context.Context, key string) (LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.attempts[key], nil
+}
+
+func (m *MemoryLoginAttemptStore) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.attempts) >= maxMemoryLoginAttempts {
+		for k, a := range m.attempts {
+			if now.Sub(a.LastFailure) > window && now.After(a.LockedUntil) {
+				delete(m.attempts, k)
+			}
+		}
+	}
+	attempts := m.attempts[key]
+	if now.Sub(attempts.LastFailure) > window {
+		attempts.Failures = 0
+	}
+	attempts.Failures++
+	attempts.LastFailure = now
+	m.attempts[key] = attempts
+	return attempts, nil
+}
+
+func (m *MemoryLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempts := m.attempts[key]
+	attempts.LockedUntil = until
+	m.attempts[key] = attempts
+	return nil
+}
+
+func (m *MemoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, key)
+	return nil
+}
+
+// PrismaLoginAttemptStore persists login attempts in the LoginAttempt table, so that
+// they are shared between instances.
+type PrismaLoginAttemptStore struct {
+	client *db.PrismaClient
+}
+
+// NewPrismaLoginAttemptStore creates a store backed by the given Prisma client.
+func NewPrismaLoginAttemptStore(client *db.PrismaClient) *PrismaLoginAttemptStore {
+	return &PrismaLoginAttemptStore{client: client}
+}
+
+func (p *PrismaLoginAttemptStore) Get(ctx context.Context, key string) (LoginAttempts, error) {
+	row, err := p.client.LoginAttempt.FindUnique(
+		db.LoginAttempt.Key.Equals(key),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return LoginAttempts{}, nil
+	}
+	if err != nil {
+		return LoginAttempts{}, err
+	}
+	return loginAttemptsFromModel(row), nil
+}
+
+func (p *PrismaLoginAttemptStore) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (LoginAttempts, error) {
+	current, err := p.Get(ctx, key)
+	if err != nil {
+		return LoginAttempts{}, err
+	}
+	failures := db.LoginAttempt.Failures.Increment(1)
+	if now.Sub(current.LastFailure) > window {
+		failures = db.LoginAttempt.Failures.Set(1)
+	}
+	row, err := p.client.LoginAttempt.UpsertOne(
+		db.LoginAttempt.Key.Equals(key),
+	).Create(
+		db.LoginAttempt.Key.Set(key),
+		db.LoginAttempt.LastFailure.Set(now),
+		db.LoginAttempt.Failures.Set(1),
+	).Update(
+		failures,
+		db.LoginAttempt.LastFailure.Set(now),
+	).Exec(ctx)
+	if err != nil {
+		return LoginAttempts{}, err
+	}
+	return loginAttemptsFromModel(row), nil
+}
+
+func (p *PrismaLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := p.client.LoginAttempt.FindUnique(
+		db.LoginAttempt.Key.Equals(key),
+	).Update(
+		db.LoginAttempt.LockedUntil.Set(until),
+	).Exec(ctx)
+	return err
+}
+
+func (p *PrismaLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	_, err := p.client.LoginAttempt.FindMany(
+		db.LoginAttempt.Key.Equals(key),
+	).Delete().Exec(ctx)
+	return err
+}
+
+func loginAttemptsFromModel(row *db.LoginAttemptModel) LoginAttempts {
+	attempts := LoginAttempts{Failures: row.Failures, LastFailure: row.LastFailure}
+	if until, ok := row.LockedUntil(); ok {
+		attempts.LockedUntil = until
+	}
+	return attempts
+}
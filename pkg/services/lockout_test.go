@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"db"
+	. "generated"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func useLoginAttemptStore(t *testing.T, store LoginAttemptStore) {
+	t.Helper()
+	SetLoginAttemptStore(store)
+	t.Cleanup(func() { SetLoginAttemptStore(NewMemoryLoginAttemptStore()) })
+}
+
+func TestLockoutPolicyRetryAfter(t *testing.T) {
+	policy := LockoutPolicy{MaxAccountFailures: 5, MaxIPFailures: 20, LockoutDuration: 15 * time.Minute}
+	now := time.Now()
+
+	assert.Zero(t, policy.retryAfter(LoginAttempts{}, now))
+	assert.Equal(t, time.Second, policy.retryAfter(LoginAttempts{Failures: 1, LastFailure: now}, now))
+	assert.Equal(t, 8*time.Second, policy.retryAfter(LoginAttempts{Failures: 4, LastFailure: now}, now), "expected backoff to double with every failure")
+	assert.Equal(t, loginBackoffMax, policy.retryAfter(LoginAttempts{Failures: 40, LastFailure: now}, now), "expected backoff to be capped")
+	assert.Zero(t, policy.retryAfter(LoginAttempts{Failures: 3, LastFailure: now.Add(-time.Minute)}, now))
+	assert.Equal(t, time.Minute, policy.retryAfter(LoginAttempts{Failures: 5, LastFailure: now, LockedUntil: now.Add(time.Minute)}, now))
+}
+
+func TestMemoryLoginAttemptStoreWindow(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryLoginAttemptStore()
+	now := time.Now()
+
+	_, err := store.RecordFailure(ctx, "account:a", now.Add(-time.Hour), 15*time.Minute)
+	require.NoError(t, err)
+	attempts, err := store.RecordFailure(ctx, "account:a", now, 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts.Failures, "expected failures outside the window to be forgotten")
+
+	attempts, err = store.RecordFailure(ctx, "account:a", now, 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts.Failures)
+
+	require.NoError(t, store.Reset(ctx, "account:a"))
+	attempts, err = store.Get(ctx, "account:a")
+	require.NoError(t, err)
+	assert.Zero(t, attempts.Failures)
+}
+
+func TestLoginLockout(t *testing.T) {
+	t.Setenv("LOGIN_MAX_FAILURES", "1")
+	useLoginAttemptStore(t, NewMemoryLoginAttemptStore())
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.User.Expect(
+		client.User.FindUnique(
+			Scope(context.Background(), client).UserByEmail("locked@test.com"),
+		),
+	).Errors(db.ErrNotFound)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	login := &LoginRequest{Email: "locked@test.com", Password: "wrong"}
+
+	_, err := s.Login(context.Background(), login)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = s.Login(context.Background(), login)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), "expected the account to be locked")
+	var retry *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	require.NotNil(t, retry, "expected a retry-after hint in the status details")
+	assert.Greater(t, retry.RetryDelay.AsDuration(), 14*time.Minute)
+
+	_, err = s.UnlockUser(context.Background(), &UnlockUserRequest{Email: "Locked@test.com"})
+	require.NoError(t, err)
+
+	_, err = s.Login(context.Background(), login)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "expected the account to be usable after unlocking")
+}
+
+func TestLoginGuardSkipsLoopbackIP(t *testing.T) {
+	guarded := func(ip string) bool {
+		g := newLoginGuard(WithClientIP(context.Background(), ip), "user@test.com")
+		_, ok := g.keys[ipAttemptKey(ip)]
+		return ok
+	}
+	assert.True(t, guarded("203.0.113.7"))
+	assert.False(t, guarded("127.0.0.1"), "expected the gateway address not to be locked")
+	assert.False(t, guarded("::1"))
+	assert.False(t, guarded(""))
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"db"
+	"errors"
+	"fmt"
+	. "generated"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Logout revokes the access token used for the call and ends its session. When a
+// refresh token is given, its family is revoked as well so that it cannot be refreshed.
+func (s *AuthServiceServer) Logout(ctx context.Context, in *LogoutRequest) (*LogoutReply, error) {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		s.Logger.Warnw("Logout failed: not authenticated")
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+
+	if in.RefreshToken != "" {
+		stored, err := s.PrismaClient.RefreshToken.FindUnique(
+			db.RefreshToken.TokenHash.Equals(hashToken(in.RefreshToken)),
+		).Exec(ctx)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			s.Logger.Infow("Logout with unknown refresh token", "email", principal.Email)
+		case err != nil:
+			s.Logger.Errorw("Failed to look up refresh token", "error", err)
+			return nil, status.Errorf(codes.Internal, "could not log out")
+		case stored.UserID != principal.UserID:
+			s.Logger.Warnw("Logout with refresh token of another user", "email", principal.Email)
+			return nil, status.Errorf(codes.PermissionDenied, "refresh token does not belong to the current user")
+		default:
+			if err := s.revokeRefreshTokenFamily(ctx, stored.FamilyID); err != nil {
+				s.Logger.Errorw("Failed to revoke refresh token family", "family", stored.FamilyID, "error", err)
+				return nil, status.Errorf(codes.Internal, "could not log out")
+			}
+		}
+	}
+
+	if principal.SessionID != "" {
+		if _, err := s.revokeSession(ctx, principal.UserID, principal.SessionID); err != nil {
+			s.Logger.Errorw("Failed to revoke session", "session", principal.SessionID, "error", err)
+			return nil, status.Errorf(codes.Internal, "could not log out")
+		}
+	}
+
+	if principal.Method == AuthMethodJWT && principal.TokenID != "" {
+		if err := Revocations().RevokeToken(ctx, principal.TokenID, principal.ExpiresAt); err != nil {
+			s.Logger.Errorw("Failed to revoke access token", "email", principal.Email, "error", err)
+			return nil, status.Errorf(codes.Internal, "could not log out")
+		}
+	}
+
+	s.Logger.Infof("Logged out email %s", principal.Email)
+	auditTokenRevoke(ctx, "", "logout")
+	return &LogoutReply{Reply: "Logged out"}, nil
+}
+
+// RevokeAllSessions invalidates every access and refresh token of the current user,
+// including the token used for the call.
+func (s *AuthServiceServer) RevokeAllSessions(ctx context.Context, in *RevokeAllSessionsRequest) (*RevokeAllSessionsReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	principal, ok := FromContext(ctx)
+	if !ok {
+		s.Logger.Warnw("Revoke all sessions failed: not authenticated")
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+
+	userID := principal.UserID
+	if userID == "" {
+		// Tokens issued before subjects were added only carry the email.
+		user, err := Scope(ctx, s.PrismaClient).FindUserByEmail(ctx, principal.Email)
+		if err != nil {
+			s.Logger.Warnw("Revoke all sessions failed: user not found", "email", principal.Email, "error", err)
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		userID = user.ID
+	}
+
+	if err := s.revokeUserSessions(ctx, userID); err != nil {
+		s.Logger.Errorw("Failed to revoke sessions", "user", userID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not revoke sessions")
+	}
+
+	s.Logger.Infow("Revoked all sessions", "email", principal.Email)
+	auditTokenRevoke(ctx, "", "all sessions")
+	return &RevokeAllSessionsReply{Reply: "All sessions revoked"}, nil
+}
+
+// revokeUserSessions revokes the access tokens issued to the user so far, all of their
+// refresh tokens and their sessions.
+func (s *AuthServiceServer) revokeUserSessions(ctx context.Context, userID string) error {
+	now := revocationCutoff()
+	if err := Revocations().RevokeUserTokens(ctx, userID, now); err != nil {
+		return fmt.Errorf("could not revoke access tokens: %v", err)
+	}
+	if _, err := s.PrismaClient.RefreshToken.FindMany(
+		db.RefreshToken.UserID.Equals(userID),
+		db.RefreshToken.RevokedAt.IsNull(),
+	).Update(
+		db.RefreshToken.RevokedAt.Set(now),
+	).Exec(ctx); err != nil {
+		return fmt.Errorf("could not revoke refresh tokens: %v", err)
+	}
+	if _, err := s.PrismaClient.Session.FindMany(
+		db.Session.UserID.Equals(userID),
+		db.Session.RevokedAt.IsNull(),
+	).Update(
+		db.Session.RevokedAt.Set(now),
+	).Exec(ctx); err != nil {
+		return fmt.Errorf("could not revoke sessions: %v", err)
+	}
+	return nil
+}
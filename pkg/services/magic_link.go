@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"db"
+	"errors"
+	"fmt"
+	. "generated"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultMagicLinkTTL is the lifetime of magic links when MAGIC_LINK_TTL is unset.
+const defaultMagicLinkTTL = 10 * time.Minute
+
+// magicLinkReply is returned whether or not the email belongs to a user.
+const magicLinkReply = "If the email is registered, a login link has been sent."
+
+// MagicLinkTTL returns the magic link lifetime, configurable through the MAGIC_LINK_TTL
+// environment variable (e.g. "5m").
+func MagicLinkTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("MAGIC_LINK_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultMagicLinkTTL
+}
+
+// magicLinkMessage builds the login mail. The token is appended to MAGIC_LINK_URL when
+// it is set.
+func magicLinkMessage(email, token string) Message {
+	link := token
+	if base := os.Getenv("MAGIC_LINK_URL"); base != "" {
+		link = fmt.Sprintf("%s?token=%s", base, token)
+	}
+	return Message{
+		To:      email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf("Use the following link to log in:\n\n%s\n\n"+
+			"It only works on the device you asked for it from, and expires in %s. "+
+			"If you did not ask to log in, you can ignore this mail.", link, MagicLinkTTL()),
+	}
+}
+
+// RequestMagicLink mails a single-use login link to the user. The link is bound to the
+// nonce of the reply, which the requesting device has to present with it. The reply is
+// the same for unknown email addresses, so it cannot be used to discover accounts.
+func (s *AuthServiceServer) RequestMagicLink(ctx context.Context, in *RequestMagicLinkRequest) (*RequestMagicLinkReply, error) {
+	nonce, nonceHash, err := newOpaqueToken()
+	if err != nil {
+		s.Logger.Errorw("Failed to generate magic link nonce", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not request magic link")
+	}
+	reply := &RequestMagicLinkReply{Reply: magicLinkReply, Nonce: nonce}
+
+	user, err := Scope(ctx, s.PrismaClient).FindUserByEmail(ctx, in.Email)
+	if err != nil {
+		if !errors.Is(err, db.ErrNotFound) {
+			s.Logger.Errorw("Failed to look up user for magic link", "error", err)
+			return nil, status.Errorf(codes.Internal, "could not request magic link")
+		}
+		s.Logger.Infow("Magic link requested for unknown email")
+		return reply, nil
+	}
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		s.Logger.Errorw("Failed to generate magic link", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not request magic link")
+	}
+	if _, err := s.PrismaClient.MagicLinkToken.CreateOne(
+		db.MagicLinkToken.TokenHash.Set(hash),
+		db.MagicLinkToken.NonceHash.Set(nonceHash),
+		db.MagicLinkToken.User.Link(db.User.ID.Equals(user.ID)),
+		db.MagicLinkToken.ExpiresAt.Set(time.Now().Add(MagicLinkTTL())),
+	).Exec(ctx); err != nil {
+		s.Logger.Errorw("Failed to store magic link", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not request magic link")
+	}
+
+	s.sendMail(magicLinkMessage(user.Email, raw))
+	s.Logger.Infow("Magic link requested", "user", user.ID)
+	return reply, nil
+}
+
+// ConsumeMagicLink logs in with a link from RequestMagicLink and the nonce of the device
+// that asked for it. It returns the same tokens as Login, or a two-factor challenge. As
+// the link proves that the user owns the address, it also verifies the email.
+func (s *AuthServiceServer) ConsumeMagicLink(ctx context.Context, in *ConsumeMagicLinkRequest) (*LoginReply, error) {
+	if in.Token == "" || in.Nonce == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token and nonce are required")
+	}
+
+	stored, err := s.PrismaClient.MagicLinkToken.FindUnique(
+		db.MagicLinkToken.TokenHash.Equals(hashToken(in.Token)),
+	).With(
+		db.MagicLinkToken.User.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			s.Logger.Warnw("Magic link login failed: unknown token")
+			return nil, status.Errorf(codes.Unauthenticated, "invalid or expired link")
+		}
+		s.Logger.Errorw("Failed to look up magic link", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not log in")
+	}
+	user := stored.User()
+	// Links are only accepted within the tenant they were requested in.
+	if err := Scope(ctx, s.PrismaClient).Own(user); err != nil {
+		s.Logger.Warnw("Magic link login failed: other tenant", "user", user.ID)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired link")
+	}
+	if _, used := stored.UsedAt(); used || time.Now().After(stored.ExpiresAt) {
+		s.Logger.Warnw("Magic link login failed: link used or expired", "user", stored.UserID)
+		auditLoginFailure(ctx, user.ID, user.Email, "magic link used or expired")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired link")
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(in.Nonce)), []byte(stored.NonceHash)) != 1 {
+		// The link was opened on another device; it stays valid for the one that asked.
+		s.Logger.Warnw("Magic link login failed: nonce mismatch", "user", stored.UserID)
+		auditLoginFailure(ctx, user.ID, user.Email, "magic link nonce mismatch")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired link")
+	}
+
+	// Consume the link first so that concurrent requests cannot both use it.
+	claimed, err := s.PrismaClient.MagicLinkToken.FindMany(
+		db.MagicLinkToken.ID.Equals(stored.ID),
+		db.MagicLinkToken.UsedAt.IsNull(),
+	).Update(
+		db.MagicLinkToken.UsedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		s.Logger.Errorw("Failed to consume magic link", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not log in")
+	}
+	if claimed.Count == 0 {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired link")
+	}
+
+	if err := s.refuseDisabled(ctx, user); err != nil {
+		return nil, err
+	}
+	if !user.EmailVerified {
+		verified, err := Scope(ctx, s.PrismaClient).UpdateUser(ctx, user, db.User.EmailVerified.Set(true))
+		if err != nil {
+			s.Logger.Errorw("Failed to mark email verified", "user", user.ID, "error", err)
+			return nil, status.Errorf(codes.Internal, "could not log in")
+		}
+		user = verified
+	}
+
+	if user.TotpEnabled {
+		reply, err := twoFactorChallenge(user)
+		if err != nil {
+			s.Logger.Errorw("Error generating two-factor challenge", "email", user.Email, "error", err)
+			return nil, status.Errorf(codes.Internal, "could not generate token: %v", err)
+		}
+		return reply, nil
+	}
+	reply, _, err := s.issueTokens(ctx, user, "")
+	if err != nil {
+		s.Logger.Errorw("Error generating token", "email", user.Email, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not generate token: %v", err)
+	}
+	s.Logger.Infow("Generated token after magic link login", "email", user.Email)
+	auditLoginSuccess(ctx, user)
+	return reply, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"db"
+	. "generated"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestMagicLinkUnknownEmail(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.User.Expect(
+		client.User.FindUnique(Scope(context.Background(), client).UserByEmail("nobody@test.com")),
+	).Errors(db.ErrNotFound)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	reply, err := s.RequestMagicLink(context.Background(), &RequestMagicLinkRequest{Email: "nobody@test.com"})
+	require.NoError(t, err, "expected unknown emails not to be revealed")
+	assert.Equal(t, magicLinkReply, reply.Reply)
+	assert.NotEmpty(t, reply.Nonce, "expected a nonce for unknown emails as well")
+}
+
+func TestConsumeMagicLinkRefused(t *testing.T) {
+	useAuditStore(t)
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	user := db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "magic@test.com"}}
+	for token, row := range map[string]db.InnerMagicLinkToken{
+		"other-device": {ID: "link-1", UserID: "user-1", NonceHash: hashToken("device-nonce"), ExpiresAt: time.Now().Add(time.Minute)},
+		"expired":      {ID: "link-2", UserID: "user-1", NonceHash: hashToken("nonce"), ExpiresAt: time.Now().Add(-time.Minute)},
+	} {
+		mock.MagicLinkToken.Expect(
+			client.MagicLinkToken.FindUnique(
+				db.MagicLinkToken.TokenHash.Equals(hashToken(token)),
+			).With(
+				db.MagicLinkToken.User.Fetch(),
+			),
+		).Returns(db.MagicLinkTokenModel{
+			InnerMagicLinkToken:     row,
+			RelationsMagicLinkToken: db.RelationsMagicLinkToken{User: &user},
+		})
+	}
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err := s.ConsumeMagicLink(context.Background(), &ConsumeMagicLinkRequest{Token: "other-device", Nonce: "nonce"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "expected a link opened on another device to be rejected")
+
+	_, err = s.ConsumeMagicLink(context.Background(), &ConsumeMagicLinkRequest{Token: "expired", Nonce: "nonce"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "expected an expired link to be rejected")
+
+	_, err = s.ConsumeMagicLink(context.Background(), &ConsumeMagicLinkRequest{Token: "expired"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected a missing nonce to be rejected")
+}
+
+func TestMagicLinkMessage(t *testing.T) {
+	t.Setenv("MAGIC_LINK_URL", "https://app.test/magic")
+	msg := magicLinkMessage("user@test.com", "tok")
+	assert.Equal(t, "user@test.com", msg.To)
+	assert.Contains(t, msg.Body, "https://app.test/magic?token=tok")
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain text mail.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers mail sent by the Auth service, such as password reset links.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ErrMailerNotConfigured is returned when mail is sent before a mailer was set.
+var ErrMailerNotConfigured = errors.New("no mailer configured")
+
+// unconfiguredMailer refuses every message, so that tokens sent by mail never end up in
+// logs by default.
+type unconfiguredMailer struct{}
+
+func (unconfiguredMailer) Send(ctx context.Context, msg Message) error {
+	return ErrMailerNotConfigured
+}
+
+var (
+	mailerMu      sync.RWMutex
+	defaultMailer Mailer = unconfiguredMailer{}
+)
+
+// SetMailer replaces the mailer used by the Auth service. Until it is called, mail is
+// not sent.
+func SetMailer(m Mailer) {
+	mailerMu.Lock()
+	defer mailerMu.Unlock()
+	defaultMailer = m
+}
+
+// currentMailer returns the active mailer.
+func currentMailer() Mailer {
+	mailerMu.RLock()
+	defer mailerMu.RUnlock()
+	return defaultMailer
+}
+
+// LoadMailerFromEnv builds the mailer selected by MAILER, which is required: "smtp"
+// (configured through SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and MAIL_FROM),
+// or, for development only, "file" (appends to MAILER_FILE) or "stdout". The latter two
+// write the tokens of the mail in the clear.
+func LoadMailerFromEnv() (Mailer, error) {
+	switch kind := os.Getenv("MAILER"); kind {
+	case "":
+		return nil, fmt.Errorf("MAILER is not set; use smtp, or stdout or file in development")
+	case "stdout":
+		return NewWriterMailer(os.Stdout), nil
+	case "file":
+		path := os.Getenv("MAILER_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("MAILER_FILE is not set")
+		}
+		return NewFileMailer(path)
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST is not set")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		from := os.Getenv("MAIL_FROM")
+		if from == "" {
+			return nil, fmt.Errorf("MAIL_FROM is not set")
+		}
+		return NewSMTPMailer(net.JoinHostPort(host, port), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q", kind)
+	}
+}
+
+// SMTPMailer sends mail through an SMTP server. The connection is upgraded with
+// STARTTLS when the server supports it.
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer creates a mailer for the server at addr ("host:port"). Authentication
+// is skipped when username is empty.
+func NewSMTPMailer(addr, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{addr: addr, from: from}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+// Send delivers msg. The connection is closed when ctx is done, which aborts the
+// delivery.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %v", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to send mail: %v", err)
+		}
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := m.deliver(conn, msg); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to send mail: %v", ctx.Err())
+		}
+		return fmt.Errorf("failed to send mail: %v", err)
+	}
+	return nil
+}
+
+// deliver runs the SMTP conversation of smtp.SendMail on conn.
+func (m *SMTPMailer) deliver(conn net.Conn, msg Message) error {
+	host, _, _ := net.SplitHostPort(m.addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("server does not support AUTH")
+		}
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(formatMessage(m.from, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// WriterMailer writes mail to an io.Writer instead of delivering it. It is meant for
+// development and tests.
+type WriterMailer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterMailer creates a mailer that writes every message to w.
+func NewWriterMailer(w io.Writer) *WriterMailer {
+	return &WriterMailer{w: w}
+}
+
+// NewFileMailer creates a mailer that appends every message to the file at path.
+func NewFileMailer(path string) (*WriterMailer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mail file: %v", err)
+	}
+	return NewWriterMailer(f), nil
+}
+
+func (m *WriterMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.w.Write(append(formatMessage("", msg), '\n')); err != nil {
+		return fmt.Errorf("failed to write mail: %v", err)
+	}
+	return nil
+}
+
+// formatMessage renders msg as an RFC 5322 message.
+func formatMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	if from != "" {
+		fmt.Fprintf(&b, "From: %s\r\n", headerValue(from))
+	}
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// headerValue strips line breaks so that values cannot inject additional headers.
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
+
+// mailTimeout bounds the delivery of a single message.
+const mailTimeout = 30 * time.Second
+
+// sendMail delivers msg in the background, so that response times do not reveal
+// whether a message was sent.
+func (s *AuthServiceServer) sendMail(msg Message) {
+	mailer := currentMailer()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+		defer cancel()
+		if err := mailer.Send(ctx, msg); err != nil {
+			s.Logger.Errorw("Failed to send mail", "subject", msg.Subject, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"db"
+	"encoding/json"
+	"errors"
+	"fmt"
+	. "generated"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PurposeOAuthState marks the state tokens returned by BeginOAuthLogin.
+const PurposeOAuthState = "oauth-state"
+
+// oauthStateTTL is the time a user has to sign in at the provider.
+const oauthStateTTL = 10 * time.Minute
+
+// OAuthProviderConfig configures an OpenID Connect provider.
+type OAuthProviderConfig struct {
+	// Name identifies the provider in BeginOAuthLogin and in stored identities.
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+}
+
+// OAuthProvider is a configured provider. Its discovery document is fetched on first use
+// so that an unreachable provider does not prevent the server from starting.
+type OAuthProvider struct {
+	config OAuthProviderConfig
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewOAuthProvider validates the configuration of a provider.
+func NewOAuthProvider(config OAuthProviderConfig) (*OAuthProvider, error) {
+	if config.Name == "" || config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, fmt.Errorf("OAuth provider %q: name, issuer, client_id and redirect_url are required", config.Name)
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"email", "profile"}
+	}
+	return &OAuthProvider{config: config}, nil
+}
+
+// Name returns the name of the provider.
+func (p *OAuthProvider) Name() string {
+	return p.config.Name
+}
+
+// discover fetches the discovery document of the provider, once it succeeds.
+func (p *OAuthProvider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider == nil {
+		provider, err := oidc.NewProvider(ctx, p.config.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover OAuth provider %q: %v", p.config.Name, err)
+		}
+		p.provider = provider
+	}
+	return p.provider, nil
+}
+
+// oauth2Config returns the OAuth2 client configuration of the provider.
+func (p *OAuthProvider) oauth2Config(ctx context.Context) (*oauth2.Config, *oidc.Provider, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       append([]string{oidc.ScopeOpenID}, p.config.Scopes...),
+	}, provider, nil
+}
+
+// externalIdentity is the account at a provider, as asserted by its ID token.
+type externalIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// exchange redeems an authorization code and verifies the ID token it returns against
+// the expected nonce.
+func (p *OAuthProvider) exchange(ctx context.Context, code, verifier, nonce string) (*externalIdentity, error) {
+	config, provider, err := p.oauth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %v", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("provider did not return an ID token")
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.config.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %v", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("invalid ID token: nonce mismatch")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("invalid ID token claims: %v", err)
+	}
+	return &externalIdentity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+var (
+	oauthProvidersMu sync.RWMutex
+	oauthProviders   = map[string]*OAuthProvider{}
+)
+
+// SetOAuthProviders replaces the providers users can sign in with.
+func SetOAuthProviders(providers ...*OAuthProvider) {
+	byName := make(map[string]*OAuthProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	oauthProvidersMu.Lock()
+	defer oauthProvidersMu.Unlock()
+	oauthProviders = byName
+}
+
+// lookupOAuthProvider returns the provider with the given name.
+func lookupOAuthProvider(name string) (*OAuthProvider, bool) {
+	oauthProvidersMu.RLock()
+	defer oauthProvidersMu.RUnlock()
+	p, ok := oauthProviders[name]
+	return p, ok
+}
+
+// LoadOAuthProvidersFromEnv reads the providers from the JSON file named by
+// OAUTH_PROVIDERS_FILE, a list of OAuthProviderConfig. Environment variables in the
+// file are expanded, so secrets can be kept out of it. No providers are configured when
+// the variable is unset.
+func LoadOAuthProvidersFromEnv() ([]*OAuthProvider, error) {
+	path := os.Getenv("OAUTH_PROVIDERS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth providers: %v", err)
+	}
+	var configs []OAuthProviderConfig
+	if err := json.Unmarshal([]byte(os.ExpandEnv(string(data))), &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse OAuth providers: %v", err)
+	}
+
+	providers := make([]*OAuthProvider, 0, len(configs))
+	seen := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		if seen[config.Name] {
+			return nil, fmt.Errorf("duplicate OAuth provider %q", config.Name)
+		}
+		seen[config.Name] = true
+		p, err := NewOAuthProvider(config)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// BeginOAuthLogin starts a sign-in with an OpenID Connect provider. The client sends the
+// user to the returned URL and keeps the state and PKCE verifier for CompleteOAuthLogin.
+func (s *AuthServiceServer) BeginOAuthLogin(ctx context.Context, in *BeginOAuthLoginRequest) (*BeginOAuthLoginReply, error) {
+	provider, ok := lookupOAuthProvider(in.Provider)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown provider %q", in.Provider)
+	}
+	config, _, err := provider.oauth2Config(ctx)
+	if err != nil {
+		s.Logger.Errorw("OAuth provider unavailable", "provider", in.Provider, "error", err)
+		return nil, status.Errorf(codes.Unavailable, "provider %q is unavailable", in.Provider)
+	}
+
+	// The subject of a state token is the provider; its ID doubles as the nonce the ID
+	// token must carry, and makes the state single-use.
+	claims := NewClaims("",
+		WithSubject(provider.Name()),
+		WithTenant(TenantID(ctx)),
+		WithPurpose(PurposeOAuthState),
+		WithTTL(oauthStateTTL),
+	)
+	state, err := signClaims(claims)
+	if err != nil {
+		s.Logger.Errorw("Failed to sign OAuth state", "provider", in.Provider, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not start sign-in")
+	}
+	verifier := oauth2.GenerateVerifier()
+	return &BeginOAuthLoginReply{
+		AuthorizeUrl: config.AuthCodeURL(state, oidc.Nonce(claims.ID), oauth2.S256ChallengeOption(verifier)),
+		State:        state,
+		CodeVerifier: verifier,
+	}, nil
+}
+
+// CompleteOAuthLogin exchanges the authorization code returned by the provider, links
+// the external identity to a user, creating one on first sign-in, and logs the user in.
+func (s *AuthServiceServer) CompleteOAuthLogin(ctx context.Context, in *CompleteOAuthLoginRequest) (*LoginReply, error) {
+	if in.Code == "" || in.CodeVerifier == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "code and code_verifier are required")
+	}
+	state, err := verifyPurposeJWT(in.State, PurposeOAuthState)
+	if err == nil {
+		err = CheckRevocation(ctx, state)
+	}
+	// The sign-in must complete in the tenant it started in.
+	if err == nil && state.TenantID != TenantID(ctx) {
+		err = ErrTenantMismatch
+	}
+	if err != nil {
+		s.Logger.Warnw("OAuth login failed: invalid state", "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired state")
+	}
+	provider, ok := lookupOAuthProvider(state.Subject)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown provider %q", state.Subject)
+	}
+	// States are single-use.
+	if err := Revocations().RevokeToken(ctx, state.ID, state.ExpiresAt.Time); err != nil {
+		s.Logger.Warnw("Failed to revoke OAuth state", "provider", provider.Name(), "error", err)
+	}
+
+	identity, err := provider.exchange(ctx, in.Code, in.CodeVerifier, state.ID)
+	if err != nil {
+		s.Logger.Warnw("OAuth login failed", "provider", provider.Name(), "error", err)
+		return nil, status.Errorf(codes.Unauthenticated, "could not verify identity with provider")
+	}
+	user, err := s.linkExternalIdentity(ctx, provider.Name(), identity)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refuseDisabled(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if user.TotpEnabled {
+		reply, err := twoFactorChallenge(user)
+		if err != nil {
+			s.Logger.Errorw("Error generating two-factor challenge", "email", user.Email, "error", err)
+			return nil, status.Errorf(codes.Internal, "could not generate token: %v", err)
+		}
+		return reply, nil
+	}
+	reply, _, err := s.issueTokens(ctx, user, "")
+	if err != nil {
+		s.Logger.Errorw("Error generating token", "email", user.Email, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not generate token: %v", err)
+	}
+	s.Logger.Infow("Generated token after OAuth login", "email", user.Email, "provider", provider.Name())
+	auditLoginSuccess(ctx, user)
+	return reply, nil
+}
+
+// linkExternalIdentity returns the user an external identity belongs to in the tenant of
+// the request. Unknown identities are linked to the user with the same email address, but
+// only when both the provider and the user have verified it; otherwise a new user is
+// created.
+func (s *AuthServiceServer) linkExternalIdentity(ctx context.Context, provider string, identity *externalIdentity) (*db.UserModel, error) {
+	scope := Scope(ctx, s.PrismaClient)
+	linked, err := s.PrismaClient.ExternalIdentity.FindUnique(
+		db.ExternalIdentity.TenantIDProviderSubject(
+			db.ExternalIdentity.TenantID.Equals(scope.TenantID()),
+			db.ExternalIdentity.Provider.Equals(provider),
+			db.ExternalIdentity.Subject.Equals(identity.Subject),
+		),
+	).With(
+		db.ExternalIdentity.User.Fetch(),
+	).Exec(ctx)
+	if err == nil {
+		return linked.User(), nil
+	}
+	if !errors.Is(err, db.ErrNotFound) {
+		s.Logger.Errorw("Failed to look up external identity", "provider", provider, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not sign in")
+	}
+	if identity.Email == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "provider did not share an email address")
+	}
+
+	user, err := scope.FindUserByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		// Linking on an unverified address would let whoever controls either side
+		// take over the other.
+		if !identity.EmailVerified || !user.EmailVerified {
+			s.Logger.Warnw("OAuth login refused: email not verified", "provider", provider, "email", identity.Email)
+			return nil, status.Errorf(codes.FailedPrecondition, "an account with this email address already exists")
+		}
+	case errors.Is(err, db.ErrNotFound):
+		if user, err = s.createExternalUser(ctx, scope, identity); err != nil {
+			s.Logger.Errorw("Failed to create user", "provider", provider, "email", identity.Email, "error", err)
+			return nil, status.Errorf(codes.Internal, "could not sign in")
+		}
+	default:
+		s.Logger.Errorw("Failed to look up user", "email", identity.Email, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not sign in")
+	}
+
+	_, err = s.PrismaClient.ExternalIdentity.CreateOne(
+		db.ExternalIdentity.Provider.Set(provider),
+		db.ExternalIdentity.Subject.Set(identity.Subject),
+		db.ExternalIdentity.User.Link(db.User.ID.Equals(user.ID)),
+		db.ExternalIdentity.Email.Set(identity.Email),
+		db.ExternalIdentity.TenantID.Set(scope.TenantID()),
+	).Exec(ctx)
+	if err != nil {
+		s.Logger.Errorw("Failed to link external identity", "provider", provider, "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not sign in")
+	}
+	s.Logger.Infow("External identity linked", "provider", provider, "user", user.ID)
+	return user, nil
+}
+
+// createExternalUser creates the user of an identity seen for the first time. The
+// password is random, so the account can only be used through the provider until the
+// user sets one with a password reset.
+func (s *AuthServiceServer) createExternalUser(ctx context.Context, scope *TenantScope, identity *externalIdentity) (*db.UserModel, error) {
+	password, _, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	name := identity.Name
+	if name == "" {
+		name = identity.Email
+	}
+	return scope.CreateUser(ctx, name, identity.Email, hashedPassword, 0,
+		db.User.EmailVerified.Set(identity.EmailVerified),
+	)
+}
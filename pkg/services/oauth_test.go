@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"db"
+	"encoding/base64"
+	"encoding/json"
+	. "generated"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeOIDCProvider is an in-process OpenID Connect provider. Codes are handed out by
+// authorize, which stands in for the user signing in at the provider.
+type fakeOIDCProvider struct {
+	*httptest.Server
+	t   *testing.T
+	key *SigningKey
+
+	mu    sync.Mutex
+	codes map[string]fakeOIDCGrant
+}
+
+type fakeOIDCGrant struct {
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p := &fakeOIDCProvider{
+		t:     t,
+		key:   &SigningKey{ID: "fake", Method: jwt.SigningMethodRS256, Private: private, Public: &private.PublicKey},
+		codes: map[string]fakeOIDCGrant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                p.URL,
+			"authorization_endpoint":                p.URL + "/authorize",
+			"token_endpoint":                        p.URL + "/token",
+			"jwks_uri":                              p.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		jwk, err := toJWK(p.key)
+		require.NoError(t, err)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []jsonWebKey{jwk}})
+	})
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+// authorize signs the user in and returns the code the provider redirects back with.
+func (p *fakeOIDCProvider) authorize(authorizeURL string, claims jwt.MapClaims) string {
+	p.t.Helper()
+	u, err := url.Parse(authorizeURL)
+	require.NoError(p.t, err)
+	query := u.Query()
+	require.Equal(p.t, "S256", query.Get("code_challenge_method"))
+
+	code, err := newRandomID()
+	require.NoError(p.t, err)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.codes[code] = fakeOIDCGrant{challenge: query.Get("code_challenge"), nonce: query.Get("nonce"), claims: claims}
+	return code
+}
+
+func (p *fakeOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	grant, ok := p.codes[r.FormValue("code")]
+	delete(p.codes, r.FormValue("code"))
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   p.URL,
+		"aud":   "thunder",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	}
+	for k, v := range grant.claims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.key.ID
+	idToken, err := token.SignedString(p.key.Private)
+	require.NoError(p.t, err)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+func useOAuthProvider(t *testing.T, issuer string) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "testsecret")
+	useRevocationStore(t, NewMemoryRevocationStore())
+	provider, err := NewOAuthProvider(OAuthProviderConfig{
+		Name:        "fake",
+		Issuer:      issuer,
+		ClientID:    "thunder",
+		RedirectURL: "https://app.example.com/callback",
+	})
+	require.NoError(t, err)
+	SetOAuthProviders(provider)
+	t.Cleanup(func() { SetOAuthProviders() })
+}
+
+func TestLoadOAuthProvidersFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "providers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{
+		"name": "google",
+		"issuer": "https://accounts.google.com",
+		"client_id": "client",
+		"client_secret": "${GOOGLE_CLIENT_SECRET}",
+		"redirect_url": "https://app.example.com/callback"
+	}]`), 0o600))
+	t.Setenv("OAUTH_PROVIDERS_FILE", path)
+	t.Setenv("GOOGLE_CLIENT_SECRET", "secret")
+
+	providers, err := LoadOAuthProvidersFromEnv()
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	assert.Equal(t, "google", providers[0].Name())
+	assert.Equal(t, "secret", providers[0].config.ClientSecret, "expected environment variables to be expanded")
+	assert.Equal(t, []string{"email", "profile"}, providers[0].config.Scopes)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "incomplete"}]`), 0o600))
+	_, err = LoadOAuthProvidersFromEnv()
+	assert.Error(t, err)
+}
+
+func TestBeginOAuthLogin(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	useOAuthProvider(t, provider.URL)
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+
+	_, err := s.BeginOAuthLogin(context.Background(), &BeginOAuthLoginRequest{Provider: "unknown"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	reply, err := s.BeginOAuthLogin(context.Background(), &BeginOAuthLoginRequest{Provider: "fake"})
+	require.NoError(t, err)
+	u, err := url.Parse(reply.AuthorizeUrl)
+	require.NoError(t, err)
+	assert.Equal(t, provider.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, reply.State, u.Query().Get("state"))
+	assert.NotEmpty(t, u.Query().Get("nonce"))
+	assert.NotEmpty(t, reply.CodeVerifier)
+
+	_, err = VerifyJWT(reply.State)
+	assert.Error(t, err, "expected state tokens to be rejected as access tokens")
+}
+
+func TestCompleteOAuthLogin(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	useOAuthProvider(t, provider.URL)
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	ctx := context.Background()
+
+	// A local account that never verified its address is not linked.
+	mock.ExternalIdentity.Expect(
+		client.ExternalIdentity.FindUnique(
+			db.ExternalIdentity.TenantIDProviderSubject(
+				db.ExternalIdentity.TenantID.Equals(""),
+				db.ExternalIdentity.Provider.Equals("fake"),
+				db.ExternalIdentity.Subject.Equals("subject-1"),
+			),
+		).With(db.ExternalIdentity.User.Fetch()),
+	).Errors(db.ErrNotFound)
+	mock.User.Expect(
+		client.User.FindUnique(Scope(ctx, client).UserByEmail("oidc@example.com")),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "oidc@example.com"}})
+
+	begin, err := s.BeginOAuthLogin(ctx, &BeginOAuthLoginRequest{Provider: "fake"})
+	require.NoError(t, err)
+	code := provider.authorize(begin.AuthorizeUrl, jwt.MapClaims{
+		"sub":            "subject-1",
+		"email":          "oidc@example.com",
+		"email_verified": true,
+	})
+	_, err = s.CompleteOAuthLogin(ctx, &CompleteOAuthLoginRequest{State: begin.State, Code: code, CodeVerifier: begin.CodeVerifier})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = s.CompleteOAuthLogin(ctx, &CompleteOAuthLoginRequest{State: begin.State, Code: code, CodeVerifier: begin.CodeVerifier})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected state to be single-use")
+}
+
+func TestCompleteOAuthLoginRejectsWrongVerifier(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	useOAuthProvider(t, provider.URL)
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	ctx := context.Background()
+
+	begin, err := s.BeginOAuthLogin(ctx, &BeginOAuthLoginRequest{Provider: "fake"})
+	require.NoError(t, err)
+	code := provider.authorize(begin.AuthorizeUrl, jwt.MapClaims{"sub": "subject-1"})
+
+	_, err = s.CompleteOAuthLogin(ctx, &CompleteOAuthLoginRequest{State: begin.State, Code: code, CodeVerifier: "wrong"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = s.CompleteOAuthLogin(ctx, &CompleteOAuthLoginRequest{State: "invalid", Code: code, CodeVerifier: begin.CodeVerifier})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
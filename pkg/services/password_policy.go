@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PasswordPolicy decides which passwords users may choose.
+type PasswordPolicy struct {
+	// MinLength is the least number of characters.
+	MinLength int
+	// MaxLength is the most number of bytes; bcrypt ignores everything after 72.
+	MaxLength int
+	// MinCharacterClasses is how many of lowercase letters, uppercase letters, digits
+	// and other characters a password has to mix.
+	MinCharacterClasses int
+	// Denylist holds lowercased passwords that are refused, such as breached ones.
+	Denylist map[string]struct{}
+}
+
+// DefaultPasswordPolicy returns the policy used when nothing is configured.
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{MinLength: 8, MaxLength: 72, MinCharacterClasses: 1}
+}
+
+var (
+	passwordPolicyMu     sync.RWMutex
+	activePasswordPolicy = DefaultPasswordPolicy()
+)
+
+// SetPasswordPolicy replaces the policy checked when users choose a password.
+func SetPasswordPolicy(policy *PasswordPolicy) {
+	passwordPolicyMu.Lock()
+	defer passwordPolicyMu.Unlock()
+	activePasswordPolicy = policy
+}
+
+// currentPasswordPolicy returns the active password policy.
+func currentPasswordPolicy() *PasswordPolicy {
+	passwordPolicyMu.RLock()
+	defer passwordPolicyMu.RUnlock()
+	return activePasswordPolicy
+}
+
+// LoadPasswordPolicyFromEnv reads the policy from PASSWORD_MIN_LENGTH (default 8),
+// PASSWORD_MAX_LENGTH (default 72), PASSWORD_MIN_CHARACTER_CLASSES (default 1) and
+// PASSWORD_DENYLIST_FILE, a file with one refused password per line.
+func LoadPasswordPolicyFromEnv() (*PasswordPolicy, error) {
+	policy := DefaultPasswordPolicy()
+	for name, field := range map[string]*int{
+		"PASSWORD_MIN_LENGTH":            &policy.MinLength,
+		"PASSWORD_MAX_LENGTH":            &policy.MaxLength,
+		"PASSWORD_MIN_CHARACTER_CLASSES": &policy.MinCharacterClasses,
+	} {
+		if v := os.Getenv(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%s must be a non-negative number", name)
+			}
+			*field = n
+		}
+	}
+	if policy.MinCharacterClasses > 4 {
+		return nil, fmt.Errorf("PASSWORD_MIN_CHARACTER_CLASSES must be at most 4")
+	}
+	if policy.MaxLength < policy.MinLength {
+		return nil, fmt.Errorf("PASSWORD_MAX_LENGTH must not be below PASSWORD_MIN_LENGTH")
+	}
+	if path := os.Getenv("PASSWORD_DENYLIST_FILE"); path != "" {
+		denylist, err := loadPasswordDenylist(path)
+		if err != nil {
+			return nil, err
+		}
+		policy.Denylist = denylist
+	}
+	return policy, nil
+}
+
+// loadPasswordDenylist reads one password per line. Empty lines and lines starting
+// with "#" are skipped.
+func loadPasswordDenylist(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open password denylist: %v", err)
+	}
+	defer f.Close()
+
+	denylist := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denylist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read password denylist: %v", err)
+	}
+	return denylist, nil
+}
+
+// Check returns an InvalidArgument error naming the first rule password breaks.
+func (p *PasswordPolicy) Check(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return status.Errorf(codes.InvalidArgument, "password must be at least %d characters long", p.MinLength)
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return status.Errorf(codes.InvalidArgument, "password must be at most %d bytes long", p.MaxLength)
+	}
+	if characterClasses(password) < p.MinCharacterClasses {
+		return status.Errorf(codes.InvalidArgument,
+			"password must mix at least %d of lowercase letters, uppercase letters, digits and symbols", p.MinCharacterClasses)
+	}
+	if _, denied := p.Denylist[strings.ToLower(password)]; denied {
+		return status.Errorf(codes.InvalidArgument, "password is too common, please choose another one")
+	}
+	return nil
+}
+
+// characterClasses counts the kinds of characters in password.
+func characterClasses(password string) int {
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
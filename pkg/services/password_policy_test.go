@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	. "generated"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	policy := &PasswordPolicy{
+		MinLength:           10,
+		MaxLength:           72,
+		MinCharacterClasses: 3,
+		Denylist:            map[string]struct{}{"password123!": {}},
+	}
+	for password, ok := range map[string]bool{
+		"Sh0rt!":                 false,
+		"alllowercaseletters":    false,
+		"Mixed-Case-Letters":     true,
+		"Password123!":           false, // denylisted regardless of case
+		"ÄÖÜäöü12345":            true,
+		string(make([]byte, 73)): false,
+	} {
+		err := policy.Check(password)
+		if ok {
+			assert.NoError(t, err, password)
+		} else {
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), password)
+		}
+	}
+}
+
+func TestLoadPasswordPolicyFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# breached\n123456\n\nQwerty123\n"), 0o600))
+	t.Setenv("PASSWORD_DENYLIST_FILE", path)
+	t.Setenv("PASSWORD_MIN_LENGTH", "12")
+
+	policy, err := LoadPasswordPolicyFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 12, policy.MinLength)
+	assert.Len(t, policy.Denylist, 2)
+	assert.Contains(t, policy.Denylist, "qwerty123")
+
+	t.Setenv("PASSWORD_MIN_CHARACTER_CLASSES", "5")
+	_, err = LoadPasswordPolicyFromEnv()
+	assert.Error(t, err)
+}
+
+func TestRegisterRefusesWeakPassword(t *testing.T) {
+	previous := currentPasswordPolicy()
+	SetPasswordPolicy(&PasswordPolicy{MinLength: 8, MaxLength: 72, Denylist: map[string]struct{}{"password": {}}})
+	t.Cleanup(func() { SetPasswordPolicy(previous) })
+
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	_, err := s.Register(context.Background(), &RegisterRequest{Email: "weak@test.com", Password: "password"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"audit"
+	"context"
+	"db"
+	"errors"
+	"fmt"
+	. "generated"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPasswordResetTTL is the lifetime of reset tokens when PASSWORD_RESET_TOKEN_TTL is unset.
+const defaultPasswordResetTTL = time.Hour
+
+// passwordResetReply is returned whether or not the email belongs to a user.
+const passwordResetReply = "If the email is registered, a password reset link has been sent."
+
+// PasswordResetTTL returns the reset token lifetime, configurable through the
+// PASSWORD_RESET_TOKEN_TTL environment variable (e.g. "30m").
+func PasswordResetTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TOKEN_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultPasswordResetTTL
+}
+
+// passwordResetMessage builds the reset mail. The token is appended to PASSWORD_RESET_URL
+// when it is set, so that the mail contains a clickable link.
+func passwordResetMessage(email, token string) Message {
+	link := token
+	if base := os.Getenv("PASSWORD_RESET_URL"); base != "" {
+		link = fmt.Sprintf("%s?token=%s", base, token)
+	}
+	return Message{
+		To:      email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use the following link to choose a new password:\n\n%s\n\n"+
+			"It expires in %s. If you did not ask to reset your password, you can ignore this mail.",
+			link, PasswordResetTTL()),
+	}
+}
+
+// RequestPasswordReset mails a single-use reset token to the user. The reply is the
+// same for unknown email addresses, so it cannot be used to discover accounts.
+func (s *AuthServiceServer) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest) (*RequestPasswordResetReply, error) {
+	reply := &RequestPasswordResetReply{Reply: passwordResetReply}
+
+	user, err := Scope(ctx, s.PrismaClient).FindUserByEmail(ctx, in.Email)
+	if err != nil {
+		if !errors.Is(err, db.ErrNotFound) {
+			s.Logger.Errorw("Failed to look up user for password reset", "error", err)
+			return nil, status.Errorf(codes.Internal, "could not request password reset")
+		}
+		s.Logger.Infow("Password reset requested for unknown email")
+		return reply, nil
+	}
+
+	if err := s.sendPasswordReset(ctx, user); err != nil {
+		s.Logger.Errorw("Failed to send password reset", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not request password reset")
+	}
+	s.Logger.Infow("Password reset requested", "user", user.ID)
+	return reply, nil
+}
+
+// sendPasswordReset stores a new reset token for the user and mails it.
+func (s *AuthServiceServer) sendPasswordReset(ctx context.Context, user *db.UserModel) error {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if _, err := s.PrismaClient.PasswordResetToken.CreateOne(
+		db.PasswordResetToken.TokenHash.Set(hash),
+		db.PasswordResetToken.User.Link(db.User.ID.Equals(user.ID)),
+		db.PasswordResetToken.ExpiresAt.Set(time.Now().Add(PasswordResetTTL())),
+	).Exec(ctx); err != nil {
+		return fmt.Errorf("could not store password reset token: %v", err)
+	}
+	s.sendMail(passwordResetMessage(user.Email, raw))
+	return nil
+}
+
+// ResetPassword sets a new password using a token from RequestPasswordReset. The token
+// is consumed, and all sessions of the user are revoked.
+func (s *AuthServiceServer) ResetPassword(ctx context.Context, in *ResetPasswordRequest) (*ResetPasswordReply, error) {
+	if in.Token == "" || in.NewPassword == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token and new password are required")
+	}
+	if err := currentPasswordPolicy().Check(in.NewPassword); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.PrismaClient.PasswordResetToken.FindUnique(
+		db.PasswordResetToken.TokenHash.Equals(hashToken(in.Token)),
+	).With(
+		db.PasswordResetToken.User.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			s.Logger.Warnw("Password reset failed: unknown token")
+			return nil, status.Errorf(codes.InvalidArgument, "invalid or expired reset token")
+		}
+		s.Logger.Errorw("Failed to look up password reset token", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not reset password")
+	}
+	user := stored.User()
+	// Tokens are only accepted within the tenant they were issued in.
+	scope := Scope(ctx, s.PrismaClient)
+	if err := scope.Own(user); err != nil {
+		s.Logger.Warnw("Password reset failed: other tenant", "user", user.ID)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired reset token")
+	}
+	if _, used := stored.UsedAt(); used || time.Now().After(stored.ExpiresAt) {
+		s.Logger.Warnw("Password reset failed: token used or expired", "user", stored.UserID)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired reset token")
+	}
+
+	// Consume the token first so that concurrent requests cannot both use it.
+	claimed, err := s.PrismaClient.PasswordResetToken.FindMany(
+		db.PasswordResetToken.ID.Equals(stored.ID),
+		db.PasswordResetToken.UsedAt.IsNull(),
+	).Update(
+		db.PasswordResetToken.UsedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		s.Logger.Errorw("Failed to consume password reset token", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not reset password")
+	}
+	if claimed.Count == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired reset token")
+	}
+
+	hashedPassword, err := hashPassword(in.NewPassword)
+	if err != nil {
+		s.Logger.Errorw("Failed to hash password", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not reset password")
+	}
+	if _, err := scope.UpdateUser(ctx, user, db.User.Password.Set(hashedPassword)); err != nil {
+		s.Logger.Errorw("Failed to update password", "user", stored.UserID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not reset password")
+	}
+
+	// Other outstanding reset tokens are no longer needed.
+	if _, err := s.PrismaClient.PasswordResetToken.FindMany(
+		db.PasswordResetToken.UserID.Equals(stored.UserID),
+		db.PasswordResetToken.UsedAt.IsNull(),
+	).Update(
+		db.PasswordResetToken.UsedAt.Set(time.Now()),
+	).Exec(ctx); err != nil {
+		s.Logger.Warnw("Failed to invalidate password reset tokens", "user", stored.UserID, "error", err)
+	}
+	if err := s.revokeUserSessions(ctx, stored.UserID); err != nil {
+		s.Logger.Errorw("Failed to revoke sessions after password reset", "user", stored.UserID, "error", err)
+	}
+
+	s.Logger.Infow("Password reset", "user", stored.UserID)
+	audit.Record(ctx, audit.Event{
+		Action:  audit.ActionPasswordReset,
+		Outcome: audit.OutcomeSuccess,
+		ActorID: stored.UserID,
+	})
+	return &ResetPasswordReply{Reply: "Password has been reset"}, nil
+}
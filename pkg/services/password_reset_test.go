@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"db"
+	. "generated"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestPasswordResetUnknownEmail(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.User.Expect(
+		client.User.FindUnique(
+			Scope(context.Background(), client).UserByEmail("nobody@test.com"),
+		),
+	).Errors(db.ErrNotFound)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	reply, err := s.RequestPasswordReset(context.Background(), &RequestPasswordResetRequest{Email: "nobody@test.com"})
+	require.NoError(t, err, "expected unknown emails not to be revealed")
+	assert.Equal(t, passwordResetReply, reply.Reply)
+}
+
+func TestResetPasswordInvalidToken(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	findToken := func(token string) db.PasswordResetTokenMockExpectParam {
+		return client.PasswordResetToken.FindUnique(
+			db.PasswordResetToken.TokenHash.Equals(hashToken(token)),
+		).With(
+			db.PasswordResetToken.User.Fetch(),
+		)
+	}
+	mock.PasswordResetToken.Expect(findToken("unknown")).Errors(db.ErrNotFound)
+	mock.PasswordResetToken.Expect(findToken("expired")).Returns(db.PasswordResetTokenModel{
+		InnerPasswordResetToken: db.InnerPasswordResetToken{
+			ID:        "reset-1",
+			UserID:    "user-1",
+			ExpiresAt: time.Now().Add(-time.Minute),
+		},
+		RelationsPasswordResetToken: db.RelationsPasswordResetToken{
+			User: &db.UserModel{InnerUser: db.InnerUser{ID: "user-1"}},
+		},
+	})
+	// Tokens of another tenant are refused before they are consumed.
+	mock.PasswordResetToken.Expect(findToken("other-tenant")).Returns(db.PasswordResetTokenModel{
+		InnerPasswordResetToken: db.InnerPasswordResetToken{
+			ID:        "reset-2",
+			UserID:    "user-2",
+			ExpiresAt: time.Now().Add(time.Hour),
+		},
+		RelationsPasswordResetToken: db.RelationsPasswordResetToken{
+			User: &db.UserModel{InnerUser: db.InnerUser{ID: "user-2", TenantID: "acme"}},
+		},
+	})
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	for _, token := range []string{"unknown", "expired", "other-tenant"} {
+		_, err := s.ResetPassword(context.Background(), &ResetPasswordRequest{Token: token, NewPassword: "new-password"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected %s token to be rejected", token)
+	}
+
+	_, err := s.ResetPassword(context.Background(), &ResetPasswordRequest{Token: "unknown"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected missing password to be rejected")
+}
+
+func TestWriterMailer(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewWriterMailer(&buf)
+
+	err := m.Send(context.Background(), Message{
+		To:      "user@test.com\r\nBcc: attacker@test.com",
+		Subject: "Reset your password",
+		Body:    "line one\nline two",
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "To: user@test.comBcc: attacker@test.com\r\n", "expected line breaks to be stripped from headers")
+	assert.NotContains(t, out, "\r\nBcc:")
+	assert.Contains(t, out, "Subject: Reset your password\r\n")
+	assert.Contains(t, out, "line one\r\nline two")
+}
+
+func TestLoadMailerFromEnv(t *testing.T) {
+	t.Setenv("MAILER", "")
+	_, err := LoadMailerFromEnv()
+	assert.Error(t, err, "expected MAILER to be required")
+
+	t.Setenv("MAILER", "stdout")
+	_, err = LoadMailerFromEnv()
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, unconfiguredMailer{}.Send(context.Background(), Message{}), ErrMailerNotConfigured)
+}
+
+func TestSMTPMailerHonorsContext(t *testing.T) {
+	// The server accepts connections but never greets.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	m := NewSMTPMailer(lis.Addr().String(), "", "", "noreply@test.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Error(t, m.Send(ctx, Message{To: "user@test.com"}))
+	assert.Less(t, time.Since(start), 5*time.Second, "expected the deadline to end the delivery")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start = time.Now()
+	assert.Error(t, m.Send(ctx, Message{To: "user@test.com"}))
+	assert.Less(t, time.Since(start), 5*time.Second, "expected cancellation to end the delivery")
+}
+
+func TestPasswordResetMessage(t *testing.T) {
+	t.Setenv("PASSWORD_RESET_URL", "https://app.test/reset")
+	msg := passwordResetMessage("user@test.com", "tok")
+	assert.Equal(t, "user@test.com", msg.To)
+	assert.Contains(t, msg.Body, "https://app.test/reset?token=tok")
+}
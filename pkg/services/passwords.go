@@ -0,0 +1,242 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// errPasswordMismatch is returned when a password does not match its hash.
+var errPasswordMismatch = errors.New("password does not match")
+
+// PasswordHasher hashes passwords into a self-describing encoded form, such as
+// "$2a$12$..." for bcrypt or "$argon2id$v=19$..." for argon2id.
+type PasswordHasher interface {
+	// Hash returns the encoded hash of password.
+	Hash(password string) (string, error)
+	// Verify checks password against an encoded hash of this hasher.
+	Verify(encoded, password string) error
+	// Recognizes reports whether encoded was produced by this kind of hasher.
+	Recognizes(encoded string) bool
+	// NeedsRehash reports whether encoded should be replaced by a new hash, because it
+	// was made by another hasher or with other parameters.
+	NeedsRehash(encoded string) bool
+}
+
+// defaultBcryptCost is the bcrypt cost used when BCRYPT_COST is unset.
+const defaultBcryptCost = 12
+
+var (
+	passwordHasherMu sync.RWMutex
+	passwordHasher   PasswordHasher = &BcryptHasher{Cost: defaultBcryptCost}
+)
+
+// SetPasswordHasher replaces the hasher used for new passwords. Existing hashes of
+// other hashers still verify, and are replaced on the next login. It defaults to bcrypt.
+func SetPasswordHasher(h PasswordHasher) {
+	passwordHasherMu.Lock()
+	defer passwordHasherMu.Unlock()
+	passwordHasher = h
+}
+
+// currentPasswordHasher returns the active password hasher.
+func currentPasswordHasher() PasswordHasher {
+	passwordHasherMu.RLock()
+	defer passwordHasherMu.RUnlock()
+	return passwordHasher
+}
+
+// LoadPasswordHasherFromEnv builds the hasher selected by PASSWORD_HASHER: "bcrypt", the
+// default, with BCRYPT_COST, or "argon2id" with ARGON2_MEMORY (KiB), ARGON2_TIME and
+// ARGON2_THREADS.
+func LoadPasswordHasherFromEnv() (PasswordHasher, error) {
+	switch kind := os.Getenv("PASSWORD_HASHER"); kind {
+	case "", "bcrypt":
+		h := &BcryptHasher{Cost: defaultBcryptCost}
+		if v := os.Getenv("BCRYPT_COST"); v != "" {
+			cost, err := strconv.Atoi(v)
+			if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+				return nil, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+			}
+			h.Cost = cost
+		}
+		return h, nil
+	case "argon2id":
+		h := NewArgon2idHasher()
+		for name, field := range map[string]*uint32{
+			"ARGON2_MEMORY": &h.Memory,
+			"ARGON2_TIME":   &h.Time,
+		} {
+			if v := os.Getenv(name); v != "" {
+				n, err := strconv.ParseUint(v, 10, 32)
+				if err != nil || n == 0 {
+					return nil, fmt.Errorf("%s must be a positive number", name)
+				}
+				*field = uint32(n)
+			}
+		}
+		if v := os.Getenv("ARGON2_THREADS"); v != "" {
+			n, err := strconv.ParseUint(v, 10, 8)
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("ARGON2_THREADS must be between 1 and 255")
+			}
+			h.Threads = uint8(n)
+		}
+		return h, nil
+	default:
+		return nil, fmt.Errorf("unknown PASSWORD_HASHER %q", kind)
+	}
+}
+
+// knownPasswordHashers verify hashes stored under an earlier configuration.
+var knownPasswordHashers = []PasswordHasher{&BcryptHasher{}, &Argon2idHasher{}}
+
+// hashPassword hashes a password with the active hasher.
+func hashPassword(password string) (string, error) {
+	return currentPasswordHasher().Hash(password)
+}
+
+// verifyPassword checks password against a hash of any known hasher. needsRehash is set
+// when the password matches, but the hash is not what the active hasher would produce.
+func verifyPassword(encoded, password string) (needsRehash bool, err error) {
+	active := currentPasswordHasher()
+	for _, h := range append([]PasswordHasher{active}, knownPasswordHashers...) {
+		if !h.Recognizes(encoded) {
+			continue
+		}
+		if err := h.Verify(encoded, password); err != nil {
+			return false, err
+		}
+		return active.NeedsRehash(encoded), nil
+	}
+	return false, fmt.Errorf("unknown password hash format")
+}
+
+// BcryptHasher hashes passwords with bcrypt. Only the first 72 bytes of a password are
+// used, and longer ones are refused.
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (h *BcryptHasher) Verify(encoded, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return errPasswordMismatch
+	}
+	return err
+}
+
+func (h *BcryptHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	if !h.Recognizes(encoded) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2idHasher hashes passwords with argon2id, encoded in the PHC string format:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>.
+type Argon2idHasher struct {
+	// Memory is the memory cost in KiB.
+	Memory uint32
+	// Time is the number of passes over the memory.
+	Time uint32
+	// Threads is the degree of parallelism.
+	Threads uint8
+	// SaltLength and KeyLength are in bytes.
+	SaltLength uint32
+	KeyLength  uint32
+}
+
+// NewArgon2idHasher returns a hasher with the second recommended option of RFC 9106:
+// 64 MiB of memory, 3 passes and 4 threads.
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{Memory: 64 * 1024, Time: 3, Threads: 4, SaltLength: 16, KeyLength: 32}
+}
+
+// argon2idParams are the parameters of an encoded argon2id hash.
+type argon2idParams struct {
+	memory, time uint32
+	threads      uint8
+	salt, key    []byte
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *Argon2idHasher) Verify(encoded, password string) error {
+	p, err := parseArgon2id(encoded)
+	if err != nil {
+		return err
+	}
+	key := argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, uint32(len(p.key)))
+	if subtle.ConstantTimeCompare(key, p.key) != 1 {
+		return errPasswordMismatch
+	}
+	return nil
+}
+
+func (h *Argon2idHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	p, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return p.memory != h.Memory || p.time != h.Time || p.threads != h.Threads ||
+		uint32(len(p.salt)) != h.SaltLength || uint32(len(p.key)) != h.KeyLength
+}
+
+// parseArgon2id decodes a hash produced by Argon2idHasher.
+func parseArgon2id(encoded string) (*argon2idParams, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, fmt.Errorf("invalid argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2id version %q", parts[2])
+	}
+	p := &argon2idParams{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return nil, fmt.Errorf("invalid argon2id parameters: %v", err)
+	}
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("invalid argon2id salt: %v", err)
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) == 0 {
+		return nil, fmt.Errorf("invalid argon2id key")
+	}
+	return p, nil
+}
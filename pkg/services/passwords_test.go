@@ -0,0 +1,91 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// usePasswordHasher replaces the password hasher for the duration of the test.
+func usePasswordHasher(t *testing.T, h PasswordHasher) {
+	t.Helper()
+	previous := currentPasswordHasher()
+	SetPasswordHasher(h)
+	t.Cleanup(func() { SetPasswordHasher(previous) })
+}
+
+// testArgon2idHasher keeps tests fast; real deployments use NewArgon2idHasher.
+func testArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{Memory: 64, Time: 1, Threads: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestArgon2idHasher(t *testing.T) {
+	h := testArgon2idHasher()
+	encoded, err := h.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$"))
+	assert.True(t, h.Recognizes(encoded))
+
+	assert.NoError(t, h.Verify(encoded, "correct horse"))
+	assert.ErrorIs(t, h.Verify(encoded, "wrong horse"), errPasswordMismatch)
+	assert.False(t, h.NeedsRehash(encoded))
+
+	stronger := testArgon2idHasher()
+	stronger.Time = 2
+	assert.True(t, stronger.NeedsRehash(encoded), "expected outdated parameters to need a rehash")
+}
+
+func TestVerifyPasswordAcrossHashers(t *testing.T) {
+	usePasswordHasher(t, testArgon2idHasher())
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	needsRehash, err := verifyPassword(string(legacy), "password")
+	require.NoError(t, err, "expected bcrypt hashes to verify after switching to argon2id")
+	assert.True(t, needsRehash)
+
+	_, err = verifyPassword(string(legacy), "wrong")
+	assert.ErrorIs(t, err, errPasswordMismatch)
+
+	current, err := hashPassword("password")
+	require.NoError(t, err)
+	needsRehash, err = verifyPassword(current, "password")
+	require.NoError(t, err)
+	assert.False(t, needsRehash)
+
+	_, err = verifyPassword("plaintext", "plaintext")
+	assert.Error(t, err, "expected unknown formats to be refused")
+}
+
+func TestBcryptHasherNeedsRehash(t *testing.T) {
+	h := &BcryptHasher{Cost: bcrypt.MinCost}
+	encoded, err := h.Hash("password")
+	require.NoError(t, err)
+	assert.False(t, h.NeedsRehash(encoded))
+	assert.True(t, (&BcryptHasher{Cost: bcrypt.MinCost + 1}).NeedsRehash(encoded))
+}
+
+func TestLoadPasswordHasherFromEnv(t *testing.T) {
+	t.Setenv("PASSWORD_HASHER", "argon2id")
+	t.Setenv("ARGON2_MEMORY", "19456")
+	t.Setenv("ARGON2_TIME", "2")
+	t.Setenv("ARGON2_THREADS", "1")
+	h, err := LoadPasswordHasherFromEnv()
+	require.NoError(t, err)
+	require.IsType(t, &Argon2idHasher{}, h)
+	assert.Equal(t, uint32(19456), h.(*Argon2idHasher).Memory)
+	assert.Equal(t, uint32(2), h.(*Argon2idHasher).Time)
+	assert.Equal(t, uint8(1), h.(*Argon2idHasher).Threads)
+
+	t.Setenv("PASSWORD_HASHER", "bcrypt")
+	t.Setenv("BCRYPT_COST", "99")
+	_, err = LoadPasswordHasherFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("PASSWORD_HASHER", "md5")
+	_, err = LoadPasswordHasherFromEnv()
+	assert.Error(t, err)
+}
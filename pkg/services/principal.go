@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthMethod is the way a caller proved its identity.
+type AuthMethod string
+
+const (
+	AuthMethodJWT         AuthMethod = "jwt"
+	AuthMethodAPIKey      AuthMethod = "api_key"
+	AuthMethodCertificate AuthMethod = "certificate"
+)
+
+// Principal is the authenticated caller of a request, stored in the context by the auth
+// interceptors.
+type Principal struct {
+	UserID   string
+	Email    string
+	Roles    []string
+	TenantID string
+	Method   AuthMethod
+	// TokenID is the jti of the access token, the ID of the API key, or the SHA-256
+	// fingerprint of the client certificate.
+	TokenID string
+	// SessionID is the login session of the access token; empty for other methods.
+	SessionID string
+	// ExpiresAt is the expiry of the credential; zero when it does not expire.
+	ExpiresAt time.Time
+	// ImpersonatorID is the admin acting as the user with an impersonation token.
+	ImpersonatorID string
+}
+
+// NewPrincipal returns the principal identified by verified claims.
+func NewPrincipal(claims *Claims) *Principal {
+	p := &Principal{
+		UserID:    claims.Subject,
+		Email:     claims.Email,
+		Roles:     claims.Roles,
+		TenantID:  claims.TenantID,
+		Method:    AuthMethodJWT,
+		TokenID:   claims.ID,
+		SessionID: claims.SessionID,
+	}
+	if claims.ExpiresAt != nil {
+		p.ExpiresAt = claims.ExpiresAt.Time
+	}
+	if claims.Actor != nil {
+		p.ImpersonatorID = claims.Actor.Subject
+	}
+	if claims.APIKeyID != "" {
+		p.Method = AuthMethodAPIKey
+		p.TokenID = claims.APIKeyID
+		p.ExpiresAt = time.Time{}
+	}
+	return p
+}
+
+// HasRole reports whether the principal holds the role.
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the authenticated caller of the request. It reports false on
+// public methods and outside of the auth interceptors.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// MustFromContext returns the authenticated caller and panics when there is none. Use it
+// only in methods that the auth interceptors never let through unauthenticated.
+func MustFromContext(ctx context.Context) *Principal {
+	p, ok := FromContext(ctx)
+	if !ok {
+		panic("services: no principal in context")
+	}
+	return p
+}
+
+// requireInteractivePrincipal refuses callers that did not sign in as the user, before
+// they manage the credentials or the account of the user. API keys are refused because
+// their scopes narrow what they may do, and client certificates because they identify
+// services.
+func requireInteractivePrincipal(ctx context.Context) error {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	if p.Method != AuthMethodJWT {
+		return status.Errorf(codes.PermissionDenied, "sign in as the user to manage the account")
+	}
+	return nil
+}
+
+// CurrentUser returns the email of the authenticated caller. It is kept for existing
+// handlers; FromContext also carries the user ID and roles.
+func CurrentUser(ctx context.Context) (string, error) {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("no authenticated user in context")
+	}
+	return p.Email, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"db"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	. "generated"
+	"os"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultRefreshTokenTTL is the lifetime of refresh tokens when JWT_REFRESH_TOKEN_TTL is unset.
+const defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// RefreshTokenTTL returns the refresh token lifetime, configurable through the
+// JWT_REFRESH_TOKEN_TTL environment variable (e.g. "720h").
+func RefreshTokenTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("JWT_REFRESH_TOKEN_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultRefreshTokenTTL
+}
+
+// newOpaqueToken returns a random URL-safe token together with the hash that gets stored.
+func newOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %v", err)
+	}
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+	return raw, hashToken(raw), nil
+}
+
+// hashToken returns the hex encoded SHA-256 digest of an opaque token.
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRandomID returns a random hex identifier, used for token IDs.
+func newRandomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate id: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// issueTokens creates an access token and a refresh token for the user. An empty
+// familyID starts a new session, whose ID is the family of its refresh tokens; the ID of
+// the stored refresh token is returned so that rotated tokens can be chained.
+func (s *AuthServiceServer) issueTokens(ctx context.Context, user *db.UserModel, familyID string) (*LoginReply, string, error) {
+	if familyID == "" {
+		session, err := s.startSession(ctx, user.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		familyID = session.ID
+	} else {
+		s.touchSession(ctx, familyID)
+	}
+
+	token, err := GenerateJWT(user.Email,
+		WithSubject(user.ID),
+		WithRoles(user.Roles...),
+		WithEmailVerified(user.EmailVerified),
+		WithSession(familyID),
+		WithTenant(user.TenantID),
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not generate token: %v", err)
+	}
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	stored, err := s.PrismaClient.RefreshToken.CreateOne(
+		db.RefreshToken.TokenHash.Set(hash),
+		db.RefreshToken.FamilyID.Set(familyID),
+		db.RefreshToken.User.Link(db.User.ID.Equals(user.ID)),
+		db.RefreshToken.ExpiresAt.Set(time.Now().Add(RefreshTokenTTL())),
+	).Exec(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not store refresh token: %v", err)
+	}
+
+	return &LoginReply{
+		Token:        token,
+		RefreshToken: raw,
+		ExpiresIn:    int64(AccessTokenTTL().Seconds()),
+	}, stored.ID, nil
+}
+
+// revokeRefreshTokenFamily revokes every token that is still active in the family.
+func (s *AuthServiceServer) revokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	_, err := s.PrismaClient.RefreshToken.FindMany(
+		db.RefreshToken.FamilyID.Equals(familyID),
+		db.RefreshToken.RevokedAt.IsNull(),
+	).Update(
+		db.RefreshToken.RevokedAt.Set(time.Now()),
+	).Exec(ctx)
+	return err
+}
+
+// RefreshToken rotates a refresh token: the presented token is consumed and a new
+// access/refresh token pair is returned. Presenting a token that was already used
+// revokes the whole family, since it means the token has leaked.
+func (s *AuthServiceServer) RefreshToken(ctx context.Context, in *RefreshTokenRequest) (*LoginReply, error) {
+	if in.RefreshToken == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "refresh token is required")
+	}
+
+	stored, err := s.PrismaClient.RefreshToken.FindUnique(
+		db.RefreshToken.TokenHash.Equals(hashToken(in.RefreshToken)),
+	).With(
+		db.RefreshToken.User.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			s.Logger.Warnw("Refresh failed: unknown token")
+			return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+		}
+		s.Logger.Errorw("Failed to look up refresh token", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not refresh token")
+	}
+
+	if _, revoked := stored.RevokedAt(); revoked {
+		s.Logger.Warnw("Refresh failed: token revoked", "family", stored.FamilyID)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+	}
+	if _, used := stored.UsedAt(); used {
+		return nil, s.refreshTokenReused(ctx, stored)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		s.Logger.Infow("Refresh failed: token expired", "family", stored.FamilyID)
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token expired")
+	}
+	user := stored.User()
+	if err := s.refuseDisabled(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Claim the token. If a concurrent request consumed it first, treat it as reuse.
+	claimed, err := s.PrismaClient.RefreshToken.FindMany(
+		db.RefreshToken.ID.Equals(stored.ID),
+		db.RefreshToken.UsedAt.IsNull(),
+	).Update(
+		db.RefreshToken.UsedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		s.Logger.Errorw("Failed to consume refresh token", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not refresh token")
+	}
+	if claimed.Count == 0 {
+		return nil, s.refreshTokenReused(ctx, stored)
+	}
+
+	reply, nextID, err := s.issueTokens(ctx, user, stored.FamilyID)
+	if err != nil {
+		s.Logger.Errorw("Error issuing tokens", "email", user.Email, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not refresh token")
+	}
+	if _, err := s.PrismaClient.RefreshToken.FindUnique(
+		db.RefreshToken.ID.Equals(stored.ID),
+	).Update(
+		db.RefreshToken.ReplacedBy.Set(nextID),
+	).Exec(ctx); err != nil {
+		s.Logger.Warnw("Failed to link rotated refresh token", "error", err)
+	}
+
+	s.Logger.Infof("Refreshed token for email %s", user.Email)
+	return reply, nil
+}
+
+// refreshTokenReused ends the session of a token that was presented twice. Whoever holds
+// the other copy may already have access tokens of the session, so those are revoked
+// along with the refresh token family.
+func (s *AuthServiceServer) refreshTokenReused(ctx context.Context, stored *db.RefreshTokenModel) error {
+	s.Logger.Warnw("Refresh token reuse detected, revoking session", "family", stored.FamilyID, "user", stored.UserID)
+	// Families created before sessions existed have no row.
+	if _, err := s.PrismaClient.Session.FindMany(
+		db.Session.ID.Equals(stored.FamilyID),
+		db.Session.RevokedAt.IsNull(),
+	).Update(
+		db.Session.RevokedAt.Set(time.Now()),
+	).Exec(ctx); err != nil {
+		s.Logger.Errorw("Failed to revoke session", "session", stored.FamilyID, "error", err)
+	}
+	if err := s.endSessions(ctx, stored.FamilyID); err != nil {
+		s.Logger.Errorw("Failed to end session", "session", stored.FamilyID, "error", err)
+	}
+	auditTokenRevoke(ctx, stored.UserID, "refresh token reuse")
+	return status.Errorf(codes.Unauthenticated, "refresh token reuse detected")
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"db"
+	. "generated"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewOpaqueToken(t *testing.T) {
+	raw, hash, err := newOpaqueToken()
+	assert.NoError(t, err, "expected no error from newOpaqueToken")
+	assert.NotEmpty(t, raw, "expected raw token to be non-empty")
+	assert.Equal(t, hashToken(raw), hash, "expected hash to match the raw token")
+	assert.NotEqual(t, raw, hash, "expected stored hash to differ from the raw token")
+
+	other, _, err := newOpaqueToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, raw, other, "expected tokens to be unique")
+}
+
+func TestRefreshTokenTTL(t *testing.T) {
+	t.Setenv("JWT_REFRESH_TOKEN_TTL", "")
+	assert.Equal(t, defaultRefreshTokenTTL, RefreshTokenTTL())
+
+	t.Setenv("JWT_REFRESH_TOKEN_TTL", "48h")
+	assert.Equal(t, 48*time.Hour, RefreshTokenTTL())
+
+	t.Setenv("JWT_REFRESH_TOKEN_TTL", "bogus")
+	assert.Equal(t, defaultRefreshTokenTTL, RefreshTokenTTL())
+}
+
+func TestRefreshTokenUnknown(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.RefreshToken.Expect(
+		client.RefreshToken.FindUnique(
+			db.RefreshToken.TokenHash.Equals(hashToken("unknown")),
+		).With(
+			db.RefreshToken.User.Fetch(),
+		),
+	).Errors(db.ErrNotFound)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err := s.RefreshToken(context.Background(), &RefreshTokenRequest{RefreshToken: "unknown"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "expected unknown refresh token to be rejected")
+}
+
+func TestRefreshTokenEmpty(t *testing.T) {
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	_, err := s.RefreshToken(context.Background(), &RefreshTokenRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected empty refresh token to be rejected")
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	ctx := context.Background()
+	useRevocationStore(t, NewMemoryRevocationStore())
+
+	// The database is unreachable: the access tokens of the session must be revoked
+	// regardless.
+	s := &AuthServiceServer{PrismaClient: db.NewClient(), Logger: zap.NewNop().Sugar()}
+	stored := &db.RefreshTokenModel{InnerRefreshToken: db.InnerRefreshToken{ID: "token-1", FamilyID: "session-1", UserID: "user-1"}}
+	err := s.refreshTokenReused(ctx, stored)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "expected reused refresh token to be rejected")
+
+	claims := NewClaims("reuse@test.com", WithSubject("user-1"), WithSession("session-1"))
+	assert.ErrorIs(t, CheckRevocation(ctx, claims), ErrTokenRevoked, "expected access tokens of the session to be revoked")
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"db"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrTokenRevoked is returned by CheckRevocation for tokens that must no longer be accepted.
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// RevocationStore keeps track of access tokens that were invalidated before they expired.
+// Single tokens are revoked by their jti; all tokens of a user are revoked by recording
+// a cutoff time, after which only tokens issued later are accepted.
+type RevocationStore interface {
+	// RevokeToken revokes a single token. expiresAt is the expiry of the token, after
+	// which the revocation no longer needs to be remembered.
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	// IsTokenRevoked reports whether the token was revoked.
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// RevokeUserTokens revokes every token issued to the user at or before the cutoff.
+	RevokeUserTokens(ctx context.Context, userID string, cutoff time.Time) error
+	// UserTokensRevokedAt returns the cutoff of the user, or the zero time if none is set.
+	UserTokensRevokedAt(ctx context.Context, userID string) (time.Time, error)
+}
+
+var (
+	revocationMu    sync.RWMutex
+	revocationStore RevocationStore = NewMemoryRevocationStore()
+)
+
+// SetRevocationStore replaces the store consulted by CheckRevocation and written by
+// Logout and RevokeAllSessions. It defaults to an in-memory store.
+func SetRevocationStore(store RevocationStore) {
+	revocationMu.Lock()
+	defer revocationMu.Unlock()
+	revocationStore = store
+}
+
+// Revocations returns the active revocation store.
+func Revocations() RevocationStore {
+	revocationMu.RLock()
+	defer revocationMu.RUnlock()
+	return revocationStore
+}
+
+// CheckRevocation returns ErrTokenRevoked if the token was revoked, either by its jti or
+// through a cutoff set for its subject. Other errors mean the store could not be queried.
+func CheckRevocation(ctx context.Context, claims *Claims) error {
+	store := Revocations()
+	if claims.ID != "" {
+		revoked, err := store.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check token revocation: %v", err)
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+	if claims.SessionID != "" {
+		revoked, err := store.IsTokenRevoked(ctx, sessionRevocationKey(claims.SessionID))
+		if err != nil {
+			return fmt.Errorf("failed to check token revocation: %v", err)
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+	if claims.Subject != "" && claims.IssuedAt != nil {
+		cutoff, err := store.UserTokensRevokedAt(ctx, claims.Subject)
+		if err != nil {
+			return fmt.Errorf("failed to check token revocation: %v", err)
+		}
+		// Cutoffs have millisecond precision, so tokens issued within the microsecond of
+		// the cutoff are revoked as well.
+		if !cutoff.IsZero() && !claims.IssuedAt.Time.After(cutoff) {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
+// revocationCutoff returns the current time in milliseconds, the precision of the
+// database, as the cutoff for RevokeUserTokens. The database rounds finer times, which
+// could move the cutoff past tokens issued after it.
+func revocationCutoff() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+// LoadRevocationStoreFromEnv builds the revocation store selected by JWT_REVOCATION_STORE
+// ("database", the default, or "memory"). Lookups are cached for JWT_REVOCATION_CACHE_TTL
+// (default 5s), which bounds how long other instances keep accepting a revoked token.
+func LoadRevocationStoreFromEnv(client *db.PrismaClient) (RevocationStore, error) {
+	var store RevocationStore
+	switch kind := os.Getenv("JWT_REVOCATION_STORE"); kind {
+	case "", "database":
+		store = NewPrismaRevocationStore(client)
+	case "memory":
+		store = NewMemoryRevocationStore()
+	default:
+		return nil, fmt.Errorf("unknown JWT_REVOCATION_STORE %q", kind)
+	}
+
+	ttl := defaultRevocationCacheTTL
+	if value := os.Getenv("JWT_REVOCATION_CACHE_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_REVOCATION_CACHE_TTL: %v", err)
+		}
+		ttl = parsed
+	}
+	if ttl <= 0 {
+		return store, nil
+	}
+	return NewCachedRevocationStore(store, ttl), nil
+}
+
+// MemoryRevocationStore keeps revocations in process memory. It is suitable for a single
+// instance; revocations are lost on restart.
+type MemoryRevocationStore struct {
+	mu      sync.Mutex
+	tokens  map[string]time.Time // jti -> token expiry
+	cutoffs map[string]time.Time // user ID -> cutoff
+}
+
+// NewMemoryRevocationStore creates an empty in-memory store.
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens:  make(map[string]time.Time),
+		cutoffs: make(map[string]time.Time),
+	}
+}
+
+func (m *MemoryRevocationStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for id, exp := range m.tokens {
+		if now.After(exp) {
+			delete(m.tokens, id)
+		}
+	}
+	m.tokens[jti] = expiresAt
+	return nil
+}
+
+func (m *MemoryRevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, revoked := m.tokens[jti]
+	return revoked, nil
+}
+
+func (m *MemoryRevocationStore) RevokeUserTokens(ctx context.Context, userID string, cutoff time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cutoffs[userID] = cutoff
+	return nil
+}
+
+func (m *MemoryRevocationStore) UserTokensRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cutoffs[userID], nil
+}
+
+// PrismaRevocationStore persists revocations in the database so that they are shared
+// between instances: revoked jtis in RevokedToken, user cutoffs in User.tokensRevokedAt.
+type PrismaRevocationStore struct {
+	client *db.PrismaClient
+}
+
+// NewPrismaRevocationStore creates a store backed by the given Prisma client.
+func NewPrismaRevocationStore(client *db.PrismaClient) *PrismaRevocationStore {
+	return &PrismaRevocationStore{client: client}
+}
+
+func (p *PrismaRevocationStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := p.client.RevokedToken.CreateOne(
+		db.RevokedToken.Jti.Set(jti),
+		db.RevokedToken.ExpiresAt.Set(expiresAt),
+	).Exec(ctx)
+	if _, exists := db.IsErrUniqueConstraint(err); err != nil && !exists {
+		return err
+	}
+	// Revocations of expired tokens are no longer needed.
+	_, err = p.client.RevokedToken.FindMany(
+		db.RevokedToken.ExpiresAt.Lt(time.Now()),
+	).Delete().Exec(ctx)
+	return err
+}
+
+func (p *PrismaRevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	_, err := p.client.RevokedToken.FindUnique(
+		db.RevokedToken.Jti.Equals(jti),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *PrismaRevocationStore) RevokeUserTokens(ctx context.Context, userID string, cutoff time.Time) error {
+	_, err := p.client.User.FindUnique(
+		db.User.ID.Equals(userID),
+	).Update(
+		db.User.TokensRevokedAt.Set(cutoff),
+	).Exec(ctx)
+	return err
+}
+
+func (p *PrismaRevocationStore) UserTokensRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	user, err := p.client.User.FindUnique(
+		db.User.ID.Equals(userID),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	cutoff, _ := user.TokensRevokedAt()
+	return cutoff, nil
+}
+
+// defaultRevocationCacheTTL is how long lookups are cached when JWT_REVOCATION_CACHE_TTL is unset.
+const defaultRevocationCacheTTL = 5 * time.Second
+
+// maxRevocationCacheEntries bounds the memory used by CachedRevocationStore.
+const maxRevocationCacheEntries = 100000
+
+type revocationCacheEntry struct {
+	revoked bool
+	cutoff  time.Time
+	expires time.Time
+}
+
+// CachedRevocationStore caches the lookups of another store for a short time, so that
+// the auth interceptors do not query the backing store on every request. Writes go
+// through to the backing store and update the cache immediately.
+type CachedRevocationStore struct {
+	store RevocationStore
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	tokens  map[string]revocationCacheEntry
+	cutoffs map[string]revocationCacheEntry
+}
+
+// NewCachedRevocationStore wraps store with a cache whose entries live for ttl.
+func NewCachedRevocationStore(store RevocationStore, ttl time.Duration) *CachedRevocationStore {
+	return &CachedRevocationStore{
+		store:   store,
+		ttl:     ttl,
+		tokens:  make(map[string]revocationCacheEntry),
+		cutoffs: make(map[string]revocationCacheEntry),
+	}
+}
+
+func (c *CachedRevocationStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if err := c.store.RevokeToken(ctx, jti, expiresAt); err != nil {
+		return err
+	}
+	c.put(c.tokens, jti, revocationCacheEntry{revoked: true})
+	return nil
+}
+
+func (c *CachedRevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if entry, ok := c.get(c.tokens, jti); ok {
+		return entry.revoked, nil
+	}
+	revoked, err := c.store.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+	c.put(c.tokens, jti, revocationCacheEntry{revoked: revoked})
+	return revoked, nil
+}
+
+func (c *CachedRevocationStore) RevokeUserTokens(ctx context.Context, userID string, cutoff time.Time) error {
+	if err := c.store.RevokeUserTokens(ctx, userID, cutoff); err != nil {
+		return err
+	}
+	c.put(c.cutoffs, userID, revocationCacheEntry{cutoff: cutoff})
+	return nil
+}
+
+func (c *CachedRevocationStore) UserTokensRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	if entry, ok := c.get(c.cutoffs, userID); ok {
+		return entry.cutoff, nil
+	}
+	cutoff, err := c.store.UserTokensRevokedAt(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	c.put(c.cutoffs, userID, revocationCacheEntry{cutoff: cutoff})
+	return cutoff, nil
+}
+
+func (c *CachedRevocationStore) get(entries map[string]revocationCacheEntry, key string) (revocationCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return revocationCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *CachedRevocationStore) put(entries map[string]revocationCacheEntry, key string, entry revocationCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(entries) >= maxRevocationCacheEntries {
+		for k, e := range entries {
+			if now.After(e.expires) {
+				delete(entries, k)
+			}
+		}
+		if len(entries) >= maxRevocationCacheEntries {
+			clear(entries)
+		}
+	}
+	entry.expires = now.Add(c.ttl)
+	entries[key] = entry
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"db"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRevocationStore counts the lookups that reach the backing store.
+type countingRevocationStore struct {
+	*MemoryRevocationStore
+	lookups int
+}
+
+func (c *countingRevocationStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	c.lookups++
+	return c.MemoryRevocationStore.IsTokenRevoked(ctx, jti)
+}
+
+func useRevocationStore(t *testing.T, store RevocationStore) {
+	t.Helper()
+	SetRevocationStore(store)
+	t.Cleanup(func() { SetRevocationStore(NewMemoryRevocationStore()) })
+}
+
+func TestClaimsHaveUniqueID(t *testing.T) {
+	first := NewClaims("jti@test.com", WithSubject("user-1"))
+	second := NewClaims("jti@test.com")
+	assert.NotEmpty(t, first.ID, "expected claims to carry a jti")
+	assert.NotEqual(t, first.ID, second.ID, "expected every token to get its own jti")
+	assert.Equal(t, "user-1", first.Subject)
+	assert.NotNil(t, first.IssuedAt)
+}
+
+func TestCheckRevocation(t *testing.T) {
+	ctx := context.Background()
+	useRevocationStore(t, NewMemoryRevocationStore())
+
+	claims := NewClaims("revoke@test.com", WithSubject("user-1"))
+	assert.NoError(t, CheckRevocation(ctx, claims))
+
+	require.NoError(t, Revocations().RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time))
+	assert.ErrorIs(t, CheckRevocation(ctx, claims), ErrTokenRevoked, "expected revoked jti to be rejected")
+
+	other := NewClaims("revoke@test.com", WithSubject("user-1"))
+	require.NoError(t, Revocations().RevokeUserTokens(ctx, "user-1", time.Now()))
+	assert.ErrorIs(t, CheckRevocation(ctx, other), ErrTokenRevoked, "expected tokens issued before the cutoff to be rejected")
+
+	later := NewClaims("revoke@test.com", WithSubject("user-1"))
+	later.IssuedAt = jwt.NewNumericDate(time.Now().Add(2 * time.Second))
+	assert.NoError(t, CheckRevocation(ctx, later), "expected tokens issued after the cutoff to be accepted")
+}
+
+func TestMemoryRevocationStorePurgesExpired(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRevocationStore()
+	require.NoError(t, store.RevokeToken(ctx, "expired", time.Now().Add(-time.Minute)))
+	require.NoError(t, store.RevokeToken(ctx, "active", time.Now().Add(time.Minute)))
+
+	revoked, err := store.IsTokenRevoked(ctx, "expired")
+	require.NoError(t, err)
+	assert.False(t, revoked, "expected revocations of expired tokens to be dropped")
+	revoked, err = store.IsTokenRevoked(ctx, "active")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestCachedRevocationStore(t *testing.T) {
+	ctx := context.Background()
+	backing := &countingRevocationStore{MemoryRevocationStore: NewMemoryRevocationStore()}
+	cached := NewCachedRevocationStore(backing, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		revoked, err := cached.IsTokenRevoked(ctx, "jti")
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	}
+	assert.Equal(t, 1, backing.lookups, "expected repeated lookups to be served from the cache")
+
+	require.NoError(t, cached.RevokeToken(ctx, "jti", time.Now().Add(time.Minute)))
+	revoked, err := cached.IsTokenRevoked(ctx, "jti")
+	require.NoError(t, err)
+	assert.True(t, revoked, "expected revocations to update the cache immediately")
+	assert.Equal(t, 1, backing.lookups)
+}
+
+func TestPrismaRevocationStore(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.RevokedToken.Expect(
+		client.RevokedToken.FindUnique(
+			db.RevokedToken.Jti.Equals("unknown"),
+		),
+	).Errors(db.ErrNotFound)
+	mock.RevokedToken.Expect(
+		client.RevokedToken.FindUnique(
+			db.RevokedToken.Jti.Equals("revoked"),
+		),
+	).Returns(db.RevokedTokenModel{
+		InnerRevokedToken: db.InnerRevokedToken{Jti: "revoked", ExpiresAt: time.Now().Add(time.Minute)},
+	})
+
+	store := NewPrismaRevocationStore(client)
+	revoked, err := store.IsTokenRevoked(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = store.IsTokenRevoked(context.Background(), "revoked")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestCheckRevocationWithinSecondOfCutoff(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	ctx := context.Background()
+	useRevocationStore(t, NewMemoryRevocationStore())
+
+	require.NoError(t, Revocations().RevokeUserTokens(ctx, "user-1", revocationCutoff()))
+	time.Sleep(2 * time.Millisecond)
+	token, err := GenerateJWT("revoke@test.com", WithSubject("user-1"))
+	require.NoError(t, err)
+	claims, err := VerifyJWT(token)
+	require.NoError(t, err)
+	assert.NoError(t, CheckRevocation(ctx, claims), "expected tokens issued after the cutoff within its second to be accepted")
+}
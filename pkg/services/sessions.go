@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"db"
+	"fmt"
+	. "generated"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// sessionRevocationKey is the key under which a revoked session is recorded in the
+// revocation store, next to the jti of revoked tokens.
+func sessionRevocationKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// userAgent returns the user agent of the client, as forwarded by the gateway or sent
+// by a gRPC client.
+func userAgent(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{"grpcgateway-user-agent", "user-agent"} {
+		if v := md.Get(key); len(v) > 0 && v[0] != "" {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// startSession records a new login of the user from the calling client.
+func (s *AuthServiceServer) startSession(ctx context.Context, userID string) (*db.SessionModel, error) {
+	session, err := s.PrismaClient.Session.CreateOne(
+		db.Session.User.Link(db.User.ID.Equals(userID)),
+		db.Session.ExpiresAt.Set(time.Now().Add(RefreshTokenTTL())),
+		db.Session.UserAgent.Set(userAgent(ctx)),
+		db.Session.IP.Set(ClientIP(ctx)),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create session: %v", err)
+	}
+	return session, nil
+}
+
+// touchSession records that the session was refreshed. Families created before sessions
+// existed have no row, so failures are only logged.
+func (s *AuthServiceServer) touchSession(ctx context.Context, sessionID string) {
+	now := time.Now()
+	if _, err := s.PrismaClient.Session.FindUnique(
+		db.Session.ID.Equals(sessionID),
+	).Update(
+		db.Session.LastSeenAt.Set(now),
+		db.Session.ExpiresAt.Set(now.Add(RefreshTokenTTL())),
+		db.Session.UserAgent.Set(userAgent(ctx)),
+		db.Session.IP.Set(ClientIP(ctx)),
+	).Exec(ctx); err != nil {
+		s.Logger.Debugw("Failed to update session", "session", sessionID, "error", err)
+	}
+}
+
+// endSessions revokes the refresh tokens and access tokens of sessions that were marked
+// as revoked.
+func (s *AuthServiceServer) endSessions(ctx context.Context, sessionIDs ...string) error {
+	// Access tokens of the session cannot outlive this.
+	expiresAt := time.Now().Add(AccessTokenTTL())
+	for _, id := range sessionIDs {
+		// Access tokens first, so that they are revoked even when the database fails.
+		if err := Revocations().RevokeToken(ctx, sessionRevocationKey(id), expiresAt); err != nil {
+			return fmt.Errorf("could not revoke access tokens: %v", err)
+		}
+		if err := s.revokeRefreshTokenFamily(ctx, id); err != nil {
+			return fmt.Errorf("could not revoke refresh tokens: %v", err)
+		}
+	}
+	return nil
+}
+
+// revokeSession revokes a session of the user. It reports false when the user has no
+// such active session.
+func (s *AuthServiceServer) revokeSession(ctx context.Context, userID, sessionID string) (bool, error) {
+	revoked, err := s.PrismaClient.Session.FindMany(
+		db.Session.ID.Equals(sessionID),
+		db.Session.UserID.Equals(userID),
+		db.Session.RevokedAt.IsNull(),
+	).Update(
+		db.Session.RevokedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("could not revoke session: %v", err)
+	}
+	if revoked.Count == 0 {
+		return false, nil
+	}
+	return true, s.endSessions(ctx, sessionID)
+}
+
+// activeSessions returns the sessions of the user that can still be refreshed, most
+// recently used first.
+func (s *AuthServiceServer) activeSessions(ctx context.Context, userID string) ([]db.SessionModel, error) {
+	return s.PrismaClient.Session.FindMany(
+		db.Session.UserID.Equals(userID),
+		db.Session.RevokedAt.IsNull(),
+		db.Session.ExpiresAt.After(time.Now()),
+	).OrderBy(
+		db.Session.LastSeenAt.Order(db.SortOrderDesc),
+	).Exec(ctx)
+}
+
+// revokeOtherSessions revokes the active sessions of the user except the given one and
+// returns how many were revoked.
+func (s *AuthServiceServer) revokeOtherSessions(ctx context.Context, userID, keepID string) (int32, error) {
+	rows, err := s.activeSessions(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("could not list sessions: %v", err)
+	}
+	var count int32
+	for _, row := range rows {
+		if row.ID == keepID {
+			continue
+		}
+		revoked, err := s.revokeSession(ctx, userID, row.ID)
+		if err != nil {
+			return count, err
+		}
+		if revoked {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListSessions returns the active sessions of the current user.
+func (s *AuthServiceServer) ListSessions(ctx context.Context, in *ListSessionsRequest) (*ListSessionsReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := MustFromContext(ctx).SessionID
+	rows, err := s.activeSessions(ctx, user.ID)
+	if err != nil {
+		s.Logger.Errorw("Failed to list sessions", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not list sessions")
+	}
+
+	reply := &ListSessionsReply{Sessions: make([]*Session, 0, len(rows))}
+	for _, row := range rows {
+		reply.Sessions = append(reply.Sessions, &Session{
+			Id:         row.ID,
+			UserAgent:  row.UserAgent,
+			Ip:         row.IP,
+			CreatedAt:  row.CreatedAt.Format(time.RFC3339),
+			LastSeenAt: row.LastSeenAt.Format(time.RFC3339),
+			Current:    row.ID == current,
+		})
+	}
+	return reply, nil
+}
+
+// RevokeSession logs the current user out of one of their sessions.
+func (s *AuthServiceServer) RevokeSession(ctx context.Context, in *RevokeSessionRequest) (*RevokeSessionReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := s.revokeSession(ctx, user.ID, in.Id)
+	if err != nil {
+		s.Logger.Errorw("Failed to revoke session", "user", user.ID, "session", in.Id, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not revoke session")
+	}
+	if !revoked {
+		return nil, status.Errorf(codes.NotFound, "session not found")
+	}
+
+	s.Logger.Infow("Session revoked", "user", user.ID, "session", in.Id)
+	auditTokenRevoke(ctx, "", "session "+in.Id)
+	return &RevokeSessionReply{Reply: "Session revoked"}, nil
+}
+
+// RevokeOtherSessions logs the current user out everywhere except the session of the
+// token used for the call.
+func (s *AuthServiceServer) RevokeOtherSessions(ctx context.Context, in *RevokeOtherSessionsRequest) (*RevokeOtherSessionsReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := MustFromContext(ctx).SessionID
+	if current == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "the current token does not belong to a session")
+	}
+	count, err := s.revokeOtherSessions(ctx, user.ID, current)
+	if err != nil {
+		s.Logger.Errorw("Failed to revoke sessions", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not revoke sessions")
+	}
+
+	s.Logger.Infow("Other sessions revoked", "user", user.ID, "count", count)
+	auditTokenRevoke(ctx, "", "other sessions")
+	return &RevokeOtherSessionsReply{Revoked: count}, nil
+}
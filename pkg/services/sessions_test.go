@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"db"
+	. "generated"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUserAgent(t *testing.T) {
+	assert.Empty(t, userAgent(context.Background()))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-agent", "grpc-go/1.71.0"))
+	assert.Equal(t, "grpc-go/1.71.0", userAgent(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"user-agent", "grpc-go/1.71.0",
+		"grpcgateway-user-agent", "Mozilla/5.0",
+	))
+	assert.Equal(t, "Mozilla/5.0", userAgent(ctx), "expected the user agent forwarded by the gateway to win")
+}
+
+func TestCheckRevocationSession(t *testing.T) {
+	ctx := context.Background()
+	useRevocationStore(t, NewMemoryRevocationStore())
+
+	claims := NewClaims("session@test.com", WithSubject("user-1"), WithSession("session-1"))
+	other := NewClaims("session@test.com", WithSubject("user-1"), WithSession("session-2"))
+	assert.NoError(t, CheckRevocation(ctx, claims))
+
+	require.NoError(t, Revocations().RevokeToken(ctx, sessionRevocationKey("session-1"), claims.ExpiresAt.Time))
+	assert.ErrorIs(t, CheckRevocation(ctx, claims), ErrTokenRevoked, "expected tokens of a revoked session to be rejected")
+	assert.NoError(t, CheckRevocation(ctx, other), "expected tokens of other sessions to be accepted")
+}
+
+func TestSessionClaimRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	token, err := GenerateJWT("session@test.com", WithSession("session-1"))
+	require.NoError(t, err)
+
+	claims, err := VerifyJWT(token)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+}
+
+func TestRevokeOtherSessionsRequiresSession(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.User.Expect(
+		client.User.FindFirst(Scope(context.Background(), client).UserWhere(db.User.ID.Equals("user-1"))...),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "session@test.com"}})
+
+	ctx := WithPrincipal(context.Background(), NewPrincipal(NewClaims("session@test.com", WithSubject("user-1"))))
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err := s.RevokeOtherSessions(ctx, &RevokeOtherSessionsRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"db"
+	"errors"
+	"net"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// TenantHeader is the metadata key that names the tenant of unauthenticated requests.
+const TenantHeader = "x-tenant-id"
+
+var (
+	// ErrTenantMismatch is returned when a request addresses another tenant than the one
+	// its credentials belong to.
+	ErrTenantMismatch = errors.New("credentials belong to another tenant")
+	// ErrUnscopedQuery is returned when a query across tenants is made on behalf of a
+	// tenant.
+	ErrUnscopedQuery = errors.New("unscoped query in tenant context")
+	// ErrCrossTenant is returned when a record of another tenant is reached from a tenant
+	// context, for example through a token that was issued elsewhere.
+	ErrCrossTenant = errors.New("record belongs to another tenant")
+)
+
+type tenantKey struct{}
+
+// WithTenantID returns a context scoped to a tenant. The auth interceptors store the
+// tenant resolved by ResolveTenant here.
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantID returns the tenant of the request, or "" when it is not scoped to a tenant.
+func TenantID(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
+
+// tenantFromHost returns the subdomain label of host under domain, e.g. "acme" for
+// "acme.thunder.example.com:8080" under "thunder.example.com".
+func tenantFromHost(host, domain string) string {
+	if domain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(domain))
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
+
+// requestedTenant returns the tenant a request addresses: the x-tenant-id header, or
+// else the subdomain of the host under TENANT_DOMAIN.
+func requestedTenant(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(TenantHeader); len(values) > 0 && strings.TrimSpace(values[0]) != "" {
+		return strings.TrimSpace(values[0])
+	}
+	domain := os.Getenv("TENANT_DOMAIN")
+	// The gateway forwards the Host of HTTP requests as x-forwarded-host.
+	for _, key := range []string{"x-forwarded-host", ":authority"} {
+		if values := md.Get(key); len(values) > 0 {
+			if tenantID := tenantFromHost(values[0], domain); tenantID != "" {
+				return tenantID
+			}
+		}
+	}
+	return ""
+}
+
+// ResolveTenant returns the tenant of a request. Authenticated requests belong to the
+// tenant of their credentials, and fail with ErrTenantMismatch when the header or the
+// subdomain names another one; other requests belong to the tenant they address.
+func ResolveTenant(ctx context.Context, p *Principal) (string, error) {
+	requested := requestedTenant(ctx)
+	if p == nil {
+		return requested, nil
+	}
+	if requested != "" && requested != p.TenantID {
+		return "", ErrTenantMismatch
+	}
+	return p.TenantID, nil
+}
+
+// TenantScope runs user queries within the tenant of a request. Handlers query users
+// through it, never through the client, so that every query carries the tenant; queries
+// across tenants go through Unscoped.
+type TenantScope struct {
+	client   *db.PrismaClient
+	tenantID string
+}
+
+// Scope returns the queries of the tenant of ctx. Outside a tenant they reach the users
+// without one.
+func Scope(ctx context.Context, client *db.PrismaClient) *TenantScope {
+	return &TenantScope{client: client, tenantID: TenantID(ctx)}
+}
+
+// TenantID returns the tenant of the scope.
+func (s *TenantScope) TenantID() string {
+	return s.tenantID
+}
+
+// UserWhere returns params restricted to the users of the tenant.
+func (s *TenantScope) UserWhere(params ...db.UserWhereParam) []db.UserWhereParam {
+	return append(params, db.User.TenantID.Equals(s.tenantID))
+}
+
+// UserByEmail returns the unique filter of the user with email in the tenant.
+func (s *TenantScope) UserByEmail(email string) db.UserEqualsUniqueWhereParam {
+	return db.User.TenantIDEmail(db.User.TenantID.Equals(s.tenantID), db.User.Email.Equals(email))
+}
+
+// FindUser returns the user with id, or db.ErrNotFound when it is not in the tenant.
+func (s *TenantScope) FindUser(ctx context.Context, id string) (*db.UserModel, error) {
+	return s.client.User.FindFirst(s.UserWhere(db.User.ID.Equals(id))...).Exec(ctx)
+}
+
+// FindUserByEmail returns the user with email, or db.ErrNotFound when it is not in the
+// tenant.
+func (s *TenantScope) FindUserByEmail(ctx context.Context, email string) (*db.UserModel, error) {
+	return s.client.User.FindUnique(s.UserByEmail(email)).Exec(ctx)
+}
+
+// CreateUser creates a user in the tenant. The tenant must exist.
+func (s *TenantScope) CreateUser(ctx context.Context, name, email, password string, age int, optional ...db.UserSetParam) (*db.UserModel, error) {
+	if s.tenantID != "" {
+		if _, err := s.client.Tenant.FindUnique(db.Tenant.ID.Equals(s.tenantID)).Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	optional = append(optional, db.User.TenantID.Set(s.tenantID))
+	return s.client.User.CreateOne(
+		db.User.Name.Set(name),
+		db.User.Password.Set(password),
+		db.User.Email.Set(email),
+		db.User.Age.Set(age),
+		optional...,
+	).Exec(ctx)
+}
+
+// FindUsers returns up to take users of the tenant matching where, in order, starting
+// after the user with ID cursor when it is set.
+func (s *TenantScope) FindUsers(ctx context.Context, where []db.UserWhereParam, order []db.UserOrderByParam, take int, cursor string) ([]db.UserModel, error) {
+	query := s.client.User.FindMany(s.UserWhere(where...)...).OrderBy(order...).Take(take)
+	if cursor != "" {
+		query = query.Cursor(db.User.ID.Cursor(cursor)).Skip(1)
+	}
+	return query.Exec(ctx)
+}
+
+// UpdateUser applies params to user, loaded through the scope or checked with Own, and
+// returns it updated. It fails with ErrCrossTenant for a user of another tenant.
+func (s *TenantScope) UpdateUser(ctx context.Context, user *db.UserModel, params ...db.UserSetParam) (*db.UserModel, error) {
+	if err := s.Own(user); err != nil {
+		return nil, err
+	}
+	return s.client.User.FindUnique(db.User.ID.Equals(user.ID)).Update(params...).Exec(ctx)
+}
+
+// UpdateUsers applies params to the users of the tenant matching where, and returns how
+// many were updated.
+func (s *TenantScope) UpdateUsers(ctx context.Context, where []db.UserWhereParam, params ...db.UserSetParam) (*db.BatchResult, error) {
+	return s.client.User.FindMany(s.UserWhere(where...)...).Update(params...).Exec(ctx)
+}
+
+// DeleteUser deletes user, loaded through the scope or checked with Own. It fails with
+// ErrCrossTenant for a user of another tenant.
+func (s *TenantScope) DeleteUser(ctx context.Context, user *db.UserModel) error {
+	if err := s.Own(user); err != nil {
+		return err
+	}
+	_, err := s.client.User.FindUnique(db.User.ID.Equals(user.ID)).Delete().Exec(ctx)
+	return err
+}
+
+// Own returns ErrCrossTenant when user, loaded without the scope, is not in the tenant.
+func (s *TenantScope) Own(user *db.UserModel) error {
+	if user.TenantID != s.tenantID {
+		return ErrCrossTenant
+	}
+	return nil
+}
+
+// Unscoped returns the client for queries across tenants, such as maintenance jobs. It
+// fails with ErrUnscopedQuery on behalf of a tenant, where such a query would leak the
+// records of the others.
+func Unscoped(ctx context.Context, client *db.PrismaClient) (*db.PrismaClient, error) {
+	if TenantID(ctx) != "" {
+		return nil, ErrUnscopedQuery
+	}
+	return client, nil
+}
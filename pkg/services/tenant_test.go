@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"db"
+	. "generated"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenantFromHost(t *testing.T) {
+	assert.Equal(t, "acme", tenantFromHost("acme.thunder.example.com", "thunder.example.com"))
+	assert.Equal(t, "acme", tenantFromHost("ACME.thunder.example.com:8080", "thunder.example.com"))
+	assert.Empty(t, tenantFromHost("thunder.example.com", "thunder.example.com"))
+	assert.Empty(t, tenantFromHost("a.b.thunder.example.com", "thunder.example.com"), "expected nested subdomains to be ignored")
+	assert.Empty(t, tenantFromHost("acme.other.com", "thunder.example.com"))
+	assert.Empty(t, tenantFromHost("acme.thunder.example.com", ""), "expected subdomains to be ignored without TENANT_DOMAIN")
+}
+
+func TestResolveTenant(t *testing.T) {
+	t.Setenv("TENANT_DOMAIN", "thunder.example.com")
+
+	tenantID, err := ResolveTenant(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, tenantID)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forwarded-host", "acme.thunder.example.com"))
+	tenantID, err = ResolveTenant(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenantID)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-forwarded-host", "acme.thunder.example.com",
+		TenantHeader, "globex",
+	))
+	tenantID, err = ResolveTenant(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "globex", tenantID, "expected the header to win over the subdomain")
+
+	principal := NewPrincipal(NewClaims("user@test.com", WithTenant("globex")))
+	tenantID, err = ResolveTenant(ctx, principal)
+	require.NoError(t, err)
+	assert.Equal(t, "globex", tenantID)
+
+	tenantID, err = ResolveTenant(context.Background(), principal)
+	require.NoError(t, err)
+	assert.Equal(t, "globex", tenantID, "expected the tenant of the credentials without header")
+
+	principal = NewPrincipal(NewClaims("user@test.com", WithTenant("acme")))
+	_, err = ResolveTenant(ctx, principal)
+	assert.ErrorIs(t, err, ErrTenantMismatch, "expected credentials of another tenant to be rejected")
+}
+
+func TestTenantClaimRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	token, err := GenerateJWT("tenant@test.com", WithTenant("acme"))
+	require.NoError(t, err)
+
+	claims, err := VerifyJWT(token)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", claims.TenantID)
+	assert.Equal(t, "acme", NewPrincipal(claims).TenantID)
+}
+
+func TestTenantScope(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := WithTenantID(context.Background(), "acme")
+	scope := Scope(ctx, client)
+	mock.User.Expect(
+		client.User.FindUnique(db.User.TenantIDEmail(
+			db.User.TenantID.Equals("acme"),
+			db.User.Email.Equals("tenant@test.com"),
+		)),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "user-1", TenantID: "acme", Email: "tenant@test.com"}})
+
+	user, err := scope.FindUserByEmail(ctx, "tenant@test.com")
+	require.NoError(t, err)
+	assert.NoError(t, scope.Own(user))
+	assert.ErrorIs(t, Scope(context.Background(), client).Own(user), ErrCrossTenant)
+}
+
+func TestTenantScopeWrites(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := WithTenantID(context.Background(), "acme")
+	scope := Scope(ctx, client)
+	mock.User.Expect(
+		client.User.FindUnique(db.User.ID.Equals("user-1")).Update(db.User.Name.Set("Ada")),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "user-1", TenantID: "acme", Name: "Ada"}})
+
+	user := &db.UserModel{InnerUser: db.InnerUser{ID: "user-1", TenantID: "acme"}}
+	updated, err := scope.UpdateUser(ctx, user, db.User.Name.Set("Ada"))
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", updated.Name)
+
+	other := &db.UserModel{InnerUser: db.InnerUser{ID: "user-2", TenantID: "globex"}}
+	_, err = scope.UpdateUser(ctx, other, db.User.Name.Set("Ada"))
+	assert.ErrorIs(t, err, ErrCrossTenant)
+	assert.ErrorIs(t, scope.DeleteUser(ctx, other), ErrCrossTenant)
+}
+
+// Test that handlers query users through the tenant scope, never through the client.
+func TestUserQueriesAreScoped(t *testing.T) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		require.NoError(t, err)
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "User" {
+				return true
+			}
+			if client, ok := sel.X.(*ast.SelectorExpr); ok && client.Sel.Name == "PrismaClient" {
+				t.Errorf("%s: users must be queried through Scope or Unscoped", fset.Position(sel.Pos()))
+			}
+			return true
+		})
+	}
+}
+
+func TestUnscoped(t *testing.T) {
+	client, _, _ := db.NewMock()
+
+	got, err := Unscoped(context.Background(), client)
+	require.NoError(t, err)
+	assert.Same(t, client, got)
+
+	_, err = Unscoped(WithTenantID(context.Background(), "acme"), client)
+	assert.ErrorIs(t, err, ErrUnscopedQuery)
+}
+
+func TestRegisterUnknownTenant(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := WithTenantID(context.Background(), "unknown")
+	mock.User.Expect(
+		client.User.FindUnique(Scope(ctx, client).UserByEmail("tenant@test.com")),
+	).Errors(db.ErrNotFound)
+	mock.Tenant.Expect(
+		client.Tenant.FindUnique(db.Tenant.ID.Equals("unknown")),
+	).Errors(db.ErrNotFound)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err := s.Register(ctx, &RegisterRequest{Email: "tenant@test.com", Password: "password", Name: "Ada"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAccountAttemptKeyIsPerTenant(t *testing.T) {
+	assert.Equal(t, "account:user@test.com", accountAttemptKey("", "User@test.com"))
+	assert.NotEqual(t, accountAttemptKey("acme", "user@test.com"), accountAttemptKey("globex", "user@test.com"))
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TOTP parameters (RFC 6238). They are the defaults understood by every
+// authenticator app, so they are not configurable.
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	// totpSkew is the number of steps accepted before and after the current one.
+	totpSkew = 1
+)
+
+// recoveryCodeCount is the number of recovery codes issued when TOTP is confirmed.
+const recoveryCodeCount = 10
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newTOTPSecret returns a random 160-bit secret, base32 encoded.
+func newTOTPSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %v", err)
+	}
+	return base32NoPadding.EncodeToString(buf), nil
+}
+
+// totpURI returns the otpauth:// URI for the secret, understood by authenticator apps.
+func totpURI(secret, email string) string {
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "Thunder"
+	}
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + email,
+		RawQuery: params.Encode(),
+	}).String()
+}
+
+// totpCode computes the code of a base32 secret for the given time step (RFC 4226).
+func totpCode(secret string, step int64) (string, error) {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", fmt.Errorf("invalid secret: %v", err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod), nil
+}
+
+// verifyTOTP checks a code against the steps around now and returns the matching step.
+func verifyTOTP(secret, code string, now time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := totpCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes returns single-use recovery codes formatted as "xxxxx-xxxxx".
+func newRecoveryCodes() ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %v", err)
+		}
+		raw := strings.ToLower(base32NoPadding.EncodeToString(buf))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode strips the formatting users may add or remove when typing a code.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// totpEncryptionKey fetches the AES-256 key for TOTP secrets, base64 encoded in
+// TOTP_ENCRYPTION_KEY.
+func totpEncryptionKey() ([]byte, error) {
+	encoded := os.Getenv("TOTP_ENCRYPTION_KEY")
+	if encoded == "" {
+		return nil, fmt.Errorf("TOTP_ENCRYPTION_KEY is not set in environment variables")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("TOTP_ENCRYPTION_KEY must be 32 bytes, base64 encoded")
+	}
+	return key, nil
+}
+
+// totpCipher returns the AEAD used to encrypt TOTP secrets at rest.
+func totpCipher() (cipher.AEAD, error) {
+	key, err := totpEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptTOTPSecret encrypts a secret with AES-GCM; the nonce is prepended to the result.
+func encryptTOTPSecret(secret string) (string, error) {
+	aead, err := totpCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptTOTPSecret reverses encryptTOTPSecret.
+func decryptTOTPSecret(encrypted string) (string, error) {
+	aead, err := totpCipher()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed TOTP secret")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt TOTP secret: %v", err)
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"db"
+	"encoding/base32"
+	"encoding/base64"
+	. "generated"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// Test vectors from RFC 6238, appendix B (SHA-1), truncated to six digits.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range vectors {
+		code, err := totpCode(secret, unix/totpPeriod)
+		require.NoError(t, err)
+		assert.Equal(t, want, code, "unexpected code at %d", unix)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := newTOTPSecret()
+	require.NoError(t, err)
+	now := time.Now()
+
+	code, err := totpCode(secret, now.Unix()/totpPeriod-1)
+	require.NoError(t, err)
+	_, ok := verifyTOTP(secret, code, now)
+	assert.True(t, ok, "expected the previous code to be accepted")
+
+	code, err = totpCode(secret, now.Unix()/totpPeriod-3)
+	require.NoError(t, err)
+	_, ok = verifyTOTP(secret, code, now)
+	assert.False(t, ok, "expected old codes to be rejected")
+}
+
+func TestTOTPSecretEncryption(t *testing.T) {
+	t.Setenv("TOTP_ENCRYPTION_KEY", "")
+	_, err := encryptTOTPSecret("secret")
+	assert.Error(t, err, "expected missing key to be reported")
+
+	t.Setenv("TOTP_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	encrypted, err := encryptTOTPSecret("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "JBSWY3DPEHPK3PXP")
+
+	plain, err := decryptTOTPSecret(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plain)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := totpURI("JBSWY3DPEHPK3PXP", "user@test.com")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Thunder:user@test.com?"), uri)
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	recoveryCodes, err := newRecoveryCodes()
+	require.NoError(t, err)
+	assert.Len(t, recoveryCodes, recoveryCodeCount)
+	assert.Len(t, recoveryCodes[0], 11)
+	assert.Equal(t, normalizeRecoveryCode(recoveryCodes[0]), normalizeRecoveryCode(strings.ToUpper(strings.ReplaceAll(recoveryCodes[0], "-", " "))))
+}
+
+func TestChallengeTokenIsNotAnAccessToken(t *testing.T) {
+	token, err := GenerateJWT("2fa@test.com", WithSubject("user-1"), WithPurpose(PurposeTwoFactor), WithTTL(time.Minute))
+	require.NoError(t, err)
+
+	_, err = VerifyJWT(token)
+	assert.Error(t, err, "expected challenge tokens to be rejected as access tokens")
+
+	claims, err := verifyPurposeJWT(token, PurposeTwoFactor)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), claims.ExpiresAt.Time, time.Second)
+
+	access, err := GenerateJWT("2fa@test.com")
+	require.NoError(t, err)
+	_, err = verifyPurposeJWT(access, PurposeTwoFactor)
+	assert.Error(t, err, "expected access tokens to be rejected as challenges")
+}
+
+func TestLoginVerifyInvalidChallenge(t *testing.T) {
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	_, err := s.LoginVerify(context.Background(), &LoginVerifyRequest{ChallengeToken: "invalid", Code: "123456"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestDisableTOTPIsThrottled(t *testing.T) {
+	t.Setenv("LOGIN_MAX_FAILURES", "1")
+	useLoginAttemptStore(t, NewMemoryLoginAttemptStore())
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	mock.User.Expect(
+		client.User.FindFirst(Scope(context.Background(), client).UserWhere(db.User.ID.Equals("user-1"))...),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "totp@test.com", TotpEnabled: true}})
+
+	ctx := WithPrincipal(context.Background(), NewPrincipal(NewClaims("totp@test.com", WithSubject("user-1"))))
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+
+	_, err := s.DisableTOTP(ctx, &DisableTOTPRequest{Code: "123456"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.DisableTOTP(ctx, &DisableTOTPRequest{Code: "123456"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "expected guesses to lock the account")
+	_, err = s.ConfirmTOTP(ctx, &ConfirmTOTPRequest{Code: "123456"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "expected enabled TOTP to be refused first")
+}
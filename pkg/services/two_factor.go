@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"db"
+	"errors"
+	"fmt"
+	. "generated"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PurposeTwoFactor marks the challenge tokens returned by Login for users with TOTP enabled.
+const PurposeTwoFactor = "2fa"
+
+// twoFactorChallengeTTL is the time a user has to enter their code after the password.
+const twoFactorChallengeTTL = 5 * time.Minute
+
+// errInvalidCode is returned when neither a TOTP code nor a recovery code matches.
+var errInvalidCode = errors.New("invalid code")
+
+// twoFactorChallenge returns the reply of a Login that still needs a second factor.
+func twoFactorChallenge(user *db.UserModel) (*LoginReply, error) {
+	token, err := GenerateJWT(user.Email,
+		WithSubject(user.ID),
+		WithTenant(user.TenantID),
+		WithPurpose(PurposeTwoFactor),
+		WithTTL(twoFactorChallengeTTL),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginReply{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresIn:         int64(twoFactorChallengeTTL.Seconds()),
+	}, nil
+}
+
+// currentUserModel loads the user that made the call.
+func (s *AuthServiceServer) currentUserModel(ctx context.Context) (*db.UserModel, error) {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		s.Logger.Warnw("Failed to retrieve current user: not authenticated")
+		return nil, status.Errorf(codes.Unauthenticated, "unauthenticated")
+	}
+	scope := Scope(ctx, s.PrismaClient)
+	var user *db.UserModel
+	var err error
+	if principal.UserID != "" {
+		user, err = scope.FindUser(ctx, principal.UserID)
+	} else {
+		user, err = scope.FindUserByEmail(ctx, principal.Email)
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		s.Logger.Errorw("Failed to look up current user", "email", principal.Email, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not load user")
+	}
+	return user, nil
+}
+
+// checkSecondFactor accepts a TOTP code of the user or one of their unused recovery codes.
+// Both are single-use: a TOTP code cannot be replayed within its validity window.
+func (s *AuthServiceServer) checkSecondFactor(ctx context.Context, user *db.UserModel, code string) error {
+	encrypted, ok := user.TotpSecret()
+	if !ok {
+		return errInvalidCode
+	}
+
+	if len(code) == totpDigits {
+		secret, err := decryptTOTPSecret(encrypted)
+		if err != nil {
+			return err
+		}
+		step, ok := verifyTOTP(secret, code, time.Now())
+		if !ok {
+			return errInvalidCode
+		}
+		claimed, err := Scope(ctx, s.PrismaClient).UpdateUsers(ctx, []db.UserWhereParam{
+			db.User.ID.Equals(user.ID),
+			db.User.Or(
+				db.User.TotpLastStep.IsNull(),
+				db.User.TotpLastStep.Lt(int(step)),
+			),
+		}, db.User.TotpLastStep.Set(int(step)))
+		if err != nil {
+			return err
+		}
+		if claimed.Count == 0 {
+			return errInvalidCode
+		}
+		return nil
+	}
+
+	claimed, err := s.PrismaClient.RecoveryCode.FindMany(
+		db.RecoveryCode.UserID.Equals(user.ID),
+		db.RecoveryCode.CodeHash.Equals(hashToken(normalizeRecoveryCode(code))),
+		db.RecoveryCode.UsedAt.IsNull(),
+	).Update(
+		db.RecoveryCode.UsedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if claimed.Count == 0 {
+		return errInvalidCode
+	}
+	s.Logger.Infow("Recovery code used", "user", user.ID)
+	return nil
+}
+
+// secondFactorStatus maps a checkSecondFactor failure to a gRPC status.
+func (s *AuthServiceServer) secondFactorStatus(user *db.UserModel, err error) error {
+	if errors.Is(err, errInvalidCode) {
+		s.Logger.Warnw("Invalid two-factor code", "user", user.ID)
+		return status.Errorf(codes.Unauthenticated, "invalid two-factor code")
+	}
+	s.Logger.Errorw("Failed to check two-factor code", "user", user.ID, "error", err)
+	return status.Errorf(codes.Internal, "could not check two-factor code")
+}
+
+// verifySecondFactor checks code with checkSecondFactor. Codes are short, so guessing
+// them is throttled like passwords: it is refused while the account or the client IP is
+// locked, and failures count towards their locks.
+func (s *AuthServiceServer) verifySecondFactor(ctx context.Context, user *db.UserModel, code string) error {
+	guard := newLoginGuard(ctx, user.Email)
+	if err := guard.check(ctx); err != nil {
+		s.Logger.Warnw("Two-factor code refused: too many failed attempts", "user", user.ID)
+		return err
+	}
+	if err := s.checkSecondFactor(ctx, user, code); err != nil {
+		if errors.Is(err, errInvalidCode) {
+			s.recordLoginFailure(ctx, guard, user.Email)
+		}
+		return s.secondFactorStatus(user, err)
+	}
+	if err := guard.succeed(ctx, user.Email); err != nil {
+		s.Logger.Warnw("Failed to clear failed logins", "user", user.ID, "error", err)
+	}
+	return nil
+}
+
+// LoginVerify completes a Login of a user with TOTP enabled: the challenge token and a
+// TOTP or recovery code are exchanged for an access/refresh token pair.
+func (s *AuthServiceServer) LoginVerify(ctx context.Context, in *LoginVerifyRequest) (*LoginReply, error) {
+	claims, err := verifyPurposeJWT(in.ChallengeToken, PurposeTwoFactor)
+	if err != nil {
+		s.Logger.Warnw("Login verification failed: invalid challenge", "error", err)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired challenge")
+	}
+	if err := CheckRevocation(ctx, claims); err != nil {
+		s.Logger.Warnw("Login verification failed: challenge already used", "user", claims.Subject)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired challenge")
+	}
+
+	// The challenge is only accepted within the tenant of the login.
+	user, err := Scope(ctx, s.PrismaClient).FindUser(ctx, claims.Subject)
+	if err != nil {
+		s.Logger.Warnw("Login verification failed: user not found", "user", claims.Subject, "error", err)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired challenge")
+	}
+	if err := s.refuseDisabled(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifySecondFactor(ctx, user, in.Code); err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			auditLoginFailure(ctx, user.ID, user.Email, "invalid two-factor code")
+		}
+		return nil, err
+	}
+
+	// Challenges are single-use.
+	if err := Revocations().RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		s.Logger.Warnw("Failed to revoke two-factor challenge", "user", user.ID, "error", err)
+	}
+
+	reply, _, err := s.issueTokens(ctx, user, "")
+	if err != nil {
+		s.Logger.Errorw("Error generating token", "email", user.Email, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not generate token: %v", err)
+	}
+	s.Logger.Infof("Generated token for email %s after two-factor verification", user.Email)
+	auditLoginSuccess(ctx, user)
+	return reply, nil
+}
+
+// EnrollTOTP generates a new TOTP secret for the current user. It only takes effect once
+// a code generated from it is confirmed through ConfirmTOTP.
+func (s *AuthServiceServer) EnrollTOTP(ctx context.Context, in *EnrollTOTPRequest) (*EnrollTOTPReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		s.Logger.Errorw("Failed to generate TOTP secret", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not enroll two-factor authentication")
+	}
+	encrypted, err := encryptTOTPSecret(secret)
+	if err != nil {
+		s.Logger.Errorw("Failed to encrypt TOTP secret", "error", err)
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not configured")
+	}
+	if _, err := Scope(ctx, s.PrismaClient).UpdateUser(ctx, user,
+		db.User.TotpSecret.Set(encrypted),
+		db.User.TotpLastStep.SetOptional(nil),
+	); err != nil {
+		s.Logger.Errorw("Failed to store TOTP secret", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not enroll two-factor authentication")
+	}
+
+	s.Logger.Infow("TOTP enrollment started", "user", user.ID)
+	return &EnrollTOTPReply{
+		Secret:     secret,
+		OtpauthUri: totpURI(secret, user.Email),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the user proves that their
+// authenticator produces valid codes, and returns a fresh set of recovery codes.
+func (s *AuthServiceServer) ConfirmTOTP(ctx context.Context, in *ConfirmTOTPRequest) (*ConfirmTOTPReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	}
+	if _, ok := user.TotpSecret(); !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "call EnrollTOTP first")
+	}
+	if len(in.Code) != totpDigits {
+		return nil, status.Errorf(codes.InvalidArgument, "code must have %d digits", totpDigits)
+	}
+	if err := s.verifySecondFactor(ctx, user, in.Code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		s.Logger.Errorw("Failed to create recovery codes", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not enable two-factor authentication")
+	}
+	if _, err := Scope(ctx, s.PrismaClient).UpdateUser(ctx, user, db.User.TotpEnabled.Set(true)); err != nil {
+		s.Logger.Errorw("Failed to enable TOTP", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not enable two-factor authentication")
+	}
+
+	s.Logger.Infow("TOTP enabled", "user", user.ID)
+	return &ConfirmTOTPReply{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP turns two-factor authentication off. It requires a current TOTP code or a
+// recovery code, so that a stolen access token alone cannot remove the second factor;
+// guesses are throttled like those of LoginVerify.
+func (s *AuthServiceServer) DisableTOTP(ctx context.Context, in *DisableTOTPRequest) (*DisableTOTPReply, error) {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enabled")
+	}
+	if err := s.verifySecondFactor(ctx, user, in.Code); err != nil {
+		return nil, err
+	}
+
+	if _, err := Scope(ctx, s.PrismaClient).UpdateUser(ctx, user,
+		db.User.TotpEnabled.Set(false),
+		db.User.TotpSecret.SetOptional(nil),
+		db.User.TotpLastStep.SetOptional(nil),
+	); err != nil {
+		s.Logger.Errorw("Failed to disable TOTP", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not disable two-factor authentication")
+	}
+	if _, err := s.PrismaClient.RecoveryCode.FindMany(
+		db.RecoveryCode.UserID.Equals(user.ID),
+	).Delete().Exec(ctx); err != nil {
+		s.Logger.Warnw("Failed to delete recovery codes", "user", user.ID, "error", err)
+	}
+
+	s.Logger.Infow("TOTP disabled", "user", user.ID)
+	return &DisableTOTPReply{Reply: "Two-factor authentication disabled"}, nil
+}
+
+// replaceRecoveryCodes deletes the recovery codes of the user and stores new ones.
+func (s *AuthServiceServer) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	recoveryCodes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.PrismaClient.RecoveryCode.FindMany(
+		db.RecoveryCode.UserID.Equals(userID),
+	).Delete().Exec(ctx); err != nil {
+		return nil, fmt.Errorf("could not delete recovery codes: %v", err)
+	}
+	for _, code := range recoveryCodes {
+		if _, err := s.PrismaClient.RecoveryCode.CreateOne(
+			db.RecoveryCode.CodeHash.Set(hashToken(normalizeRecoveryCode(code))),
+			db.RecoveryCode.User.Link(db.User.ID.Equals(userID)),
+		).Exec(ctx); err != nil {
+			return nil, fmt.Errorf("could not store recovery code: %v", err)
+		}
+	}
+	return recoveryCodes, nil
+}
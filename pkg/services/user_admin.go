@@ -0,0 +1,336 @@
+package services
+
+import (
+	"audit"
+	"context"
+	"db"
+	"errors"
+	. "generated"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminRole is the role required by the UserAdmin service.
+const adminRole = "admin"
+
+// UserAdminServiceServer implements the UserAdmin service. It only sees the users of the
+// tenant of the request.
+type UserAdminServiceServer struct {
+	UnimplementedUserAdminServer
+	PrismaClient *db.PrismaClient
+	Logger       *zap.SugaredLogger
+}
+
+// auth returns the Auth service sharing the client, for the session and mail helpers.
+func (s *UserAdminServiceServer) auth() *AuthServiceServer {
+	return &AuthServiceServer{PrismaClient: s.PrismaClient, Logger: s.Logger}
+}
+
+// userDisabled reports whether an admin has disabled the user.
+func userDisabled(user *db.UserModel) bool {
+	_, disabled := user.DisabledAt()
+	return disabled
+}
+
+// refuseDisabled fails the login of a disabled user, and records it.
+func (s *AuthServiceServer) refuseDisabled(ctx context.Context, user *db.UserModel) error {
+	if !userDisabled(user) {
+		return nil
+	}
+	s.Logger.Warnw("Login refused: account disabled", "user", user.ID)
+	auditLoginFailure(ctx, user.ID, user.Email, "account disabled")
+	return status.Errorf(codes.PermissionDenied, "account is disabled")
+}
+
+// findUser loads a user of the tenant of the request.
+func (s *UserAdminServiceServer) findUser(ctx context.Context, id string) (*db.UserModel, error) {
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "id is required")
+	}
+	user, err := Scope(ctx, s.PrismaClient).FindUser(ctx, id)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		s.Logger.Errorw("Failed to look up user", "user", id, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not look up user")
+	}
+	return user, nil
+}
+
+// updateUser applies params to a user returned by findUser.
+func (s *UserAdminServiceServer) updateUser(ctx context.Context, user *db.UserModel, params ...db.UserSetParam) (*db.UserModel, error) {
+	updated, err := Scope(ctx, s.PrismaClient).UpdateUser(ctx, user, params...)
+	if err != nil {
+		s.Logger.Errorw("Failed to update user", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not update user")
+	}
+	return updated, nil
+}
+
+// userOrder returns the sort of ListUsers. The ID breaks ties, so that pages are stable.
+func userOrder(orderBy string, descending bool) ([]db.UserOrderByParam, error) {
+	direction := db.SortOrderAsc
+	if descending {
+		direction = db.SortOrderDesc
+	}
+	var order db.UserOrderByParam
+	switch orderBy {
+	case "", "created_at":
+		order = db.User.CreatedAt.Order(direction)
+	case "email":
+		order = db.User.Email.Order(direction)
+	case "name":
+		order = db.User.Name.Order(direction)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "order_by must be created_at, email or name")
+	}
+	return []db.UserOrderByParam{order, db.User.ID.Order(direction)}, nil
+}
+
+// ListUsers pages through the users of the caller's tenant.
+func (s *UserAdminServiceServer) ListUsers(ctx context.Context, in *ListUsersRequest) (*ListUsersReply, error) {
+	pageSize, err := listPageSize(in.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	order, err := userOrder(in.OrderBy, in.Descending)
+	if err != nil {
+		return nil, err
+	}
+
+	var where []db.UserWhereParam
+	if in.Query != "" {
+		where = append(where, db.User.Or(
+			db.User.And(db.User.Email.Contains(in.Query), db.User.Email.Mode(db.QueryModeInsensitive)),
+			db.User.And(db.User.Name.Contains(in.Query), db.User.Name.Mode(db.QueryModeInsensitive)),
+		))
+	}
+	if in.Role != "" {
+		where = append(where, db.User.Roles.Has(in.Role))
+	}
+	switch in.Status {
+	case "":
+	case "active":
+		where = append(where, db.User.DisabledAt.IsNull())
+	case "disabled":
+		where = append(where, db.User.Not(db.User.DisabledAt.IsNull()))
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "status must be active or disabled")
+	}
+
+	// One more user than asked tells whether there is a next page.
+	rows, err := Scope(ctx, s.PrismaClient).FindUsers(ctx, where, order, pageSize+1, in.PageToken)
+	if err != nil {
+		s.Logger.Errorw("Failed to list users", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not list users")
+	}
+
+	reply := &ListUsersReply{Users: make([]*User, 0, min(len(rows), pageSize))}
+	for i := range rows {
+		if i == pageSize {
+			reply.NextPageToken = rows[i-1].ID
+			break
+		}
+		reply.Users = append(reply.Users, userToProto(&rows[i]))
+	}
+	return reply, nil
+}
+
+// GetUser returns a user of the caller's tenant.
+func (s *UserAdminServiceServer) GetUser(ctx context.Context, in *GetUserRequest) (*User, error) {
+	user, err := s.findUser(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	return userToProto(user), nil
+}
+
+// DisableUser blocks the logins of a user and revokes their sessions. Disabling a
+// disabled user does nothing.
+func (s *UserAdminServiceServer) DisableUser(ctx context.Context, in *DisableUserRequest) (*User, error) {
+	admin := MustFromContext(ctx)
+	user, err := s.findUser(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == admin.UserID {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot disable your own account")
+	}
+	if userDisabled(user) {
+		return userToProto(user), nil
+	}
+
+	user, err = s.updateUser(ctx, user, db.User.DisabledAt.Set(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auth().revokeUserSessions(ctx, user.ID); err != nil {
+		s.Logger.Errorw("Failed to revoke sessions of disabled user", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not revoke sessions")
+	}
+
+	s.Logger.Infow("User disabled", "user", user.ID, "admin", admin.UserID)
+	audit.Record(ctx, audit.Event{
+		Action:   audit.ActionUserDisable,
+		Outcome:  audit.OutcomeSuccess,
+		TargetID: user.ID,
+		Reason:   in.Reason,
+	})
+	return userToProto(user), nil
+}
+
+// EnableUser lets a disabled user log in again.
+func (s *UserAdminServiceServer) EnableUser(ctx context.Context, in *EnableUserRequest) (*User, error) {
+	user, err := s.findUser(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !userDisabled(user) {
+		return userToProto(user), nil
+	}
+
+	user, err = s.updateUser(ctx, user, db.User.DisabledAt.SetOptional(nil))
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Infow("User enabled", "user", user.ID)
+	audit.Record(ctx, audit.Event{
+		Action:   audit.ActionUserEnable,
+		Outcome:  audit.OutcomeSuccess,
+		TargetID: user.ID,
+	})
+	return userToProto(user), nil
+}
+
+// ForcePasswordReset replaces the password of a user with a random one, revokes their
+// sessions and mails them a reset link, so that they have to choose a new password.
+func (s *UserAdminServiceServer) ForcePasswordReset(ctx context.Context, in *ForcePasswordResetRequest) (*ForcePasswordResetReply, error) {
+	user, err := s.findUser(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	random, _, err := newOpaqueToken()
+	if err != nil {
+		s.Logger.Errorw("Failed to generate password", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not reset password")
+	}
+	hashedPassword, err := hashPassword(random)
+	if err != nil {
+		s.Logger.Errorw("Failed to hash password", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not reset password")
+	}
+	if _, err := s.updateUser(ctx, user, db.User.Password.Set(hashedPassword)); err != nil {
+		return nil, err
+	}
+
+	auth := s.auth()
+	if err := auth.revokeUserSessions(ctx, user.ID); err != nil {
+		s.Logger.Errorw("Failed to revoke sessions after forced password reset", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not revoke sessions")
+	}
+	// The user can still ask for a new link through RequestPasswordReset.
+	if err := auth.sendPasswordReset(ctx, user); err != nil {
+		s.Logger.Warnw("Failed to send password reset", "user", user.ID, "error", err)
+	}
+
+	s.Logger.Infow("Password reset forced", "user", user.ID)
+	audit.Record(ctx, audit.Event{
+		Action:   audit.ActionPasswordReset,
+		Outcome:  audit.OutcomeSuccess,
+		TargetID: user.ID,
+		Reason:   "forced by admin",
+	})
+	return &ForcePasswordResetReply{Reply: "Password reset forced"}, nil
+}
+
+// SetUserRoles replaces the roles of a user. Access tokens issued before are revoked so
+// that the change takes effect at the next refresh.
+func (s *UserAdminServiceServer) SetUserRoles(ctx context.Context, in *SetUserRolesRequest) (*User, error) {
+	admin := MustFromContext(ctx)
+	user, err := s.findUser(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == admin.UserID {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot change your own roles")
+	}
+
+	roles := slices.Compact(slices.Sorted(slices.Values(in.Roles)))
+	if roles == nil {
+		roles = []string{}
+	}
+	if slices.Contains(roles, "") {
+		return nil, status.Errorf(codes.InvalidArgument, "roles must not be empty")
+	}
+	user, err = s.updateUser(ctx, user, db.User.Roles.Set(roles))
+	if err != nil {
+		return nil, err
+	}
+	if err := Revocations().RevokeUserTokens(ctx, user.ID, revocationCutoff()); err != nil {
+		s.Logger.Errorw("Failed to revoke access tokens after role change", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not revoke access tokens")
+	}
+
+	s.Logger.Infow("User roles changed", "user", user.ID, "roles", roles)
+	audit.Record(ctx, audit.Event{
+		Action:   audit.ActionRolesChange,
+		Outcome:  audit.OutcomeSuccess,
+		TargetID: user.ID,
+	})
+	return userToProto(user), nil
+}
+
+// ImpersonateUser issues an access token of a user to the admin. The token names the
+// admin in its act claim and has no refresh token. Admins cannot be impersonated, and
+// impersonation tokens cannot impersonate further.
+func (s *UserAdminServiceServer) ImpersonateUser(ctx context.Context, in *ImpersonateUserRequest) (*ImpersonateUserReply, error) {
+	admin := MustFromContext(ctx)
+	if in.Reason == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "reason is required")
+	}
+	if admin.ImpersonatorID != "" {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot impersonate while impersonating")
+	}
+	user, err := s.findUser(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case user.ID == admin.UserID:
+		return nil, status.Errorf(codes.InvalidArgument, "cannot impersonate yourself")
+	case slices.Contains(user.Roles, adminRole):
+		return nil, status.Errorf(codes.PermissionDenied, "cannot impersonate an admin")
+	case userDisabled(user):
+		return nil, status.Errorf(codes.FailedPrecondition, "user is disabled")
+	}
+
+	token, err := GenerateJWT(user.Email,
+		WithSubject(user.ID),
+		WithRoles(user.Roles...),
+		WithEmailVerified(user.EmailVerified),
+		WithTenant(user.TenantID),
+		WithActor(admin.UserID, admin.Email),
+	)
+	if err != nil {
+		s.Logger.Errorw("Error generating impersonation token", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not generate token")
+	}
+
+	s.Logger.Infow("User impersonated", "user", user.ID, "admin", admin.UserID)
+	audit.Record(ctx, audit.Event{
+		Action:   audit.ActionImpersonate,
+		Outcome:  audit.OutcomeSuccess,
+		TargetID: user.ID,
+		Reason:   in.Reason,
+	})
+	return &ImpersonateUserReply{
+		Token:     token,
+		ExpiresIn: int64(AccessTokenTTL().Seconds()),
+	}, nil
+}
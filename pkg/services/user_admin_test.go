@@ -0,0 +1,207 @@
+package services
+
+import (
+	"audit"
+	"context"
+	"db"
+	. "generated"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminContext returns the context of a call made by admin-1 in the acme tenant.
+func adminContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx := WithTenantID(context.Background(), "acme")
+	return WithPrincipal(ctx, NewPrincipal(NewClaims("admin@test.com",
+		WithSubject("admin-1"), WithRoles("admin"), WithTenant("acme"))))
+}
+
+func TestListUsers(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := adminContext(t)
+	rows := []db.UserModel{
+		{InnerUser: db.InnerUser{ID: "user-3", Email: "zoe@test.com", TenantID: "acme"}},
+		{InnerUser: db.InnerUser{ID: "user-2", Email: "yann@test.com", TenantID: "acme"}},
+		{InnerUser: db.InnerUser{ID: "user-1", Email: "xavier@test.com", TenantID: "acme"}},
+	}
+	mock.User.Expect(
+		client.User.FindMany(Scope(ctx, client).UserWhere(
+			db.User.Roles.Has("support"),
+			db.User.Not(db.User.DisabledAt.IsNull()),
+		)...).OrderBy(
+			db.User.Email.Order(db.SortOrderDesc),
+			db.User.ID.Order(db.SortOrderDesc),
+		).Take(3).Cursor(db.User.ID.Cursor("user-4")).Skip(1),
+	).ReturnsMany(rows)
+
+	s := &UserAdminServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	reply, err := s.ListUsers(ctx, &ListUsersRequest{
+		Role:       "support",
+		Status:     "disabled",
+		OrderBy:    "email",
+		Descending: true,
+		PageSize:   2,
+		PageToken:  "user-4",
+	})
+	require.NoError(t, err)
+	require.Len(t, reply.Users, 2)
+	assert.Equal(t, "user-3", reply.Users[0].Id)
+	assert.Equal(t, "user-2", reply.NextPageToken, "expected the last user of the page to continue from")
+}
+
+func TestListUsersValidation(t *testing.T) {
+	s := &UserAdminServiceServer{Logger: zap.NewNop().Sugar()}
+	ctx := adminContext(t)
+
+	_, err := s.ListUsers(ctx, &ListUsersRequest{OrderBy: "password"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.ListUsers(ctx, &ListUsersRequest{Status: "locked"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetUserOfAnotherTenant(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := adminContext(t)
+	mock.User.Expect(
+		client.User.FindFirst(Scope(ctx, client).UserWhere(db.User.ID.Equals("user-1"))...),
+	).Errors(db.ErrNotFound)
+
+	s := &UserAdminServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err := s.GetUser(ctx, &GetUserRequest{Id: "user-1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestDisableOwnAccount(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := adminContext(t)
+	mock.User.Expect(
+		client.User.FindFirst(Scope(ctx, client).UserWhere(db.User.ID.Equals("admin-1"))...),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "admin-1", TenantID: "acme"}})
+
+	s := &UserAdminServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err := s.DisableUser(ctx, &DisableUserRequest{Id: "admin-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLoginDisabledUser(t *testing.T) {
+	useLoginAttemptStore(t, NewMemoryLoginAttemptStore())
+	events := useAuditStore(t)
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	disabledAt := time.Now()
+	mock.User.Expect(
+		client.User.FindUnique(Scope(context.Background(), client).UserByEmail("disabled@test.com")),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{
+		ID:         "user-1",
+		Email:      "disabled@test.com",
+		Password:   string(hashed),
+		DisabledAt: &disabledAt,
+	}})
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err = s.Login(context.Background(), &LoginRequest{Email: "disabled@test.com", Password: "password"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	recorded := events()
+	require.Len(t, recorded, 1)
+	assert.Equal(t, audit.OutcomeFailure, recorded[0].Outcome)
+	assert.Equal(t, "account disabled", recorded[0].Reason)
+}
+
+func TestSetUserRoles(t *testing.T) {
+	useRevocationStore(t, NewMemoryRevocationStore())
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := adminContext(t)
+	user := db.UserModel{InnerUser: db.InnerUser{ID: "user-1", TenantID: "acme", Roles: []string{"admin"}}}
+	mock.User.Expect(
+		client.User.FindFirst(Scope(ctx, client).UserWhere(db.User.ID.Equals("user-1"))...),
+	).Returns(user)
+	user.Roles = []string{"billing", "support"}
+	mock.User.Expect(
+		client.User.FindUnique(db.User.ID.Equals("user-1")).Update(
+			db.User.Roles.Set([]string{"billing", "support"}),
+		),
+	).Returns(user)
+
+	s := &UserAdminServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	reply, err := s.SetUserRoles(ctx, &SetUserRolesRequest{Id: "user-1", Roles: []string{"support", "billing", "support"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing", "support"}, reply.Roles)
+
+	// Tokens with the old roles are no longer accepted.
+	old := NewClaims("user@test.com", WithSubject("user-1"), WithRoles("admin"))
+	old.IssuedAt.Time = old.IssuedAt.Add(-time.Second)
+	assert.Error(t, CheckRevocation(context.Background(), old))
+}
+
+func TestImpersonateUser(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecret")
+	events := useAuditStore(t)
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := adminContext(t)
+	mock.User.Expect(
+		client.User.FindFirst(Scope(ctx, client).UserWhere(db.User.ID.Equals("user-1"))...),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "user@test.com", TenantID: "acme", Roles: []string{"support"}}})
+
+	s := &UserAdminServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	reply, err := s.ImpersonateUser(ctx, &ImpersonateUserRequest{Id: "user-1", Reason: "ticket 42"})
+	require.NoError(t, err)
+
+	claims, err := VerifyJWT(reply.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "acme", claims.TenantID)
+	require.NotNil(t, claims.Actor, "expected the token to name the admin")
+	assert.Equal(t, "admin-1", claims.Actor.Subject)
+	assert.Equal(t, "admin-1", NewPrincipal(claims).ImpersonatorID)
+
+	recorded := events()
+	require.Len(t, recorded, 1)
+	assert.Equal(t, audit.ActionImpersonate, recorded[0].Action)
+	assert.Equal(t, "user-1", recorded[0].TargetID)
+	assert.Equal(t, "ticket 42", recorded[0].Reason)
+}
+
+func TestImpersonateUserRefused(t *testing.T) {
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+
+	ctx := adminContext(t)
+	mock.User.Expect(
+		client.User.FindFirst(Scope(ctx, client).UserWhere(db.User.ID.Equals("admin-2"))...),
+	).Returns(db.UserModel{InnerUser: db.InnerUser{ID: "admin-2", TenantID: "acme", Roles: []string{"admin"}}})
+
+	s := &UserAdminServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	_, err := s.ImpersonateUser(ctx, &ImpersonateUserRequest{Id: "admin-2", Reason: "ticket 42"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "expected admins not to be impersonated")
+
+	_, err = s.ImpersonateUser(ctx, &ImpersonateUserRequest{Id: "user-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected a reason to be required")
+
+	impersonating := WithPrincipal(ctx, NewPrincipal(NewClaims("user@test.com",
+		WithSubject("user-1"), WithRoles("admin"), WithActor("admin-1", "admin@test.com"))))
+	_, err = s.ImpersonateUser(impersonating, &ImpersonateUserRequest{Id: "user-2", Reason: "ticket 42"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "expected impersonation not to chain")
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"db"
+	"encoding/json"
+	"errors"
+	"fmt"
+	. "generated"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Purposes of the session tokens of the WebAuthn ceremonies.
+const (
+	PurposeWebAuthnRegistration = "webauthn-registration"
+	PurposeWebAuthnLogin        = "webauthn-login"
+)
+
+// webAuthnCeremonyTTL is the time a user has to answer the prompt of their authenticator.
+const webAuthnCeremonyTTL = 5 * time.Minute
+
+// errClonedAuthenticator is returned when the sign counter of a passkey went backwards.
+var errClonedAuthenticator = errors.New("authenticator may have been cloned")
+
+var (
+	webAuthnMu sync.RWMutex
+	webAuthnRP *webauthn.WebAuthn
+)
+
+// SetWebAuthn sets the relying party of the WebAuthn ceremonies; nil disables passkeys.
+func SetWebAuthn(w *webauthn.WebAuthn) {
+	webAuthnMu.Lock()
+	defer webAuthnMu.Unlock()
+	webAuthnRP = w
+}
+
+// currentWebAuthn returns the relying party, or an error when passkeys are disabled.
+func currentWebAuthn() (*webauthn.WebAuthn, error) {
+	webAuthnMu.RLock()
+	defer webAuthnMu.RUnlock()
+	if webAuthnRP == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "passkeys are not configured")
+	}
+	return webAuthnRP, nil
+}
+
+// NewWebAuthn creates a relying party for the domain rpID, accepting ceremonies from
+// the given origins. Passkeys must verify the user, so that they replace both the
+// password and the second factor.
+func NewWebAuthn(rpID, rpName string, origins []string) (*webauthn.WebAuthn, error) {
+	return webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpName,
+		RPOrigins:     origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementPreferred,
+			UserVerification: protocol.VerificationRequired,
+		},
+	})
+}
+
+// LoadWebAuthnFromEnv builds the relying party from WEBAUTHN_RP_ID, WEBAUTHN_RP_ORIGINS
+// (comma separated, default https://<rp id>) and WEBAUTHN_RP_NAME. Passkeys are disabled
+// when WEBAUTHN_RP_ID is unset.
+func LoadWebAuthnFromEnv() (*webauthn.WebAuthn, error) {
+	rpID := os.Getenv("WEBAUTHN_RP_ID")
+	if rpID == "" {
+		return nil, nil
+	}
+	origins := []string{"https://" + rpID}
+	if v := os.Getenv("WEBAUTHN_RP_ORIGINS"); v != "" {
+		origins = strings.Split(v, ",")
+	}
+	name := os.Getenv("WEBAUTHN_RP_NAME")
+	if name == "" {
+		name = "Thunder"
+	}
+	w, err := NewWebAuthn(rpID, name, origins)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebAuthn configuration: %v", err)
+	}
+	return w, nil
+}
+
+// webAuthnUser adapts a user and their passkeys to webauthn.User. The user handle is
+// the ID of the user.
+type webAuthnUser struct {
+	user     *db.UserModel
+	passkeys []db.WebAuthnCredentialModel
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte          { return []byte(u.user.ID) }
+func (u *webAuthnUser) WebAuthnName() string        { return u.user.Email }
+func (u *webAuthnUser) WebAuthnDisplayName() string { return u.user.Name }
+func (u *webAuthnUser) WebAuthnIcon() string        { return "" }
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, row := range u.passkeys {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(row.Transports))
+		for _, t := range row.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              row.CredentialID,
+			PublicKey:       row.PublicKey,
+			AttestationType: row.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: row.BackupEligible,
+				BackupState:    row.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    row.Aaguid,
+				SignCount: uint32(row.SignCount),
+			},
+		})
+	}
+	return credentials
+}
+
+// loadWebAuthnUser loads the passkeys of the user.
+func (s *AuthServiceServer) loadWebAuthnUser(ctx context.Context, user *db.UserModel) (*webAuthnUser, error) {
+	passkeys, err := s.PrismaClient.WebAuthnCredential.FindMany(
+		db.WebAuthnCredential.UserID.Equals(user.ID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &webAuthnUser{user: user, passkeys: passkeys}, nil
+}
+
+// webAuthnSessionToken signs the state of a ceremony. Its ID is the challenge, which
+// also makes it single-use; the subject is the user, empty for discoverable logins.
+func webAuthnSessionToken(session *webauthn.SessionData, purpose, email, tenantID string) (string, error) {
+	claims := NewClaims(email,
+		WithSubject(string(session.UserID)),
+		WithTenant(tenantID),
+		WithPurpose(purpose),
+		WithTTL(webAuthnCeremonyTTL),
+	)
+	claims.ID = session.Challenge
+	return signClaims(claims)
+}
+
+// webAuthnSession verifies the session token of a ceremony within the tenant of the
+// request, consumes it and returns the session it was issued for.
+func webAuthnSession(ctx context.Context, w *webauthn.WebAuthn, token, purpose string) (*Claims, webauthn.SessionData, error) {
+	claims, err := verifyPurposeJWT(token, purpose)
+	if err == nil {
+		err = CheckRevocation(ctx, claims)
+	}
+	if err == nil && claims.TenantID != TenantID(ctx) {
+		err = ErrTenantMismatch
+	}
+	if err != nil {
+		return nil, webauthn.SessionData{}, err
+	}
+	if err := Revocations().RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, webauthn.SessionData{}, err
+	}
+
+	session := webauthn.SessionData{
+		Challenge:        claims.ID,
+		Expires:          claims.ExpiresAt.Time,
+		UserVerification: w.Config.AuthenticatorSelection.UserVerification,
+	}
+	if claims.Subject != "" {
+		session.UserID = []byte(claims.Subject)
+	}
+	return claims, session, nil
+}
+
+// ceremonyOptions encodes the options of a ceremony for the browser.
+func ceremonyOptions(options any) (string, error) {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// requirePasskeyRegistrant refuses callers that may not add a passkey to the account.
+// Passkey logins get every role of the user and skip two-factor authentication, so
+// neither a scoped API key nor an admin acting as the user may leave one behind.
+func requirePasskeyRegistrant(ctx context.Context) error {
+	if err := requireInteractivePrincipal(ctx); err != nil {
+		return err
+	}
+	if MustFromContext(ctx).ImpersonatorID != "" {
+		return status.Errorf(codes.PermissionDenied, "cannot register passkeys while impersonating")
+	}
+	return nil
+}
+
+// BeginWebAuthnRegistration starts adding a passkey to the account of the caller.
+func (s *AuthServiceServer) BeginWebAuthnRegistration(ctx context.Context, in *BeginWebAuthnRegistrationRequest) (*BeginWebAuthnReply, error) {
+	w, err := currentWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+	if err := requirePasskeyRegistrant(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wuser, err := s.loadWebAuthnUser(ctx, user)
+	if err != nil {
+		s.Logger.Errorw("Failed to load passkeys", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not start passkey registration")
+	}
+
+	// Authenticators refuse to register a second passkey for the same account.
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(wuser.passkeys))
+	for _, c := range wuser.WebAuthnCredentials() {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+	creation, session, err := w.BeginRegistration(wuser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		s.Logger.Errorw("Failed to start passkey registration", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not start passkey registration")
+	}
+	return s.ceremonyReply(creation, session, PurposeWebAuthnRegistration, user.Email, user.TenantID)
+}
+
+// FinishWebAuthnRegistration verifies the new passkey of the caller and stores it.
+func (s *AuthServiceServer) FinishWebAuthnRegistration(ctx context.Context, in *FinishWebAuthnRegistrationRequest) (*FinishWebAuthnRegistrationReply, error) {
+	w, err := currentWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+	if err := requirePasskeyRegistrant(ctx); err != nil {
+		return nil, err
+	}
+	user, err := s.currentUserModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, session, err := webAuthnSession(ctx, w, in.SessionToken, PurposeWebAuthnRegistration)
+	if err != nil || claims.Subject != user.ID {
+		s.Logger.Warnw("Passkey registration failed: invalid session", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired session")
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBody(strings.NewReader(in.Credential))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credential")
+	}
+	wuser, err := s.loadWebAuthnUser(ctx, user)
+	if err != nil {
+		s.Logger.Errorw("Failed to load passkeys", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not register passkey")
+	}
+	credential, err := w.CreateCredential(wuser, session, parsed)
+	if err != nil {
+		s.Logger.Warnw("Passkey registration failed: invalid attestation", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credential")
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+	if _, err := s.PrismaClient.WebAuthnCredential.CreateOne(
+		db.WebAuthnCredential.User.Link(db.User.ID.Equals(user.ID)),
+		db.WebAuthnCredential.CredentialID.Set(credential.ID),
+		db.WebAuthnCredential.PublicKey.Set(credential.PublicKey),
+		db.WebAuthnCredential.AttestationType.Set(credential.AttestationType),
+		db.WebAuthnCredential.Aaguid.Set(credential.Authenticator.AAGUID),
+		db.WebAuthnCredential.Name.Set(in.Name),
+		db.WebAuthnCredential.SignCount.Set(db.BigInt(credential.Authenticator.SignCount)),
+		db.WebAuthnCredential.Transports.Set(transports),
+		db.WebAuthnCredential.BackupEligible.Set(credential.Flags.BackupEligible),
+		db.WebAuthnCredential.BackupState.Set(credential.Flags.BackupState),
+	).Exec(ctx); err != nil {
+		s.Logger.Errorw("Failed to store passkey", "user", user.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not register passkey")
+	}
+
+	s.Logger.Infow("Passkey registered", "user", user.ID)
+	return &FinishWebAuthnRegistrationReply{
+		CredentialId: protocol.URLEncodedBase64(credential.ID).String(),
+	}, nil
+}
+
+// BeginWebAuthnLogin starts a passkey login. Without an email, or for an unknown one,
+// the authenticator offers the passkeys it holds for the site, so the reply does not
+// reveal whether an account exists.
+func (s *AuthServiceServer) BeginWebAuthnLogin(ctx context.Context, in *BeginWebAuthnLoginRequest) (*BeginWebAuthnReply, error) {
+	w, err := currentWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Email != "" {
+		user, err := Scope(ctx, s.PrismaClient).FindUserByEmail(ctx, in.Email)
+		if err != nil && !errors.Is(err, db.ErrNotFound) {
+			s.Logger.Errorw("Failed to look up user for passkey login", "error", err)
+			return nil, status.Errorf(codes.Internal, "could not start passkey login")
+		}
+		if err == nil {
+			wuser, err := s.loadWebAuthnUser(ctx, user)
+			if err != nil {
+				s.Logger.Errorw("Failed to load passkeys", "user", user.ID, "error", err)
+				return nil, status.Errorf(codes.Internal, "could not start passkey login")
+			}
+			if len(wuser.passkeys) > 0 {
+				assertion, session, err := w.BeginLogin(wuser)
+				if err != nil {
+					s.Logger.Errorw("Failed to start passkey login", "user", user.ID, "error", err)
+					return nil, status.Errorf(codes.Internal, "could not start passkey login")
+				}
+				return s.ceremonyReply(assertion, session, PurposeWebAuthnLogin, user.Email, user.TenantID)
+			}
+		}
+	}
+
+	assertion, session, err := w.BeginDiscoverableLogin()
+	if err != nil {
+		s.Logger.Errorw("Failed to start passkey login", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not start passkey login")
+	}
+	return s.ceremonyReply(assertion, session, PurposeWebAuthnLogin, "", TenantID(ctx))
+}
+
+// ceremonyReply returns the options of a ceremony with its session token.
+func (s *AuthServiceServer) ceremonyReply(options any, session *webauthn.SessionData, purpose, email, tenantID string) (*BeginWebAuthnReply, error) {
+	encoded, err := ceremonyOptions(options)
+	if err != nil {
+		s.Logger.Errorw("Failed to encode WebAuthn options", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not start passkey ceremony")
+	}
+	token, err := webAuthnSessionToken(session, purpose, email, tenantID)
+	if err != nil {
+		s.Logger.Errorw("Failed to sign WebAuthn session", "error", err)
+		return nil, status.Errorf(codes.Internal, "could not start passkey ceremony")
+	}
+	return &BeginWebAuthnReply{Options: encoded, SessionToken: token}, nil
+}
+
+// validateWebAuthnLogin checks an assertion against the passkeys of the user the session
+// was started for, or of the user named by the user handle of a discoverable login.
+func (s *AuthServiceServer) validateWebAuthnLogin(ctx context.Context, w *webauthn.WebAuthn, session webauthn.SessionData, parsed *protocol.ParsedCredentialAssertionData) (*webAuthnUser, *webauthn.Credential, error) {
+	scope := Scope(ctx, s.PrismaClient)
+	var wuser *webAuthnUser
+	lookup := func(userID string) (*webAuthnUser, error) {
+		user, err := scope.FindUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		wuser, err = s.loadWebAuthnUser(ctx, user)
+		return wuser, err
+	}
+
+	var credential *webauthn.Credential
+	var err error
+	if session.UserID != nil {
+		if _, err := lookup(string(session.UserID)); err != nil {
+			return nil, nil, err
+		}
+		credential, err = w.ValidateLogin(wuser, session, parsed)
+	} else {
+		credential, err = w.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+			return lookup(string(userHandle))
+		}, session, parsed)
+	}
+	if err != nil {
+		return wuser, nil, err
+	}
+	if credential.Authenticator.CloneWarning {
+		return wuser, nil, errClonedAuthenticator
+	}
+	return wuser, credential, nil
+}
+
+// FinishWebAuthnLogin verifies a passkey assertion and returns the same tokens as Login.
+// Passkeys verify the user, so no second factor is asked for.
+func (s *AuthServiceServer) FinishWebAuthnLogin(ctx context.Context, in *FinishWebAuthnLoginRequest) (*LoginReply, error) {
+	w, err := currentWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+	_, session, err := webAuthnSession(ctx, w, in.SessionToken, PurposeWebAuthnLogin)
+	if err != nil {
+		s.Logger.Warnw("Passkey login failed: invalid session", "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid or expired session")
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBody(strings.NewReader(in.Credential))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credential")
+	}
+
+	wuser, credential, err := s.validateWebAuthnLogin(ctx, w, session, parsed)
+	if err != nil {
+		s.Logger.Warnw("Passkey login failed", "error", err)
+		if wuser != nil {
+			auditLoginFailure(ctx, wuser.user.ID, wuser.user.Email, "invalid passkey")
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "invalid passkey")
+	}
+	user := wuser.user
+
+	for _, row := range wuser.passkeys {
+		if !bytes.Equal(row.CredentialID, credential.ID) {
+			continue
+		}
+		if _, err := s.PrismaClient.WebAuthnCredential.FindUnique(
+			db.WebAuthnCredential.ID.Equals(row.ID),
+		).Update(
+			db.WebAuthnCredential.SignCount.Set(db.BigInt(credential.Authenticator.SignCount)),
+			db.WebAuthnCredential.BackupState.Set(credential.Flags.BackupState),
+			db.WebAuthnCredential.LastUsedAt.Set(time.Now()),
+		).Exec(ctx); err != nil {
+			s.Logger.Errorw("Failed to update passkey", "user", user.ID, "error", err)
+			return nil, status.Errorf(codes.Internal, "could not log in")
+		}
+	}
+
+	if err := s.refuseDisabled(ctx, user); err != nil {
+		return nil, err
+	}
+	reply, _, err := s.issueTokens(ctx, user, "")
+	if err != nil {
+		s.Logger.Errorw("Error generating token", "email", user.Email, "error", err)
+		return nil, status.Errorf(codes.Internal, "could not generate token: %v", err)
+	}
+	s.Logger.Infow("Generated token after passkey login", "email", user.Email)
+	auditLoginSuccess(ctx, user)
+	return reply, nil
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"db"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	. "generated"
+	"strings"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testRPID   = "thunder.test"
+	testOrigin = "https://thunder.test"
+)
+
+// softAuthenticator is a WebAuthn authenticator in software. It holds one P-256 passkey
+// and answers ceremony options the way navigator.credentials does in a browser.
+type softAuthenticator struct {
+	t            *testing.T
+	origin       string
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+	return &softAuthenticator{t: t, origin: testOrigin, key: key, credentialID: id}
+}
+
+// publicKey returns the COSE encoding of the passkey.
+func (a *softAuthenticator) publicKey() []byte {
+	data, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(a.t, err)
+	return data
+}
+
+// authData builds authenticator data with the user present and verified flags.
+func (a *softAuthenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	flags := byte(protocol.FlagUserPresent | protocol.FlagUserVerified)
+	if attested {
+		flags |= byte(protocol.FlagAttestedCredentialData)
+	}
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.publicKey()...)
+	}
+	return data
+}
+
+func (a *softAuthenticator) clientData(ceremony, challenge string) []byte {
+	data, err := json.Marshal(map[string]string{"type": ceremony, "challenge": challenge, "origin": a.origin})
+	require.NoError(a.t, err)
+	return data
+}
+
+// create answers the options of BeginWebAuthnRegistration with a "none" attestation.
+func (a *softAuthenticator) create(options string) string {
+	var creation protocol.CredentialCreation
+	require.NoError(a.t, json.Unmarshal([]byte(options), &creation))
+	handle, err := base64.RawURLEncoding.DecodeString(creation.Response.User.ID.(string))
+	require.NoError(a.t, err)
+	a.userHandle = handle
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(true),
+	})
+	require.NoError(a.t, err)
+	return a.credentialJSON(map[string]any{
+		"clientDataJSON":    protocol.URLEncodedBase64(a.clientData("webauthn.create", creation.Response.Challenge.String())),
+		"attestationObject": protocol.URLEncodedBase64(attestation),
+	})
+}
+
+// get answers the options of BeginWebAuthnLogin with a signed assertion.
+func (a *softAuthenticator) get(options string) string {
+	var assertion protocol.CredentialAssertion
+	require.NoError(a.t, json.Unmarshal([]byte(options), &assertion))
+	a.signCount++
+
+	authData := a.authData(false)
+	clientData := a.clientData("webauthn.get", assertion.Response.Challenge.String())
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(a.t, err)
+	return a.credentialJSON(map[string]any{
+		"clientDataJSON":    protocol.URLEncodedBase64(clientData),
+		"authenticatorData": protocol.URLEncodedBase64(authData),
+		"signature":         protocol.URLEncodedBase64(signature),
+		"userHandle":        protocol.URLEncodedBase64(a.userHandle),
+	})
+}
+
+func (a *softAuthenticator) credentialJSON(response map[string]any) string {
+	data, err := json.Marshal(map[string]any{
+		"id":       protocol.URLEncodedBase64(a.credentialID).String(),
+		"rawId":    protocol.URLEncodedBase64(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	require.NoError(a.t, err)
+	return string(data)
+}
+
+// usePasskeys configures the relying party for the duration of the test.
+func usePasskeys(t *testing.T) *webauthn.WebAuthn {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "testsecret")
+	useRevocationStore(t, NewMemoryRevocationStore())
+	w, err := NewWebAuthn(testRPID, "Thunder", []string{testOrigin})
+	require.NoError(t, err)
+	SetWebAuthn(w)
+	t.Cleanup(func() { SetWebAuthn(nil) })
+	return w
+}
+
+func TestWebAuthnRegistrationAndLogin(t *testing.T) {
+	w := usePasskeys(t)
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+	authenticator := newSoftAuthenticator(t)
+
+	user := db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "account@test.com", Name: "Ada"}}
+	findUser := client.User.FindFirst(Scope(context.Background(), client).UserWhere(db.User.ID.Equals("user-1"))...)
+	findPasskeys := client.WebAuthnCredential.FindMany(db.WebAuthnCredential.UserID.Equals("user-1"))
+	stored := db.WebAuthnCredentialModel{InnerWebAuthnCredential: db.InnerWebAuthnCredential{
+		ID:              "passkey-1",
+		UserID:          "user-1",
+		Name:            "Laptop",
+		CredentialID:    authenticator.credentialID,
+		PublicKey:       authenticator.publicKey(),
+		AttestationType: "none",
+		Aaguid:          make([]byte, 16),
+		Transports:      []string{},
+	}}
+
+	// Registration: begin and finish each load the caller and their passkeys.
+	mock.User.Expect(findUser).Returns(user)
+	mock.WebAuthnCredential.Expect(findPasskeys).ReturnsMany(nil)
+	mock.WebAuthnCredential.Expect(
+		client.WebAuthnCredential.CreateOne(
+			db.WebAuthnCredential.User.Link(db.User.ID.Equals("user-1")),
+			db.WebAuthnCredential.CredentialID.Set(stored.CredentialID),
+			db.WebAuthnCredential.PublicKey.Set(stored.PublicKey),
+			db.WebAuthnCredential.AttestationType.Set("none"),
+			db.WebAuthnCredential.Aaguid.Set(stored.Aaguid),
+			db.WebAuthnCredential.Name.Set("Laptop"),
+			db.WebAuthnCredential.SignCount.Set(0),
+			db.WebAuthnCredential.Transports.Set([]string{}),
+			db.WebAuthnCredential.BackupEligible.Set(false),
+			db.WebAuthnCredential.BackupState.Set(false),
+		),
+	).Returns(stored)
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	ctx := accountContext(t)
+	begin, err := s.BeginWebAuthnRegistration(ctx, &BeginWebAuthnRegistrationRequest{})
+	require.NoError(t, err)
+	registered, err := s.FinishWebAuthnRegistration(ctx, &FinishWebAuthnRegistrationRequest{
+		SessionToken: begin.SessionToken,
+		Credential:   authenticator.create(begin.Options),
+		Name:         "Laptop",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, protocol.URLEncodedBase64(authenticator.credentialID).String(), registered.CredentialId)
+
+	_, err = s.FinishWebAuthnRegistration(ctx, &FinishWebAuthnRegistrationRequest{
+		SessionToken: begin.SessionToken,
+		Credential:   authenticator.create(begin.Options),
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected the session to be single-use")
+
+	// Login: the options name the passkey of the user, and the assertion verifies. The
+	// mock answers a query with its first expectation, so the stored passkey needs a
+	// client of its own.
+	client, mock, ensure = db.NewMock()
+	defer ensure(t)
+	mock.User.Expect(
+		client.User.FindUnique(Scope(context.Background(), client).UserByEmail("account@test.com")),
+	).Returns(user)
+	mock.User.Expect(findUser).Returns(user)
+	mock.WebAuthnCredential.Expect(findPasskeys).ReturnsMany([]db.WebAuthnCredentialModel{stored})
+
+	s = &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	login, err := s.BeginWebAuthnLogin(context.Background(), &BeginWebAuthnLoginRequest{Email: "account@test.com"})
+	require.NoError(t, err)
+	assert.Contains(t, login.Options, registered.CredentialId, "expected the passkey to be allowed")
+
+	_, session, err := webAuthnSession(context.Background(), w, login.SessionToken, PurposeWebAuthnLogin)
+	require.NoError(t, err)
+	parsed, err := protocol.ParseCredentialRequestResponseBody(strings.NewReader(authenticator.get(login.Options)))
+	require.NoError(t, err)
+	wuser, credential, err := s.validateWebAuthnLogin(context.Background(), w, session, parsed)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", wuser.user.ID)
+	assert.Equal(t, uint32(1), credential.Authenticator.SignCount)
+}
+
+func TestWebAuthnDiscoverableLoginRejectsClone(t *testing.T) {
+	w := usePasskeys(t)
+	client, mock, ensure := db.NewMock()
+	defer ensure(t)
+	authenticator := newSoftAuthenticator(t)
+	authenticator.userHandle = []byte("user-1")
+	authenticator.signCount = 4
+
+	user := db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "account@test.com"}}
+	stored := db.WebAuthnCredentialModel{InnerWebAuthnCredential: db.InnerWebAuthnCredential{
+		ID:           "passkey-1",
+		UserID:       "user-1",
+		CredentialID: authenticator.credentialID,
+		PublicKey:    authenticator.publicKey(),
+		Aaguid:       make([]byte, 16),
+		SignCount:    7,
+	}}
+	mock.User.Expect(
+		client.User.FindFirst(Scope(context.Background(), client).UserWhere(db.User.ID.Equals("user-1"))...),
+	).Returns(user)
+	mock.WebAuthnCredential.Expect(
+		client.WebAuthnCredential.FindMany(db.WebAuthnCredential.UserID.Equals("user-1")),
+	).ReturnsMany([]db.WebAuthnCredentialModel{stored})
+
+	s := &AuthServiceServer{PrismaClient: client, Logger: zap.NewNop().Sugar()}
+	login, err := s.BeginWebAuthnLogin(context.Background(), &BeginWebAuthnLoginRequest{})
+	require.NoError(t, err)
+
+	_, session, err := webAuthnSession(context.Background(), w, login.SessionToken, PurposeWebAuthnLogin)
+	require.NoError(t, err)
+	assert.Nil(t, session.UserID, "expected a discoverable login")
+	parsed, err := protocol.ParseCredentialRequestResponseBody(strings.NewReader(authenticator.get(login.Options)))
+	require.NoError(t, err)
+	_, _, err = s.validateWebAuthnLogin(context.Background(), w, session, parsed)
+	assert.ErrorIs(t, err, errClonedAuthenticator, "expected a counter below the stored one to be refused")
+}
+
+func TestWebAuthnWrongOrigin(t *testing.T) {
+	w := usePasskeys(t)
+	authenticator := newSoftAuthenticator(t)
+	authenticator.origin = "https://phishing.test"
+
+	user := &webAuthnUser{user: &db.UserModel{InnerUser: db.InnerUser{ID: "user-1", Email: "account@test.com"}}}
+	creation, session, err := w.BeginRegistration(user)
+	require.NoError(t, err)
+	options, err := ceremonyOptions(creation)
+	require.NoError(t, err)
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(strings.NewReader(authenticator.create(options)))
+	require.NoError(t, err)
+	_, err = w.CreateCredential(user, *session, parsed)
+	assert.Error(t, err, "expected a ceremony from another origin to be rejected")
+}
+
+func TestWebAuthnNotConfigured(t *testing.T) {
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	_, err := s.BeginWebAuthnLogin(context.Background(), &BeginWebAuthnLoginRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestWebAuthnRegistrationRefusesDelegatedCallers(t *testing.T) {
+	usePasskeys(t)
+	s := &AuthServiceServer{Logger: zap.NewNop().Sugar()}
+	callers := map[string]*Principal{
+		"API key":       NewPrincipal(&Claims{Email: "account@test.com", APIKeyID: "key-1"}),
+		"impersonation": NewPrincipal(NewClaims("account@test.com", WithSubject("user-1"), WithActor("admin-1", "admin@test.com"))),
+	}
+	for name, p := range callers {
+		ctx := WithPrincipal(context.Background(), p)
+		_, err := s.BeginWebAuthnRegistration(ctx, &BeginWebAuthnRegistrationRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err), "expected %s callers not to start passkey registration", name)
+		_, err = s.FinishWebAuthnRegistration(ctx, &FinishWebAuthnRegistrationRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err), "expected %s callers not to finish passkey registration", name)
+	}
+}